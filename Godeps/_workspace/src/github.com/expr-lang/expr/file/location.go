@@ -0,0 +1,6 @@
+package file
+
+type Location struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
@@ -0,0 +1,427 @@
+//go:build !purego
+
+// Auto-generated by gen_int64_masks.go - DO NOT EDIT
+
+#include "textflag.h"
+
+// Pre-computed permutation and shift tables for int64 unpacking
+// Each entry is 128 bytes: [permute_0_3][permute_4_7][shifts_0_3][shifts_4_7]
+// Format: 4 YMM registers worth of data per bitWidth
+
+GLOBL ·permuteInt64Table(SB), RODATA|NOPTR, $2944
+
+// BitWidth 9 (offset 0)
+DATA ·permuteInt64Table+0(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+8(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+16(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+24(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+32(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+40(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+48(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+56(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+64(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+72(SB)/8, $0x0000000000000009
+DATA ·permuteInt64Table+80(SB)/8, $0x0000000000000012
+DATA ·permuteInt64Table+88(SB)/8, $0x000000000000001b
+DATA ·permuteInt64Table+96(SB)/8, $0x0000000000000004
+DATA ·permuteInt64Table+104(SB)/8, $0x000000000000000d
+DATA ·permuteInt64Table+112(SB)/8, $0x0000000000000016
+DATA ·permuteInt64Table+120(SB)/8, $0x000000000000001f
+
+// BitWidth 10 (offset 128)
+DATA ·permuteInt64Table+128(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+136(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+144(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+152(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+160(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+168(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+176(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+184(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+192(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+200(SB)/8, $0x000000000000000a
+DATA ·permuteInt64Table+208(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+216(SB)/8, $0x000000000000001e
+DATA ·permuteInt64Table+224(SB)/8, $0x0000000000000008
+DATA ·permuteInt64Table+232(SB)/8, $0x0000000000000012
+DATA ·permuteInt64Table+240(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+248(SB)/8, $0x0000000000000006
+
+// BitWidth 11 (offset 256)
+DATA ·permuteInt64Table+256(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+264(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+272(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+280(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+288(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+296(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+304(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+312(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+320(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+328(SB)/8, $0x000000000000000b
+DATA ·permuteInt64Table+336(SB)/8, $0x0000000000000016
+DATA ·permuteInt64Table+344(SB)/8, $0x0000000000000001
+DATA ·permuteInt64Table+352(SB)/8, $0x000000000000000c
+DATA ·permuteInt64Table+360(SB)/8, $0x0000000000000017
+DATA ·permuteInt64Table+368(SB)/8, $0x0000000000000002
+DATA ·permuteInt64Table+376(SB)/8, $0x000000000000000d
+
+// BitWidth 12 (offset 384)
+DATA ·permuteInt64Table+384(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+392(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+400(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+408(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+416(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+424(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+432(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+440(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+448(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+456(SB)/8, $0x000000000000000c
+DATA ·permuteInt64Table+464(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+472(SB)/8, $0x0000000000000004
+DATA ·permuteInt64Table+480(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+488(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+496(SB)/8, $0x0000000000000008
+DATA ·permuteInt64Table+504(SB)/8, $0x0000000000000014
+
+// BitWidth 13 (offset 512)
+DATA ·permuteInt64Table+512(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+520(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+528(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+536(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+544(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+552(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+560(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+568(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+576(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+584(SB)/8, $0x000000000000000d
+DATA ·permuteInt64Table+592(SB)/8, $0x000000000000001a
+DATA ·permuteInt64Table+600(SB)/8, $0x0000000000000007
+DATA ·permuteInt64Table+608(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+616(SB)/8, $0x0000000000000001
+DATA ·permuteInt64Table+624(SB)/8, $0x000000000000000e
+DATA ·permuteInt64Table+632(SB)/8, $0x000000000000001b
+
+// BitWidth 14 (offset 640)
+DATA ·permuteInt64Table+640(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+648(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+656(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+664(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+672(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+680(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+688(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+696(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+704(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+712(SB)/8, $0x000000000000000e
+DATA ·permuteInt64Table+720(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+728(SB)/8, $0x000000000000000a
+DATA ·permuteInt64Table+736(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+744(SB)/8, $0x0000000000000006
+DATA ·permuteInt64Table+752(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+760(SB)/8, $0x0000000000000002
+
+// BitWidth 15 (offset 768)
+DATA ·permuteInt64Table+768(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+776(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+784(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+792(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+800(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+808(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+816(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+824(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+832(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+840(SB)/8, $0x000000000000000f
+DATA ·permuteInt64Table+848(SB)/8, $0x000000000000001e
+DATA ·permuteInt64Table+856(SB)/8, $0x000000000000000d
+DATA ·permuteInt64Table+864(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+872(SB)/8, $0x000000000000000b
+DATA ·permuteInt64Table+880(SB)/8, $0x000000000000001a
+DATA ·permuteInt64Table+888(SB)/8, $0x0000000000000009
+
+// BitWidth 16 (offset 896)
+DATA ·permuteInt64Table+896(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+904(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+912(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+920(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+928(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+936(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+944(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+952(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+960(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+968(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+976(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+984(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+992(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1000(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+1008(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1016(SB)/8, $0x0000000000000010
+
+// BitWidth 17 (offset 1024)
+DATA ·permuteInt64Table+1024(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1032(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1040(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1048(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1056(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1064(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1072(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1080(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1088(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1096(SB)/8, $0x0000000000000011
+DATA ·permuteInt64Table+1104(SB)/8, $0x0000000000000002
+DATA ·permuteInt64Table+1112(SB)/8, $0x0000000000000013
+DATA ·permuteInt64Table+1120(SB)/8, $0x0000000000000004
+DATA ·permuteInt64Table+1128(SB)/8, $0x0000000000000015
+DATA ·permuteInt64Table+1136(SB)/8, $0x0000000000000006
+DATA ·permuteInt64Table+1144(SB)/8, $0x0000000000000017
+
+// BitWidth 18 (offset 1152)
+DATA ·permuteInt64Table+1152(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1160(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1168(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1176(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1184(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1192(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1200(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1208(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1216(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1224(SB)/8, $0x0000000000000012
+DATA ·permuteInt64Table+1232(SB)/8, $0x0000000000000004
+DATA ·permuteInt64Table+1240(SB)/8, $0x0000000000000016
+DATA ·permuteInt64Table+1248(SB)/8, $0x0000000000000008
+DATA ·permuteInt64Table+1256(SB)/8, $0x000000000000001a
+DATA ·permuteInt64Table+1264(SB)/8, $0x000000000000000c
+DATA ·permuteInt64Table+1272(SB)/8, $0x000000000000001e
+
+// BitWidth 19 (offset 1280)
+DATA ·permuteInt64Table+1280(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1288(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1296(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1304(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1312(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1320(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1328(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1336(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+1344(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1352(SB)/8, $0x0000000000000013
+DATA ·permuteInt64Table+1360(SB)/8, $0x0000000000000006
+DATA ·permuteInt64Table+1368(SB)/8, $0x0000000000000019
+DATA ·permuteInt64Table+1376(SB)/8, $0x000000000000000c
+DATA ·permuteInt64Table+1384(SB)/8, $0x000000000000001f
+DATA ·permuteInt64Table+1392(SB)/8, $0x0000000000000012
+DATA ·permuteInt64Table+1400(SB)/8, $0x0000000000000005
+
+// BitWidth 20 (offset 1408)
+DATA ·permuteInt64Table+1408(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1416(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1424(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1432(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1440(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1448(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1456(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1464(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+1472(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1480(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+1488(SB)/8, $0x0000000000000008
+DATA ·permuteInt64Table+1496(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+1504(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+1512(SB)/8, $0x0000000000000004
+DATA ·permuteInt64Table+1520(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+1528(SB)/8, $0x000000000000000c
+
+// BitWidth 21 (offset 1536)
+DATA ·permuteInt64Table+1536(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1544(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1552(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1560(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1568(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1576(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1584(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1592(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+1600(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1608(SB)/8, $0x0000000000000015
+DATA ·permuteInt64Table+1616(SB)/8, $0x000000000000000a
+DATA ·permuteInt64Table+1624(SB)/8, $0x000000000000001f
+DATA ·permuteInt64Table+1632(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+1640(SB)/8, $0x0000000000000009
+DATA ·permuteInt64Table+1648(SB)/8, $0x000000000000001e
+DATA ·permuteInt64Table+1656(SB)/8, $0x0000000000000013
+
+// BitWidth 22 (offset 1664)
+DATA ·permuteInt64Table+1664(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1672(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1680(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1688(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1696(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1704(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1712(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+1720(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+1728(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1736(SB)/8, $0x0000000000000016
+DATA ·permuteInt64Table+1744(SB)/8, $0x000000000000000c
+DATA ·permuteInt64Table+1752(SB)/8, $0x0000000000000002
+DATA ·permuteInt64Table+1760(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+1768(SB)/8, $0x000000000000000e
+DATA ·permuteInt64Table+1776(SB)/8, $0x0000000000000004
+DATA ·permuteInt64Table+1784(SB)/8, $0x000000000000001a
+
+// BitWidth 23 (offset 1792)
+DATA ·permuteInt64Table+1792(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1800(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1808(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1816(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1824(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1832(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1840(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+1848(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+1856(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1864(SB)/8, $0x0000000000000017
+DATA ·permuteInt64Table+1872(SB)/8, $0x000000000000000e
+DATA ·permuteInt64Table+1880(SB)/8, $0x0000000000000005
+DATA ·permuteInt64Table+1888(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+1896(SB)/8, $0x0000000000000013
+DATA ·permuteInt64Table+1904(SB)/8, $0x000000000000000a
+DATA ·permuteInt64Table+1912(SB)/8, $0x0000000000000001
+
+// BitWidth 24 (offset 1920)
+DATA ·permuteInt64Table+1920(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1928(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+1936(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+1944(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+1952(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1960(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+1968(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+1976(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+1984(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+1992(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+2000(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+2008(SB)/8, $0x0000000000000008
+DATA ·permuteInt64Table+2016(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2024(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+2032(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+2040(SB)/8, $0x0000000000000008
+
+// BitWidth 25 (offset 2048)
+DATA ·permuteInt64Table+2048(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2056(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2064(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+2072(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+2080(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2088(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2096(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2104(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2112(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2120(SB)/8, $0x0000000000000019
+DATA ·permuteInt64Table+2128(SB)/8, $0x0000000000000012
+DATA ·permuteInt64Table+2136(SB)/8, $0x000000000000000b
+DATA ·permuteInt64Table+2144(SB)/8, $0x0000000000000004
+DATA ·permuteInt64Table+2152(SB)/8, $0x000000000000001d
+DATA ·permuteInt64Table+2160(SB)/8, $0x0000000000000016
+DATA ·permuteInt64Table+2168(SB)/8, $0x000000000000000f
+
+// BitWidth 26 (offset 2176)
+DATA ·permuteInt64Table+2176(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2184(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2192(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+2200(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+2208(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2216(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2224(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2232(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2240(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2248(SB)/8, $0x000000000000001a
+DATA ·permuteInt64Table+2256(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+2264(SB)/8, $0x000000000000000e
+DATA ·permuteInt64Table+2272(SB)/8, $0x0000000000000008
+DATA ·permuteInt64Table+2280(SB)/8, $0x0000000000000002
+DATA ·permuteInt64Table+2288(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+2296(SB)/8, $0x0000000000000016
+
+// BitWidth 27 (offset 2304)
+DATA ·permuteInt64Table+2304(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2312(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2320(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+2328(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+2336(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2344(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2352(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2360(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2368(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2376(SB)/8, $0x000000000000001b
+DATA ·permuteInt64Table+2384(SB)/8, $0x0000000000000016
+DATA ·permuteInt64Table+2392(SB)/8, $0x0000000000000011
+DATA ·permuteInt64Table+2400(SB)/8, $0x000000000000000c
+DATA ·permuteInt64Table+2408(SB)/8, $0x0000000000000007
+DATA ·permuteInt64Table+2416(SB)/8, $0x0000000000000002
+DATA ·permuteInt64Table+2424(SB)/8, $0x000000000000001d
+
+// BitWidth 28 (offset 2432)
+DATA ·permuteInt64Table+2432(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2440(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2448(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+2456(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+2464(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2472(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2480(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2488(SB)/8, $0x0000000700000006
+DATA ·permuteInt64Table+2496(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2504(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+2512(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+2520(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+2528(SB)/8, $0x0000000000000010
+DATA ·permuteInt64Table+2536(SB)/8, $0x000000000000000c
+DATA ·permuteInt64Table+2544(SB)/8, $0x0000000000000008
+DATA ·permuteInt64Table+2552(SB)/8, $0x0000000000000004
+
+// BitWidth 29 (offset 2560)
+DATA ·permuteInt64Table+2560(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2568(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2576(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+2584(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+2592(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2600(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2608(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2616(SB)/8, $0x0000000700000006
+DATA ·permuteInt64Table+2624(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2632(SB)/8, $0x000000000000001d
+DATA ·permuteInt64Table+2640(SB)/8, $0x000000000000001a
+DATA ·permuteInt64Table+2648(SB)/8, $0x0000000000000017
+DATA ·permuteInt64Table+2656(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+2664(SB)/8, $0x0000000000000011
+DATA ·permuteInt64Table+2672(SB)/8, $0x000000000000000e
+DATA ·permuteInt64Table+2680(SB)/8, $0x000000000000000b
+
+// BitWidth 30 (offset 2688)
+DATA ·permuteInt64Table+2688(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2696(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2704(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+2712(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+2720(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2728(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2736(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2744(SB)/8, $0x0000000700000006
+DATA ·permuteInt64Table+2752(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2760(SB)/8, $0x000000000000001e
+DATA ·permuteInt64Table+2768(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+2776(SB)/8, $0x000000000000001a
+DATA ·permuteInt64Table+2784(SB)/8, $0x0000000000000018
+DATA ·permuteInt64Table+2792(SB)/8, $0x0000000000000016
+DATA ·permuteInt64Table+2800(SB)/8, $0x0000000000000014
+DATA ·permuteInt64Table+2808(SB)/8, $0x0000000000000012
+
+// BitWidth 31 (offset 2816)
+DATA ·permuteInt64Table+2816(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2824(SB)/8, $0x0000000100000000
+DATA ·permuteInt64Table+2832(SB)/8, $0x0000000200000001
+DATA ·permuteInt64Table+2840(SB)/8, $0x0000000300000002
+DATA ·permuteInt64Table+2848(SB)/8, $0x0000000400000003
+DATA ·permuteInt64Table+2856(SB)/8, $0x0000000500000004
+DATA ·permuteInt64Table+2864(SB)/8, $0x0000000600000005
+DATA ·permuteInt64Table+2872(SB)/8, $0x0000000700000006
+DATA ·permuteInt64Table+2880(SB)/8, $0x0000000000000000
+DATA ·permuteInt64Table+2888(SB)/8, $0x000000000000001f
+DATA ·permuteInt64Table+2896(SB)/8, $0x000000000000001e
+DATA ·permuteInt64Table+2904(SB)/8, $0x000000000000001d
+DATA ·permuteInt64Table+2912(SB)/8, $0x000000000000001c
+DATA ·permuteInt64Table+2920(SB)/8, $0x000000000000001b
+DATA ·permuteInt64Table+2928(SB)/8, $0x000000000000001a
+DATA ·permuteInt64Table+2936(SB)/8, $0x0000000000000019
+
+// Total size: 2944 bytes
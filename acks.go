@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Drain requests for these tokens, or all of them when AT_LEAST_ONCE
+	// is "true", are only answered once their points have been written (or
+	// quarantined), so a crash can't lose points logplex considers
+	// delivered. Requests whose points aren't written within AckTimeout get
+	// a 503, so logplex retries them.
+	AtLeastOnce       = os.Getenv("AT_LEAST_ONCE") == "true"
+	AtLeastOnceTokens = parseSet(os.Getenv("AT_LEAST_ONCE_TOKENS"))
+	AckTimeout        = parseDurationSetting("ACK_TIMEOUT", os.Getenv("ACK_TIMEOUT"), 5*time.Second)
+
+	ackedRequestsCounter     = metrics.GetOrRegisterCounter("lumbermill.acks.delivered", metrics.DefaultRegistry)
+	ackFailedCounter         = metrics.GetOrRegisterCounter("lumbermill.acks.failed", metrics.DefaultRegistry)
+	ackTimedOutCounter       = metrics.GetOrRegisterCounter("lumbermill.acks.timeouts", metrics.DefaultRegistry)
+	ackWaitTimer             = metrics.GetOrRegisterTimer("lumbermill.acks.wait", metrics.DefaultRegistry)
+	errPointsNotDelivered    = errors.New("Points of the batch couldn't be delivered")
+	errPointsDeliveryTimeout = errors.New("Timed out delivering the points of the batch")
+)
+
+// Makes drain requests' ack ids unique
+var ackSeq uint64
+
+// The id the points of a drain request are acked by: its request id, so
+// logs still find them, made unique by the server, as clients may reuse
+// request ids
+func newAckId(requestId string) string {
+	return requestId + "/" + strconv.FormatUint(atomic.AddUint64(&ackSeq, 1), 10)
+}
+
+// The request id of the drain request a point was parsed from, without the
+// ack id's suffix
+func pointRequestId(id string) string {
+	if i := strings.LastIndexByte(id, '/'); i != -1 {
+		return id[:i]
+	}
+	return id
+}
+
+// Whether drain requests of the token wait for their points to be written
+func atLeastOnce(token string) bool {
+	return AtLeastOnce || AtLeastOnceTokens[token]
+}
+
+// A drain request waiting for its points to be written
+type pendingAck struct {
+	remaining int
+	failed    bool
+	done      chan struct{}
+}
+
+// Drain requests waiting for their points, by ack id
+type ackTracker struct {
+	sync.Mutex
+	pending map[string]*pendingAck
+	waiting int32 // len(pending), so posters can skip the lock when 0
+}
+
+var acks = newAckTracker()
+
+func newAckTracker() *ackTracker {
+	return &ackTracker{pending: make(map[string]*pendingAck)}
+}
+
+// Expects n points of the request to be written. It must be called before
+// they're handed to destinations.
+func (a *ackTracker) Expect(ackId string, n int) *pendingAck {
+	p := &pendingAck{remaining: n, done: make(chan struct{})}
+	if n == 0 {
+		close(p.done)
+		return p
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.pending[ackId] = p
+	atomic.StoreInt32(&a.waiting, int32(len(a.pending)))
+	return p
+}
+
+// Records that n points of the request were written
+func (a *ackTracker) Ack(ackId string, n int) {
+	if ackId == "" || atomic.LoadInt32(&a.waiting) == 0 {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	if p, found := a.pending[ackId]; found && !p.failed && p.remaining > 0 {
+		if p.remaining -= n; p.remaining <= 0 {
+			close(p.done)
+		}
+	}
+}
+
+// Records that points of the request were dropped, or couldn't be written
+func (a *ackTracker) Fail(ackId string) {
+	if ackId == "" || atomic.LoadInt32(&a.waiting) == 0 {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	if p, found := a.pending[ackId]; found && !p.failed && p.remaining > 0 {
+		p.failed = true
+		close(p.done)
+	}
+}
+
+// Stops waiting for the points of the request
+func (a *ackTracker) Forget(ackId string) {
+	a.Lock()
+	defer a.Unlock()
+	delete(a.pending, ackId)
+	atomic.StoreInt32(&a.waiting, int32(len(a.pending)))
+}
+
+// Waits up to timeout for the points of the request to be written, or
+// until ctx is done
+func (a *ackTracker) Wait(ctx context.Context, ackId string, p *pendingAck, timeout time.Duration) error {
+	start := time.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-p.done:
+	case <-timer.C:
+		err = errPointsDeliveryTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	a.Lock()
+	delete(a.pending, ackId)
+	atomic.StoreInt32(&a.waiting, int32(len(a.pending)))
+	if err == nil && p.failed {
+		err = errPointsNotDelivered
+	}
+	a.Unlock()
+
+	ackWaitTimer.UpdateSince(start)
+	switch err {
+	case nil:
+		ackedRequestsCounter.Inc(1)
+	case errPointsDeliveryTimeout:
+		ackTimedOutCounter.Inc(1)
+	default:
+		ackFailedCounter.Inc(1)
+	}
+	return err
+}
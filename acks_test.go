@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestAckTracker(t *testing.T) {
+	tracker := newAckTracker()
+	tracker.Ack("req-0", 1) // Nothing waiting
+
+	pending := tracker.Expect("req-1", 3)
+	tracker.Ack("req-1", 1)
+	tracker.Ack("req-1", 2)
+	if err := tracker.Wait(context.Background(), "req-1", pending, time.Second); err != nil {
+		t.Errorf("Expected the points to be acked, got %s", err)
+	}
+
+	pending = tracker.Expect("req-2", 3)
+	tracker.Ack("req-2", 1)
+	tracker.Fail("req-2")
+	if err := tracker.Wait(context.Background(), "req-2", pending, time.Second); err != errPointsNotDelivered {
+		t.Errorf("Expected a failed delivery, got %v", err)
+	}
+
+	pending = tracker.Expect("req-3", 1)
+	if err := tracker.Wait(context.Background(), "req-3", pending, 10*time.Millisecond); err != errPointsDeliveryTimeout {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+	if len(tracker.pending) != 0 || tracker.waiting != 0 {
+		t.Errorf("Expected no requests left waiting, got %d", len(tracker.pending))
+	}
+
+	if err := tracker.Wait(context.Background(), "req-4", tracker.Expect("req-4", 0), time.Second); err != nil {
+		t.Errorf("Expected a request without points to be done, got %s", err)
+	}
+}
+
+func TestDrainAtLeastOnce(t *testing.T) {
+	defer func(tokens map[string]bool) { AtLeastOnceTokens = tokens }(AtLeastOnceTokens)
+	AtLeastOnceTokens = parseSet("t.strict")
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	destination := NewDestination("acks-test", 10)
+	defer destination.Close()
+	go NewPoster(newTestWriter(db, ""), "acks-test", destination, new(sync.WaitGroup)).Run()
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	drain := func(token string) *httptest.ResponseRecorder {
+		req := lumbermilltest.NewDrainRequest("/drain", token,
+			lumbermilltest.SyslogLine(token, "router", router),
+			lumbermilltest.SyslogLine(token, "router", router),
+		)
+		recorder := httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+		return recorder
+	}
+
+	if recorder := drain("t.strict"); recorder.Code != http.StatusNoContent || db.PointCount() != 2 {
+		t.Errorf("Expected the batch to be answered once written, got %d with %d points", recorder.Code, db.PointCount())
+	}
+
+	db.Respond(lumbermilltest.Response{Status: 500})
+	recorder := drain("t.strict")
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected a 503 for a batch that wasn't written, got %d", recorder.Code)
+	}
+	assertErrorCode(t, recorder, errUndelivered)
+
+	db.Respond(lumbermilltest.Response{Status: 500})
+	if recorder := drain("t.other"); recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected other tokens not to wait, got %d", recorder.Code)
+	}
+}
+
+func TestDrainAtLeastOnceSharedRequestIds(t *testing.T) {
+	defer func(tokens map[string]bool) { AtLeastOnceTokens = tokens }(AtLeastOnceTokens)
+	AtLeastOnceTokens = parseSet("t.strict")
+
+	destination := NewDestination("acks-shared-test", 10)
+	defer destination.Close()
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+
+	// Two requests with the same id are waited on apart
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	codes := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		req := lumbermilltest.NewDrainRequest("/drain", "t.strict", lumbermilltest.SyslogLine("t.strict", "router", router))
+		req.Header.Set(requestIdHeader, "shared")
+		go func() {
+			recorder := httptest.NewRecorder()
+			server.serveDrain(recorder, req)
+			codes <- recorder.Code
+		}()
+	}
+
+	ackIds := make(map[string]bool)
+	for len(ackIds) < 2 {
+		point := <-destination.points
+		if pointRequestId(point.RequestId) != "shared" || ackIds[point.RequestId] {
+			t.Fatalf("Expected each request's points to have their own ack id, got %q", point.RequestId)
+		}
+		ackIds[point.RequestId] = true
+		destination.ack(point.RequestId, 1)
+	}
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != http.StatusNoContent {
+			t.Errorf("Expected both requests to be acked, got %d", code)
+		}
+	}
+}
+
+func TestDrainAtLeastOnceStalePoints(t *testing.T) {
+	defer func(tokens map[string]bool, maxAges []time.Duration) {
+		AtLeastOnceTokens, pointMaxAges = tokens, maxAges
+	}(AtLeastOnceTokens, pointMaxAges)
+	AtLeastOnceTokens = parseSet("t.strict")
+	pointMaxAges = seriesDurations("max age", "1h", nil)
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	destination := NewDestination("acks-stale-test", 10)
+	defer destination.Close()
+	go NewPoster(newTestWriter(db, ""), "acks-stale-test", destination, new(sync.WaitGroup)).Run()
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	req := lumbermilltest.NewDrainRequest("/drain", "t.strict",
+		lumbermilltest.SyslogLineAt(time.Now().Add(-2*time.Hour), "t.strict", "router", router),
+		lumbermilltest.SyslogLine("t.strict", "router", router),
+	)
+	recorder := httptest.NewRecorder()
+	server.serveDrain(recorder, req)
+
+	if recorder.Code != http.StatusNoContent || db.PointCount() != 1 {
+		t.Errorf("Expected the batch to be accepted without its stale point, got %d with %d points", recorder.Code, db.PointCount())
+	}
+}
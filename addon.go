@@ -0,0 +1,328 @@
+package main
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// The add-on manifest's id and password, which Heroku authenticates
+	// provisioning requests with, and its SSO salt. The add-on API is off
+	// unless ADDON_PASSWORD is set.
+	AddonId       = os.Getenv("ADDON_ID")
+	AddonPassword = os.Getenv("ADDON_PASSWORD")
+	AddonSSOSalt  = os.Getenv("ADDON_SSO_SALT")
+
+	// Where provisioned resources (and self-service drains) are kept, and
+	// the public host drain URLs point at, which must be set for either
+	AddonResourcesPath = os.Getenv("ADDON_RESOURCES_PATH")
+	AddonDrainHost     = os.Getenv("ADDON_DRAIN_HOST")
+)
+
+// How old an SSO request may be
+const addonSSOWindow = 5 * time.Minute
+
+// A provisioned add-on, or a self-service drain. Its drain authenticates as
+// the resource, and its lines are all for Token.
+type AddonResource struct {
+	Id       string    `json:"id"`
+	HerokuId string    `json:"heroku_id,omitempty"`
+	App      string    `json:"app,omitempty"`
+	Owner    string    `json:"owner,omitempty"` // User who minted a self-service drain
+	Plan     string    `json:"plan,omitempty"`
+	Region   string    `json:"region,omitempty"`
+	Token    string    `json:"token"`
+	Password string    `json:"password"`
+	Created  time.Time `json:"created"`
+}
+
+// The drain URL Heroku adds to the app
+func (a *AddonResource) DrainURL(host string) string {
+	return fmt.Sprintf("https://%s:%s@%s%s", a.Id, a.Password, host, DrainPath)
+}
+
+// Provisioned resources, saved to a JSON file when one is given
+type AddonStore struct {
+	sync.Mutex
+	path      string
+	resources map[string]*AddonResource
+}
+
+// Opens the store at path, loading the resources already in it. An empty
+// path keeps them in memory only.
+func NewAddonStore(path string) (*AddonStore, error) {
+	s := &AddonStore{path: path, resources: make(map[string]*AddonResource)}
+	if path == "" {
+		return s, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.resources); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Creates a resource with a fresh token and drain credentials
+func (s *AddonStore) Provision(herokuId, plan, region string) (*AddonResource, error) {
+	return s.add(&AddonResource{HerokuId: herokuId, Plan: plan, Region: region})
+}
+
+// Creates a self-service drain for app, owned by the user minting it
+func (s *AddonStore) Mint(app, owner string) (*AddonResource, error) {
+	return s.add(&AddonResource{App: app, Owner: owner})
+}
+
+func (s *AddonStore) add(resource *AddonResource) (*AddonResource, error) {
+	resource.Id = newRequestId()
+	resource.Token = "t." + newRequestId()
+	resource.Password = newRequestId()
+	resource.Created = time.Now().UTC()
+
+	s.Lock()
+	defer s.Unlock()
+	s.resources[resource.Id] = resource
+	return resource, s.save()
+}
+
+// Removes a resource, returning whether it existed
+func (s *AddonStore) Deprovision(id string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if _, found := s.resources[id]; !found {
+		return false, nil
+	}
+	delete(s.resources, id)
+	return true, s.save()
+}
+
+// Moves a resource to another plan, returning it
+func (s *AddonStore) ChangePlan(id, plan string) (*AddonResource, error) {
+	s.Lock()
+	defer s.Unlock()
+	resource, found := s.resources[id]
+	if !found {
+		return nil, nil
+	}
+	resource.Plan = plan
+	return resource, s.save()
+}
+
+// The resource with id, or nil. A nil store has none.
+func (s *AddonStore) Get(id string) *AddonResource {
+	if s == nil {
+		return nil
+	}
+	s.Lock()
+	defer s.Unlock()
+	return s.resources[id]
+}
+
+// The self-service drains minted by owner
+func (s *AddonStore) Owned(owner string) []*AddonResource {
+	s.Lock()
+	defer s.Unlock()
+	owned := make([]*AddonResource, 0)
+	for _, resource := range s.resources {
+		if resource.Owner == owner {
+			owned = append(owned, resource)
+		}
+	}
+	return owned
+}
+
+// The app of each resource's token, for those with an app. A nil store has
+// none.
+func (s *AddonStore) Apps() map[string]string {
+	apps := make(map[string]string)
+	if s == nil {
+		return apps
+	}
+	s.Lock()
+	defer s.Unlock()
+	for _, resource := range s.resources {
+		if resource.App != "" {
+			apps[resource.Token] = resource.App
+		}
+	}
+	return apps
+}
+
+// Writes the resources to a temporary file and moves it into place, so a
+// crash never leaves a partial file behind.
+func (s *AddonStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.resources)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Authenticates a request from Heroku with the manifest's credentials
+func (s *LumbermillServer) checkAddonAuth(w http.ResponseWriter, r *http.Request) bool {
+	if s.addons == nil || secrets.Get("ADDON_PASSWORD", AddonPassword) == "" {
+		writeError(w, r, http.StatusNotFound, errNotFound, "The add-on API is disabled")
+		return false
+	}
+	user, pass, ok := r.BasicAuth()
+	if !ok || user != AddonId || subtle.ConstantTimeCompare([]byte(pass), []byte(secrets.Get("ADDON_PASSWORD", AddonPassword))) != 1 {
+		writeError(w, r, http.StatusUnauthorized, errAuthFailed, "Incorrect add-on credentials")
+		authFailureCounter.Inc(1)
+		return false
+	}
+	return true
+}
+
+// The request body of provisioning and plan changes
+type addonRequest struct {
+	HerokuId string `json:"heroku_id"`
+	Plan     string `json:"plan"`
+	Region   string `json:"region"`
+}
+
+// POST /heroku/resources to provision, PUT /heroku/resources/<id> to change
+// plans and DELETE /heroku/resources/<id> to deprovision.
+func (s *LumbermillServer) serveAddonResources(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAddonAuth(w, r) {
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/heroku/resources"), "/")
+	var request addonRequest
+	if r.Method == "POST" || r.Method == "PUT" {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, r, http.StatusBadRequest, errBadRequest, err.Error())
+			return
+		}
+	}
+
+	switch {
+	case r.Method == "POST" && id == "":
+		resource, err := s.addons.Provision(request.HerokuId, request.Plan, request.Region)
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, errInternal, err.Error())
+			return
+		}
+		auditLog.Record("heroku", "addon.provision", nil, map[string]string{"id": resource.Id, "plan": resource.Plan, "token": resource.Token})
+		writeJSON(w, map[string]interface{}{
+			"id":            resource.Id,
+			"config":        map[string]string{"LUMBERMILL_TOKEN": resource.Token},
+			"log_drain_url": resource.DrainURL(AddonDrainHost),
+			"message":       "Lines drained to lumbermill are recorded as " + resource.Token,
+		})
+
+	case r.Method == "PUT" && id != "":
+		before := s.addons.Get(id)
+		var beforePlan string
+		if before != nil {
+			beforePlan = before.Plan
+		}
+		resource, err := s.addons.ChangePlan(id, request.Plan)
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, errInternal, err.Error())
+			return
+		}
+		if resource == nil {
+			writeError(w, r, http.StatusNotFound, errNotFound, "Unknown resource")
+			return
+		}
+		auditLog.Record("heroku", "addon.plan", beforePlan, resource.Plan)
+		writeJSON(w, map[string]interface{}{
+			"config":  map[string]string{"LUMBERMILL_TOKEN": resource.Token},
+			"message": "Moved to " + resource.Plan,
+		})
+
+	case r.Method == "DELETE" && id != "":
+		found, err := s.addons.Deprovision(id)
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, errInternal, err.Error())
+			return
+		}
+		if !found {
+			writeError(w, r, http.StatusGone, errNotFound, "Unknown resource")
+			return
+		}
+		auditLog.Record("heroku", "addon.deprovision", id, nil)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, errMethodNotAllowed, "Unsupported method")
+	}
+}
+
+// Whether an SSO token was signed with the salt, less than addonSSOWindow ago
+func validSSOToken(id, timestamp, token, salt string, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age < -addonSSOWindow || age > addonSSOWindow {
+		return false
+	}
+	sum := sha1.Sum([]byte(id + ":" + salt + ":" + timestamp))
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(token)) == 1
+}
+
+var addonSSOPage = template.Must(template.New("sso").Parse(`<!DOCTYPE html>
+<html>
+<head><title>lumbermill</title></head>
+<body>
+<h1>lumbermill</h1>
+<p>Lines drained from {{.HerokuId}} are recorded as <code>{{.Token}}</code>, on the {{.Plan}} plan.</p>
+<p>Autoscale signals: <a href="/autoscale/{{.Token}}">/autoscale/{{.Token}}</a></p>
+</body>
+</html>
+`))
+
+// POST /heroku/sso, where Heroku sends users opening the add-on from the
+// dashboard
+func (s *LumbermillServer) serveAddonSSO(w http.ResponseWriter, r *http.Request) {
+	if s.addons == nil || secrets.Get("ADDON_SSO_SALT", AddonSSOSalt) == "" {
+		writeError(w, r, http.StatusNotFound, errNotFound, "The add-on API is disabled")
+		return
+	}
+	if r.Method != "POST" {
+		writeError(w, r, http.StatusMethodNotAllowed, errMethodNotAllowed, "Only POST is accepted")
+		return
+	}
+
+	id := r.FormValue("id")
+	if !validSSOToken(id, r.FormValue("timestamp"), r.FormValue("token"), secrets.Get("ADDON_SSO_SALT", AddonSSOSalt), time.Now()) {
+		writeError(w, r, http.StatusForbidden, errAuthFailed, "Invalid or expired SSO token")
+		authFailureCounter.Inc(1)
+		return
+	}
+	resource := s.addons.Get(id)
+	if resource == nil {
+		writeError(w, r, http.StatusNotFound, errNotFound, "Unknown resource")
+		return
+	}
+
+	// Lets Heroku's navigation header render on the page
+	http.SetCookie(w, &http.Cookie{Name: "heroku-nav-data", Value: r.FormValue("nav-data"), Path: "/"})
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	addonSSOPage.Execute(w, resource)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestAddonLifecycle(t *testing.T) {
+	defer func(id, password, host string) { AddonId, AddonPassword, AddonDrainHost = id, password, host }(AddonId, AddonPassword, AddonDrainHost)
+	AddonId, AddonPassword, AddonDrainHost = "lumbermill", "manifest", "drain.example.com"
+
+	dir, err := ioutil.TempDir("", "lumbermill-addon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "resources.json")
+
+	destination := NewDestination("addon-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	if server.addons, err = NewAddonStore(path); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth("lumbermill", "manifest")
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	recorder := call("POST", "/heroku/resources", `{"heroku_id":"app123@heroku.com","plan":"test","region":"amazon-web-services::us-east-1"}`)
+	var provisioned struct {
+		Id          string            `json:"id"`
+		Config      map[string]string `json:"config"`
+		LogDrainURL string            `json:"log_drain_url"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &provisioned); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("Provisioning failed (%d): %s", recorder.Code, recorder.Body)
+	}
+	token := provisioned.Config["LUMBERMILL_TOKEN"]
+
+	// The drain URL's credentials map the drain's lines to the token
+	drainURL, err := url.Parse(provisioned.LogDrainURL)
+	if err != nil || drainURL.User.Username() != provisioned.Id || drainURL.Host != "drain.example.com" {
+		t.Fatalf("Unexpected drain URL: %s", provisioned.LogDrainURL)
+	}
+	password, _ := drainURL.User.Password()
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	req := lumbermilltest.NewDrainRequest("/drain", "d.logplex", lumbermilltest.SyslogLine("heroku", "router", router))
+	req.SetBasicAuth(provisioned.Id, password)
+	server.serveDrain(httptest.NewRecorder(), req)
+	if len(destination.points) != 1 || (<-destination.points).Token != token {
+		t.Errorf("Expected the add-on's line to be recorded as %s", token)
+	}
+
+	// They're good for nothing else
+	req, _ = http.NewRequest("GET", "/dashboards/grafana?datasource=influx&token=t.other", nil)
+	req.SetBasicAuth(provisioned.Id, password)
+	recorder = httptest.NewRecorder()
+	server.serveGrafanaDashboard(recorder, req)
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected add-on credentials to be refused outside of drains, got %d", recorder.Code)
+	}
+
+	// Resources survive restarts
+	reloaded, err := NewAddonStore(path)
+	if err != nil || reloaded.Get(provisioned.Id) == nil {
+		t.Fatalf("Resource wasn't saved: %v", err)
+	}
+
+	if recorder := call("PUT", "/heroku/resources/"+provisioned.Id, `{"plan":"basic"}`); recorder.Code != http.StatusOK || server.addons.Get(provisioned.Id).Plan != "basic" {
+		t.Errorf("Plan change failed (%d): %s", recorder.Code, recorder.Body)
+	}
+	if recorder := call("DELETE", "/heroku/resources/"+provisioned.Id, ""); recorder.Code != http.StatusNoContent || server.addons.Get(provisioned.Id) != nil {
+		t.Errorf("Deprovisioning failed (%d): %s", recorder.Code, recorder.Body)
+	}
+	if recorder := call("DELETE", "/heroku/resources/"+provisioned.Id, ""); recorder.Code != http.StatusGone {
+		t.Errorf("Expected deprovisioning twice to be gone, got %d", recorder.Code)
+	}
+
+	req, _ = http.NewRequest("POST", "/heroku/resources", strings.NewReader(`{}`))
+	req.SetBasicAuth("lumbermill", "wrong")
+	recorder = httptest.NewRecorder()
+	server.http.Handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected wrong manifest credentials to be refused, got %d", recorder.Code)
+	}
+}
+
+func TestValidSSOToken(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	sign := func(id, salt string, at time.Time) (string, string) {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		sum := sha1.Sum([]byte(id + ":" + salt + ":" + timestamp))
+		return timestamp, hex.EncodeToString(sum[:])
+	}
+
+	timestamp, token := sign("r1", "salt", now.Add(-time.Minute))
+	if !validSSOToken("r1", timestamp, token, "salt", now) {
+		t.Errorf("Expected a fresh token to be valid")
+	}
+	if validSSOToken("r2", timestamp, token, "salt", now) || validSSOToken("r1", timestamp, token, "pepper", now) {
+		t.Errorf("Expected a token for another resource or salt to be invalid")
+	}
+	timestamp, token = sign("r1", "salt", now.Add(-time.Hour))
+	if validSSOToken("r1", timestamp, token, "salt", now) {
+		t.Errorf("Expected an old token to be invalid")
+	}
+}
+
+func TestSelfServiceDrains(t *testing.T) {
+	defer func(enabled bool, users, host string) {
+		SelfServiceDrains, DrainUsers, AddonDrainHost = enabled, users, host
+	}(SelfServiceDrains, DrainUsers, AddonDrainHost)
+	SelfServiceDrains = true
+	DrainUsers = "team-a=a,team-b=b"
+	AddonDrainHost = "drain.example.com"
+
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	server.addons, _ = NewAddonStore("")
+
+	call := func(method, path, user, password string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.SetBasicAuth(user, password)
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	var minted drainURLResponse
+	recorder := call("POST", "/drains?app=shop", "team-a", "a")
+	if err := json.Unmarshal(recorder.Body.Bytes(), &minted); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("Minting failed (%d): %s", recorder.Code, recorder.Body)
+	}
+	if resource := server.addons.Get(minted.Id); resource == nil || resource.App != "shop" || resource.Owner != "team-a" {
+		t.Errorf("Mapping wasn't recorded: %+v", resource)
+	}
+
+	var listed []drainURLResponse
+	json.Unmarshal(call("GET", "/drains", "team-b", "b").Body.Bytes(), &listed)
+	if len(listed) != 0 {
+		t.Errorf("Expected team-b to see none of team-a's drains, got %v", listed)
+	}
+	json.Unmarshal(call("GET", "/drains", "team-a", "a").Body.Bytes(), &listed)
+	if len(listed) != 1 || listed[0].DrainURL != minted.DrainURL {
+		t.Errorf("Expected team-a to see its drain, got %v", listed)
+	}
+
+	drainURL, _ := url.Parse(minted.DrainURL)
+	password, _ := drainURL.User.Password()
+	if recorder := call("POST", "/drains?app=other", minted.Id, password); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected drain credentials not to mint drains, got %d", recorder.Code)
+	}
+
+	if recorder := call("DELETE", "/drains/"+minted.Id, "team-b", "b"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected team-b not to revoke team-a's drain, got %d", recorder.Code)
+	}
+	if recorder := call("DELETE", "/drains/"+minted.Id, "team-a", "a"); recorder.Code != http.StatusNoContent || server.addons.Get(minted.Id) != nil {
+		t.Errorf("Revoking failed (%d)", recorder.Code)
+	}
+}
+
+func TestAddonsRequireDrainHost(t *testing.T) {
+	defer func(enabled bool, host string) { SelfServiceDrains, AddonDrainHost = enabled, host }(SelfServiceDrains, AddonDrainHost)
+	SelfServiceDrains, AddonDrainHost = true, ""
+
+	if _, _, err := SetupLumbermill(&http.Server{}, "", true); err == nil {
+		t.Errorf("Expected self-service drains without ADDON_DRAIN_HOST to be refused")
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// What an admin API caller may do. Each role may do what the ones before it
+// can.
+type Role int
+
+const (
+	NoRole   Role = iota
+	ReadOnly      // Inspect state
+	Operator      // Also toggle debugging and the like
+	Admin         // Also change routing
+)
+
+var roleNames = map[string]Role{"read-only": ReadOnly, "operator": Operator, "admin": Admin}
+
+var (
+	// Roles of users, and of API keys sent as "Authorization: Bearer <key>",
+	// as "<user or key>=<role>,...". USER is an admin unless given another
+	// role; other users have none.
+	AdminRoles   = os.Getenv("ADMIN_ROLES")
+	AdminAPIKeys = os.Getenv("ADMIN_API_KEYS")
+)
+
+func parseRole(name string) Role {
+	return roleNames[name]
+}
+
+func (r Role) String() string {
+	for name, role := range roleNames {
+		if role == r {
+			return name
+		}
+	}
+	return "none"
+}
+
+// A boolean setting that can be changed at runtime
+type toggle struct {
+	on int32
+}
+
+func newToggle(on bool) *toggle {
+	t := &toggle{}
+	t.Set(on)
+	return t
+}
+
+func (t *toggle) On() bool {
+	return atomic.LoadInt32(&t.on) == 1
+}
+
+func (t *toggle) Set(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&t.on, v)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	body, _ := json.Marshal(v)
+	headers := w.Header()
+	headers.Set("Content-Length", fmt.Sprintf("%d", len(body)))
+	headers.Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// Authenticates an admin API request with a user or an API key, returning
+// who made it and their role
+func (s *LumbermillServer) adminAuthenticate(r *http.Request) (string, Role, error) {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		key := strings.TrimPrefix(header, "Bearer ")
+		for k, role := range parseKeyValueList(secrets.Get("ADMIN_API_KEYS", AdminAPIKeys)) {
+			if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+				return "key:" + fingerprint(key), parseRole(role), nil
+			}
+		}
+		return "", NoRole, fmt.Errorf("Unknown API key")
+	}
+
+	user, err := s.authenticate(r)
+	if err != nil {
+		return "", NoRole, err
+	}
+	if role, found := parseKeyValueList(AdminRoles)[user]; found {
+		return user, parseRole(role), nil
+	}
+	if user == secrets.Get("USER", User) {
+		return user, Admin, nil
+	}
+	return user, NoRole, nil
+}
+
+// Authenticates an admin API request, making sure the caller has at least
+// the given role. Returns who made it.
+func (s *LumbermillServer) adminActor(w http.ResponseWriter, r *http.Request, role Role) (string, bool) {
+	actor, has, err := s.adminAuthenticate(r)
+	if err != nil {
+		writeError(w, r, http.StatusForbidden, errAuthFailed, err.Error())
+		authFailureCounter.Inc(1)
+		return "", false
+	}
+	if has < role {
+		writeError(w, r, http.StatusForbidden, errInsufficientRole, fmt.Sprintf("Requires the %s role", role))
+		return "", false
+	}
+	return actor, true
+}
+
+// The role needed to read (GET) or change (POST) an admin resource
+func roleFor(r *http.Request, change Role) Role {
+	if r.Method == "POST" {
+		return change
+	}
+	return ReadOnly
+}
+
+// GET /admin/audit
+func (s *LumbermillServer) serveAdminAudit(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.adminActor(w, r, ReadOnly); !ok {
+		return
+	}
+	writeJSON(w, auditLog.Entries())
+}
+
+// GET /admin/debug, or POST /admin/debug?enabled=<bool> to toggle debug
+// logging
+func (s *LumbermillServer) serveAdminDebug(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.adminActor(w, r, roleFor(r, Operator))
+	if !ok {
+		return
+	}
+
+	if r.Method == "POST" {
+		enabled, err := strconv.ParseBool(r.URL.Query().Get("enabled"))
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "enabled must be true or false")
+			return
+		}
+		before := Debug.On()
+		Debug.Set(enabled)
+		auditLog.Record(actor, "debug.set", before, enabled)
+	}
+
+	writeJSON(w, map[string]bool{"enabled": Debug.On()})
+}
+
+// GET /admin/ring, or POST /admin/ring?action=add|remove&destination=<name>
+// to take a destination out of the ring, or put it back.
+func (s *LumbermillServer) serveAdminRing(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.adminActor(w, r, roleFor(r, Admin))
+	if !ok {
+		return
+	}
+
+	if r.Method == "POST" {
+		query := r.URL.Query()
+		var destination *Destination
+		for _, d := range s.destinations {
+			if d.Name == query.Get("destination") {
+				destination = d
+			}
+		}
+		if destination == nil {
+			writeError(w, r, http.StatusNotFound, errNotFound, "Unknown destination")
+			return
+		}
+
+		before := s.hashRing.Members()
+		switch action := query.Get("action"); action {
+		case "add":
+			if !s.hashRing.Contains(destination) {
+				s.hashRing.Add(destination)
+			}
+		case "remove":
+			s.hashRing.Remove(destination)
+		default:
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "action must be add or remove")
+			return
+		}
+		auditLog.Record(actor, "ring."+query.Get("action"), before, s.hashRing.Members())
+	}
+
+	writeJSON(w, map[string][]string{"members": s.hashRing.Members()})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdminRoles(t *testing.T) {
+	defer func(roles, keys, users string) { AdminRoles, AdminAPIKeys, DrainUsers = roles, keys, users }(AdminRoles, AdminAPIKeys, DrainUsers)
+	User, Password = "foo", "foo"
+	DrainUsers = "oncall=pager,tenant=secret"
+	AdminRoles = "oncall=read-only"
+	AdminAPIKeys = "k3y=operator"
+
+	a := NewDestination("roles-a", 1)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(a)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.destinations = []*Destination{a}
+
+	for _, tc := range []struct {
+		method, path string
+		auth         func(*http.Request)
+		status       int
+	}{
+		{"GET", "/admin/ring", func(r *http.Request) { r.SetBasicAuth("oncall", "pager") }, http.StatusOK},
+		{"POST", "/admin/ring?action=remove&destination=roles-a", func(r *http.Request) { r.SetBasicAuth("oncall", "pager") }, http.StatusForbidden},
+		{"GET", "/admin/ring", func(r *http.Request) { r.SetBasicAuth("tenant", "secret") }, http.StatusForbidden},
+		{"POST", "/admin/debug?enabled=false", func(r *http.Request) { r.Header.Set("Authorization", "Bearer k3y") }, http.StatusOK},
+		{"POST", "/admin/ring?action=remove&destination=roles-a", func(r *http.Request) { r.Header.Set("Authorization", "Bearer k3y") }, http.StatusForbidden},
+		{"GET", "/admin/ring", func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") }, http.StatusForbidden},
+		{"POST", "/admin/ring?action=add&destination=roles-a", func(r *http.Request) { r.SetBasicAuth("foo", "foo") }, http.StatusOK},
+	} {
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		tc.auth(req)
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		if recorder.Code != tc.status {
+			t.Errorf("%s %s: Expected %d, got %d", tc.method, tc.path, tc.status, recorder.Code)
+		}
+	}
+
+	if !hashRing.Contains(a) {
+		t.Errorf("A read-only user or operator changed the ring")
+	}
+}
+
+func TestAdminStatus(t *testing.T) {
+	User, Password = "foo", "foo"
+	a := NewDestination("status-a", 10)
+	a.PostPoint(pointAt(1))
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	server.destinations = []*Destination{a}
+	server.throughput = NewThroughput(time.Minute)
+	for i, token := range []string{"t.quiet", "t.busy", "t.busy"} {
+		server.throughput.Record([]Point{{Token: token, Type: Router, Points: []interface{}{int64(i), 200, 10, 0, "web"}}})
+	}
+	server.throughput.Roll()
+
+	req, _ := http.NewRequest("GET", "/admin/status?format=json", nil)
+	req.SetBasicAuth("foo", "foo")
+	recorder := httptest.NewRecorder()
+	server.http.Handler.ServeHTTP(recorder, req)
+
+	var status pipelineStatus
+	if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Destinations) != 1 || status.Destinations[0].Pending != 1 {
+		t.Errorf("Expected status-a's backlog, got %+v", status.Destinations)
+	}
+	if len(status.TopTokens) != 2 || status.TopTokens[0].Token != "t.busy" {
+		t.Errorf("Expected tokens by volume, got %+v", status.TopTokens)
+	}
+
+	req, _ = http.NewRequest("GET", "/admin/status", nil)
+	req.SetBasicAuth("foo", "foo")
+	recorder = httptest.NewRecorder()
+	server.http.Handler.ServeHTTP(recorder, req)
+	if !strings.Contains(recorder.Body.String(), "<html>") {
+		t.Errorf("Expected the status page")
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/heroku/lumbermill/proto/pointsv1"
+	metrics "github.com/rcrowley/go-metrics"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// Directory the points delivered are archived to as well, as
+	// proto/points.proto batches in a file per hour (UTC) they were
+	// archived in, <dir>/<date>/<hour>.points, for `lumbermill replay` and
+	// `lumbermill export`. Not archived when unset.
+	ArchivePath = os.Getenv("ARCHIVE_PATH")
+
+	// Points per archived batch. Batches are written at least every second.
+	ArchiveBatchSize = parseIntSetting("ARCHIVE_BATCH_SIZE", os.Getenv("ARCHIVE_BATCH_SIZE"), 1000)
+)
+
+// The points.proto schema version archived batches are written with
+const archiveSchemaVersion = 1
+
+// Writes the points of its destination to hourly archive files, as
+// length-delimited points.proto batches appended to them
+type ArchivePoster struct {
+	destination   *Destination
+	dir           string
+	batchSize     int
+	file          *os.File
+	hour          string // <date>/<hour> of the file open
+	batch         []*pointsv1.Point
+	pointsCounter metrics.Counter
+	errorsCounter metrics.Counter
+	waitGroup     *sync.WaitGroup
+}
+
+func NewArchivePoster(dir string, batchSize int, destination *Destination, waitGroup *sync.WaitGroup) *ArchivePoster {
+	if batchSize < 1 {
+		log.Printf("Invalid archive batch size (%d), using 1000\n", batchSize)
+		batchSize = 1000
+	}
+	waitGroup.Add(1)
+	return &ArchivePoster{
+		destination:   destination,
+		dir:           dir,
+		batchSize:     batchSize,
+		pointsCounter: metrics.GetOrRegisterCounter("lumbermill.poster.archive.points", metrics.DefaultRegistry),
+		errorsCounter: metrics.GetOrRegisterCounter("lumbermill.poster.archive.errors", metrics.DefaultRegistry),
+		waitGroup:     waitGroup,
+	}
+}
+
+// The archive file of the hour now is in, opened when the hour changed
+func (p *ArchivePoster) open(now time.Time) error {
+	hour := now.UTC().Format("2006-01-02/15")
+	if p.file != nil && hour == p.hour {
+		return nil
+	}
+	path := filepath.Join(p.dir, hour+".points")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if p.file != nil {
+		p.file.Close()
+	}
+	p.file, p.hour = file, hour
+	return nil
+}
+
+// Writes the batch, emptying it. Points that can't be archived are
+// counted and logged, delivery to the backends going on regardless.
+func (p *ArchivePoster) flush() {
+	if len(p.batch) == 0 {
+		return
+	}
+	now := time.Now()
+	batch := &pointsv1.Batch{SchemaVersion: archiveSchemaVersion, Points: p.batch, CreatedUnixMicros: now.UnixNano() / int64(time.Microsecond)}
+	err := p.open(now)
+	if err == nil {
+		err = writeArchiveBatch(p.file, batch)
+	}
+	if err != nil {
+		p.errorsCounter.Inc(int64(len(p.batch)))
+		log.Printf("Unable to archive %d points to %s: %s\n", len(p.batch), p.dir, err)
+	} else {
+		p.pointsCounter.Inc(int64(len(p.batch)))
+	}
+	p.batch = make([]*pointsv1.Point, 0, p.batchSize)
+}
+
+func (p *ArchivePoster) Run() {
+	defer p.waitGroup.Done()
+
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+	defer func() {
+		p.flush()
+		if p.file != nil {
+			p.file.Close()
+		}
+	}()
+
+	for {
+		select {
+		case point, open := <-p.destination.points:
+			if !open {
+				return
+			}
+			p.batch = append(p.batch, pointProto(point))
+			if len(p.batch) >= p.batchSize {
+				p.flush()
+			}
+		case <-flush.C:
+			p.flush()
+		}
+	}
+}
+
+// A point as a points.proto point
+func pointProto(point Point) *pointsv1.Point {
+	values := make([]*pointsv1.Value, len(point.Points))
+	for i, v := range point.Points {
+		values[i] = pointValue(v)
+	}
+	return &pointsv1.Point{Token: point.Token, Series: point.Type.Name(), Values: values, RequestId: point.RequestId, Prefix: point.Prefix}
+}
+
+// A points.proto point as the Point it was. Ints are ints as the parser
+// makes them, but for the time, which is an int64.
+func pointFromProto(p *pointsv1.Point) (Point, error) {
+	st, found := seriesTypesByName[p.Series]
+	if !found {
+		return Point{}, fmt.Errorf("unknown series %q", p.Series)
+	}
+	if len(p.Values) == 0 || len(p.Values) > len(st.Columns()) {
+		return Point{}, fmt.Errorf("%d values for the %d columns of %s", len(p.Values), len(st.Columns()), p.Series)
+	}
+	values := make([]interface{}, len(p.Values))
+	for i, v := range p.Values {
+		switch kind := v.Kind.(type) {
+		case *pointsv1.Value_Int:
+			if i == 0 {
+				values[i] = kind.Int
+			} else {
+				values[i] = int(kind.Int)
+			}
+		case *pointsv1.Value_Float:
+			values[i] = kind.Float
+		case *pointsv1.Value_String_:
+			values[i] = kind.String_
+		case *pointsv1.Value_Bool:
+			values[i] = kind.Bool
+		}
+	}
+	if _, ok := values[0].(int64); !ok {
+		return Point{}, fmt.Errorf("%s point without a time", p.Series)
+	}
+	return Point{Token: p.Token, Type: st, Points: values, RequestId: p.RequestId, Prefix: p.Prefix}, nil
+}
+
+// Writes batch to w, prefixed with its length as a varint
+func writeArchiveBatch(w io.Writer, batch *pointsv1.Batch) error {
+	b, err := proto.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	record := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(b))
+	record = append(record[:binary.PutUvarint(record, uint64(len(b)))], b...)
+	_, err = w.Write(record)
+	return err
+}
+
+// Reads the next batch of r, or io.EOF when there are no more. Batches of
+// a newer schema version than this lumbermill's are refused.
+func readArchiveBatch(r *bufio.Reader) (*pointsv1.Batch, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	batch := new(pointsv1.Batch)
+	if err := proto.Unmarshal(b, batch); err != nil {
+		return nil, err
+	}
+	if batch.SchemaVersion > archiveSchemaVersion {
+		return nil, fmt.Errorf("schema version %d is newer than %d", batch.SchemaVersion, archiveSchemaVersion)
+	}
+	return batch, nil
+}
+
+// Calls each with the points of the archive file at path, in order,
+// returning how many there were
+func readArchive(path string, each func(Point) error) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	points := 0
+	for n := 1; ; n++ {
+		batch, err := readArchiveBatch(r)
+		if err == io.EOF {
+			return points, nil
+		}
+		if err != nil {
+			return points, fmt.Errorf("batch %d: %s", n, err)
+		}
+		for _, p := range batch.Points {
+			point, err := pointFromProto(p)
+			if err != nil {
+				return points, fmt.Errorf("batch %d: %s", n, err)
+			}
+			if err := each(point); err != nil {
+				return points, err
+			}
+			points++
+		}
+	}
+}
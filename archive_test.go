@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/heroku/lumbermill/proto/pointsv1"
+)
+
+func TestArchivePoster(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumbermill-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backend, archive := NewDestination("archive-test-backend", 10), NewDestination("archive-test", 10)
+	archive.candidate = true
+	backend.Archive = archive
+	waitGroup := new(sync.WaitGroup)
+	poster := NewArchivePoster(dir, 2, archive, waitGroup)
+
+	points := []Point{
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1425579721500000), 200, 23, 1, "web", nil, nil, nil, "DE", "EU"}, RequestId: "req-1"},
+		{Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(1425579722000000), "H12", "web.1", "/", "web", false, nil}, RequestId: "req-1", Prefix: "staging."},
+		{Token: "t.b", Type: DynoMem, Points: []interface{}{int64(1425579723000000), "web.1", 1.5, 2.5, 3.5, 4.5, 5.5, 6.5, "web", nil}},
+	}
+	for _, point := range points {
+		backend.PostPoint(point)
+	}
+	if len(backend.points) != 3 {
+		t.Fatalf("Expected the backend to get the points too, got %d", len(backend.points))
+	}
+	archive.Close()
+	poster.Run()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "*.points"))
+	if len(files) != 1 {
+		t.Fatalf("Expected an archive file for the hour, got %v", files)
+	}
+	var read []Point
+	if n, err := readArchive(files[0], func(point Point) error {
+		read = append(read, point)
+		return nil
+	}); err != nil || n != 3 {
+		t.Fatalf("Expected 3 points archived, got %d: %v", n, err)
+	}
+	if !reflect.DeepEqual(read, points) {
+		t.Errorf("Expected the points as they were posted\nexpected: %v\nactual:   %v", points, read)
+	}
+}
+
+func TestReadArchiveBatchRefusesNewerSchemas(t *testing.T) {
+	var b bytes.Buffer
+	writeArchiveBatch(&b, &pointsv1.Batch{SchemaVersion: archiveSchemaVersion + 1})
+	if _, err := readArchiveBatch(bufio.NewReader(&b)); err == nil {
+		t.Errorf("Expected a batch of a newer schema version to be refused")
+	}
+}
+
+func TestReplayArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumbermill-replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "00.points")
+	f, _ := os.Create(path)
+	writeArchiveBatch(f, &pointsv1.Batch{SchemaVersion: archiveSchemaVersion, Points: []*pointsv1.Point{
+		pointProto(Point{Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(1), "H12", "web.1", "/", "web", false, nil}, RequestId: "req-1"}),
+		pointProto(Point{Token: "t.b", Type: EventsRouter, Points: []interface{}{int64(2), "H10", "web.1", "/", "web", false, nil}, RequestId: "req-2"}),
+	}})
+	f.Close()
+
+	destination := NewDestination("replay-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	if n, err := replayArchive(path, "t.b", hashRing); err != nil || n != 1 {
+		t.Fatalf("Expected the token's point to be replayed, got %d: %v", n, err)
+	}
+	if point := <-destination.points; point.Token != "t.b" || point.Points[1] != "H10" || point.RequestId != "" {
+		t.Errorf("Expected t.b's point, without its request, got %v", point)
+	}
+}
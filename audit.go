@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Number of entries kept in memory for the admin API
+const auditLogEntries = 1000
+
+// A runtime change to lumbermill's configuration
+type AuditEntry struct {
+	Time   time.Time   `json:"time"`
+	Actor  string      `json:"actor"` // User making the change, or "system"
+	Action string      `json:"action"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// An append-only log of runtime changes, written to a file as JSON lines
+// when one is given. The latest entries are kept in memory for the admin API.
+type AuditLog struct {
+	sync.Mutex
+	file    *os.File
+	entries []AuditEntry
+}
+
+// Opens the audit log at path, picking up its latest entries. An empty path
+// keeps the log in memory only.
+func NewAuditLog(path string) (*AuditLog, error) {
+	a := &AuditLog{}
+	if path == "" {
+		return a, nil
+	}
+
+	if existing, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(existing)
+		for scanner.Scan() {
+			var entry AuditEntry
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+				a.append(entry)
+			}
+		}
+		existing.Close()
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	a.file = file
+	return a, nil
+}
+
+// Records a change made by actor
+func (a *AuditLog) Record(actor, action string, before, after interface{}) {
+	entry := AuditEntry{Time: time.Now().UTC(), Actor: actor, Action: action, Before: before, After: after}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error encoding audit entry for %s: %s\n", action, err)
+		return
+	}
+	log.Printf("at=audit %s\n", line)
+
+	a.Lock()
+	defer a.Unlock()
+	a.append(entry)
+	if a.file != nil {
+		if _, err := a.file.Write(append(line, '\n')); err != nil {
+			log.Printf("Error writing audit entry for %s: %s\n", action, err)
+		}
+	}
+}
+
+func (a *AuditLog) append(entry AuditEntry) {
+	a.entries = append(a.entries, entry)
+	if len(a.entries) > auditLogEntries {
+		a.entries = a.entries[len(a.entries)-auditLogEntries:]
+	}
+}
+
+// The latest entries, oldest first
+func (a *AuditLog) Entries() []AuditEntry {
+	a.Lock()
+	defer a.Unlock()
+	return append([]AuditEntry(nil), a.entries...)
+}
+
+// Identifies versions of secret values in the audit log without revealing
+// them
+func fingerprints(values map[string]string) map[string]string {
+	prints := make(map[string]string, len(values))
+	for k, v := range values {
+		prints[k] = fingerprint(v)
+	}
+	return prints
+}
+
+func fingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
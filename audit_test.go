@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuditLogPersists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumbermill-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	first, err := NewAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Record("alice", "debug.set", false, true)
+
+	second, err := NewAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.Record("bob", "debug.set", true, false)
+
+	entries := second.Entries()
+	if len(entries) != 2 || entries[0].Actor != "alice" || entries[1].Actor != "bob" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestAdminRingEditsAreAudited(t *testing.T) {
+	User, Password = "foo", "foo"
+	a, b := NewDestination("admin-a", 1), NewDestination("admin-b", 1)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(a, b)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.destinations = []*Destination{a, b}
+
+	req, _ := http.NewRequest("POST", "/admin/ring?action=remove&destination=admin-b", nil)
+	req.SetBasicAuth("foo", "foo")
+	recorder := httptest.NewRecorder()
+	server.serveAdminRing(recorder, req)
+
+	if recorder.Code != http.StatusOK || hashRing.Contains(b) {
+		t.Fatalf("admin-b wasn't removed: %d", recorder.Code)
+	}
+	entries := auditLog.Entries()
+	last := entries[len(entries)-1]
+	if last.Actor != "foo" || last.Action != "ring.remove" || len(last.After.([]string)) != 1 {
+		t.Errorf("Unexpected audit entry: %+v", last)
+	}
+}
+
+func TestAdminDebugToggle(t *testing.T) {
+	defer Debug.Set(Debug.On())
+	User, Password = "foo", "foo"
+	server := NewLumbermillServer(&http.Server{}, nil)
+
+	req, _ := http.NewRequest("POST", "/admin/debug?enabled=true", nil)
+	req.SetBasicAuth("foo", "foo")
+	server.serveAdminDebug(httptest.NewRecorder(), req)
+
+	if !Debug.On() {
+		t.Errorf("Debug wasn't turned on")
+	}
+	entries := auditLog.Entries()
+	if last := entries[len(entries)-1]; last.Action != "debug.set" || last.After != true {
+		t.Errorf("Unexpected audit entry: %+v", last)
+	}
+}
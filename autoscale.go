@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Compute per token throughput for autoscalers, over windows of
+	// AUTOSCALE_WINDOW, optionally POSTing them to AUTOSCALE_WEBHOOK_URL
+	AutoscaleSignals    = os.Getenv("AUTOSCALE_SIGNALS") == "true"
+	AutoscaleWindow     = parseDurationSetting("AUTOSCALE_WINDOW", os.Getenv("AUTOSCALE_WINDOW"), time.Minute)
+	AutoscaleWebhookURL = os.Getenv("AUTOSCALE_WEBHOOK_URL")
+
+	// Serve the same tallies, with error rates and dyno counts, at
+	// /aggregates/ for status pages
+	Aggregates = os.Getenv("AGGREGATES") == "true"
+
+	// Post the in-flight requests of each dyno type, estimated with Little's
+	// law (arrival rate times mean service time), to the dyno.concurrency
+	// series every AUTOSCALE_WINDOW
+	ConcurrencyEstimates = os.Getenv("DYNO_CONCURRENCY") == "true"
+
+	autoscaleWebhookErrorCounter = metrics.GetOrRegisterCounter("lumbermill.errors.autoscale.webhook", metrics.DefaultRegistry)
+
+	// Client for webhooks, so one that hangs can't stall the window
+	webhookClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// Number of service times kept per token and window to estimate the p95
+const autoscaleSampleSize = 1028
+
+// Throughput of a token over the last complete window
+type ThroughputSignal struct {
+	Token       string  `json:"token"`
+	Requests    int64   `json:"requests"`
+	RPS         float64 `json:"rps"`
+	P95Service  float64 `json:"p95_service_ms"`
+	ErrorRate   float64 `json:"error_rate"`   // Share of requests with a 5xx or an H code
+	Concurrency float64 `json:"concurrency"`  // Requests in flight, on average
+	Dynos       int     `json:"dynos"`        // Dynos routed to, or reporting runtime metrics
+	WindowStart int64   `json:"window_start"` // Unix time
+}
+
+type tokenThroughput struct {
+	requests     int64            // Routed requests, not counting H errors
+	errors       int64            // Routed requests with a 5xx
+	routerErrors int64            // Requests failing with an H code
+	service      metrics.Sample   // Microseconds
+	busy         map[string]int64 // Microseconds spent serving requests, per dyno type
+	dynos        map[string]bool
+}
+
+// Tallies router requests per token, publishing requests per second, p95
+// service time, error rate and dyno count once per window. The signals always describe the last
+// complete window, so they don't jump around as a window fills up.
+type Throughput struct {
+	sync.Mutex
+	window  time.Duration
+	start   time.Time
+	current map[string]*tokenThroughput
+	signals map[string]ThroughputSignal
+
+	concurrency bool    // Whether Roll estimates dyno concurrency
+	points      []Point // dyno.concurrency points of the last window
+}
+
+func NewThroughput(window time.Duration) *Throughput {
+	return &Throughput{
+		window:  window,
+		start:   time.Now(),
+		current: make(map[string]*tokenThroughput),
+		signals: make(map[string]ThroughputSignal),
+	}
+}
+
+// Records the router points of a batch. A nil Throughput records nothing.
+func (t *Throughput) Record(points []Point) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	for _, point := range points {
+		switch point.Type {
+		case Router:
+			tt := t.token(point.Token)
+			tt.requests++
+			if service, ok := routerDurationMicros(point.Points[2]); ok {
+				tt.service.Update(service)
+				if len(point.Points) > 4 {
+					if kind, ok := point.Points[4].(string); ok {
+						tt.busy[kind] += service
+					}
+				}
+			}
+			if status, ok := point.Points[1].(int); ok && status >= 500 {
+				tt.errors++
+			}
+		case EventsRouter:
+			tt := t.token(point.Token)
+			tt.routerErrors++
+			if len(point.Points) > 2 {
+				if dyno, ok := point.Points[2].(string); ok && dyno != "" {
+					tt.dynos[dyno] = true
+				}
+			}
+		case DynoMem, DynoLoad:
+			if source, ok := point.Points[1].(string); ok && !(OneOffExcludeAggregates && isOneOffDyno(source)) {
+				t.token(point.Token).dynos[source] = true
+			}
+		}
+	}
+}
+
+func (t *Throughput) token(token string) *tokenThroughput {
+	tt, found := t.current[token]
+	if !found {
+		tt = &tokenThroughput{service: metrics.NewUniformSample(autoscaleSampleSize), busy: make(map[string]int64), dynos: make(map[string]bool)}
+		t.current[token] = tt
+	}
+	return tt
+}
+
+// Ends the current window, replacing the published signals with its
+// tallies, and returns them.
+func (t *Throughput) Roll() []ThroughputSignal {
+	t.Lock()
+	defer t.Unlock()
+
+	signals := make(map[string]ThroughputSignal, len(t.current))
+	now := time.Now()
+	t.points = nil
+	for token, tt := range t.current {
+		signal := ThroughputSignal{
+			Token:       token,
+			Requests:    tt.requests,
+			RPS:         float64(tt.requests) / t.window.Seconds(),
+			P95Service:  tt.service.Percentile(0.95) / 1000,
+			Dynos:       len(tt.dynos),
+			WindowStart: t.start.Unix(),
+		}
+		if total := tt.requests + tt.routerErrors; total > 0 {
+			signal.ErrorRate = float64(tt.errors+tt.routerErrors) / float64(total)
+		}
+		for kind, busy := range tt.busy {
+			concurrency := float64(busy) / float64(t.window/time.Microsecond)
+			signal.Concurrency += concurrency
+			if t.concurrency {
+				t.points = append(t.points, concurrencyPoint(token, kind, concurrency, tt.dynos, now))
+			}
+		}
+		signals[token] = signal
+	}
+	t.signals = signals
+	t.current = make(map[string]*tokenThroughput)
+	t.start = now
+
+	return t.signalList()
+}
+
+// A dyno.concurrency point of a dyno type, sharing its requests in flight
+// between the dynos of the type seen in the window, if any
+func concurrencyPoint(token, kind string, concurrency float64, dynos map[string]bool, now time.Time) Point {
+	n := 0
+	for dyno := range dynos {
+		if dynoType(dyno) == kind {
+			n++
+		}
+	}
+	var perDyno interface{}
+	if n > 0 {
+		perDyno = concurrency / float64(n)
+	}
+	return Point{Token: token, Type: DynoConcurrency, Points: []interface{}{now.UnixNano() / int64(time.Microsecond), kind, concurrency, n, perDyno}}
+}
+
+// The dyno.concurrency points of the last window
+func (t *Throughput) ConcurrencyPoints() []Point {
+	t.Lock()
+	defer t.Unlock()
+	return t.points
+}
+
+// The signal of token for the last window, and whether it had any requests
+func (t *Throughput) Signal(token string) (ThroughputSignal, bool) {
+	t.Lock()
+	defer t.Unlock()
+	signal, found := t.signals[token]
+	return signal, found
+}
+
+// The signals of all tokens for the last window
+func (t *Throughput) Signals() []ThroughputSignal {
+	t.Lock()
+	defer t.Unlock()
+	return t.signalList()
+}
+
+func (t *Throughput) signalList() []ThroughputSignal {
+	signals := make([]ThroughputSignal, 0, len(t.signals))
+	for _, signal := range t.signals {
+		signals = append(signals, signal)
+	}
+	return signals
+}
+
+// Rolls the window every t.window, POSTing the signals to webhookURL when
+// one is given, and posting dyno concurrency estimates to routes when
+// they're on, until stop is closed.
+func (t *Throughput) Run(webhookURL string, routes *derivedRoutes, stop <-chan struct{}) {
+	ticker := time.NewTicker(t.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		signals := t.Roll()
+		for _, point := range t.ConcurrencyPoints() {
+			routes.Post(point)
+		}
+		if webhookURL != "" {
+			if err := postSignals(webhookURL, signals); err != nil {
+				autoscaleWebhookErrorCounter.Inc(1)
+				log.Printf("Error posting autoscale signals: %s\n", err)
+			}
+		}
+	}
+}
+
+func postSignals(url string, signals []ThroughputSignal) error {
+	body, err := json.Marshal(signals)
+	if err != nil {
+		return err
+	}
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Webhook returned (%d)", resp.StatusCode)
+	}
+	return nil
+}
+
+// A metric in the format HireFire's custom metric sources expect
+type hireFireMetric struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// GET /autoscale/<token>, or /autoscale/ for all tokens. Add ?format=hirefire
+// for a token's signals as HireFire style metrics.
+func (s *LumbermillServer) serveAutoscale(w http.ResponseWriter, r *http.Request) {
+	s.serveSignals(w, r, "/autoscale/")
+}
+
+// GET /aggregates/<token>, or /aggregates/ for all tokens
+func (s *LumbermillServer) serveAggregates(w http.ResponseWriter, r *http.Request) {
+	s.serveSignals(w, r, "/aggregates/")
+}
+
+func (s *LumbermillServer) serveSignals(w http.ResponseWriter, r *http.Request, prefix string) {
+	if err := s.checkAuth(r); err != nil {
+		writeError(w, r, http.StatusForbidden, errAuthFailed, err.Error())
+		authFailureCounter.Inc(1)
+		return
+	}
+
+	if s.throughput == nil {
+		writeError(w, r, http.StatusNotFound, errNotFound, "Autoscale signals and aggregates are disabled")
+		return
+	}
+
+	var response interface{}
+	token := strings.TrimPrefix(r.URL.Path, prefix)
+	if token == "" {
+		response = s.throughput.Signals()
+	} else {
+		// Tokens without requests in the last window are idle, not unknown
+		signal, found := s.throughput.Signal(token)
+		if !found {
+			signal = ThroughputSignal{Token: token}
+		}
+		response = signal
+		if prefix == "/autoscale/" && r.URL.Query().Get("format") == "hirefire" {
+			response = []hireFireMetric{{"rps", signal.RPS}, {"p95_service_ms", signal.P95Service}}
+		}
+	}
+
+	writeJSON(w, response)
+}
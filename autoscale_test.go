@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestThroughputRoll(t *testing.T) {
+	throughput := NewThroughput(10 * time.Second)
+	points := make([]Point, 0)
+	for i := 1; i <= 100; i++ {
+		points = append(points, Point{Token: "t.a", Type: Router, Points: []interface{}{int64(i), 200, i, 0, "web"}})
+	}
+	points = append(points, Point{Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(1), "H12"}})
+	throughput.Record(points)
+
+	if _, found := throughput.Signal("t.a"); found {
+		t.Errorf("Signals were published before the window ended")
+	}
+
+	throughput.Roll()
+	signal, found := throughput.Signal("t.a")
+	if !found {
+		t.Fatal("No signal for t.a")
+	}
+	if signal.Requests != 100 || signal.RPS != 10 {
+		t.Errorf("Expected 100 requests at 10 rps, got %d at %f", signal.Requests, signal.RPS)
+	}
+	if signal.P95Service < 95 || signal.P95Service > 96 {
+		t.Errorf("Unexpected p95: %f", signal.P95Service)
+	}
+}
+
+func TestThroughputConcurrency(t *testing.T) {
+	defer func(unit string) { RouterDurationUnit = unit }(RouterDurationUnit)
+	RouterDurationUnit = "ms"
+	throughput := NewThroughput(10 * time.Second)
+	throughput.concurrency = true
+
+	// 100 requests of 300ms over 10s: 3 in flight, over 2 web dynos
+	points := []Point{
+		{Token: "t.a", Type: DynoLoad, Points: []interface{}{int64(1), "web.1"}},
+		{Token: "t.a", Type: DynoLoad, Points: []interface{}{int64(1), "web.2"}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 500, 0, "worker"}},
+	}
+	for i := 0; i < 100; i++ {
+		points = append(points, Point{Token: "t.a", Type: Router, Points: []interface{}{int64(i), 200, 300, 0, "web"}})
+	}
+	throughput.Record(points)
+	throughput.Roll()
+
+	if signal, _ := throughput.Signal("t.a"); signal.Concurrency < 3.04 || signal.Concurrency > 3.06 {
+		t.Errorf("Expected 3.05 requests in flight, got %f", signal.Concurrency)
+	}
+	byType := make(map[string][]interface{})
+	for _, point := range throughput.ConcurrencyPoints() {
+		if point.Type != DynoConcurrency {
+			t.Errorf("Unexpected point: %v", point)
+		}
+		byType[point.Points[1].(string)] = point.Points
+	}
+	if web := byType["web"]; web == nil || web[2] != 3.0 || web[3] != 2 || web[4] != 1.5 {
+		t.Errorf("Expected 3 web requests in flight over 2 dynos, got %v", web)
+	}
+	if worker := byType["worker"]; worker == nil || worker[3] != 0 || worker[4] != nil {
+		t.Errorf("Expected no per dyno estimate without worker dynos, got %v", worker)
+	}
+}
+
+func TestThroughputExcludesOneOffDynos(t *testing.T) {
+	defer func(exclude bool) { OneOffExcludeAggregates = exclude }(OneOffExcludeAggregates)
+	points := []Point{
+		{Token: "t.a", Type: DynoMem, Points: []interface{}{int64(1), "web.1"}},
+		{Token: "t.a", Type: DynoMem, Points: []interface{}{int64(1), "scheduler.4821"}},
+		{Token: "t.a", Type: DynoLoad, Points: []interface{}{int64(1), "run.7731"}},
+	}
+	for exclude, dynos := range map[bool]int{false: 3, true: 1} {
+		OneOffExcludeAggregates = exclude
+		throughput := NewThroughput(time.Second)
+		throughput.Record(points)
+		throughput.Roll()
+		if signal, _ := throughput.Signal("t.a"); signal.Dynos != dynos {
+			t.Errorf("Expected %d dynos when excluding one-offs is %t, got %d", dynos, exclude, signal.Dynos)
+		}
+	}
+}
+
+func TestServeAutoscaleHireFire(t *testing.T) {
+	User = "foo"
+	Password = "foo"
+	server := NewLumbermillServer(&http.Server{}, nil)
+	server.throughput = NewThroughput(time.Second)
+	server.throughput.Record([]Point{{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10, 0, "web"}}})
+	server.throughput.Roll()
+
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/autoscale/t.a?format=hirefire", nil)
+	req.SetBasicAuth("foo", "foo")
+	server.serveAutoscale(recorder, req)
+
+	var metrics []hireFireMetric
+	if err := json.Unmarshal(recorder.Body.Bytes(), &metrics); err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 2 || metrics[0].Name != "rps" || metrics[0].Value != 1 {
+		t.Errorf("Unexpected metrics: %+v", metrics)
+	}
+}
+
+func TestServeAutoscaleDisabled(t *testing.T) {
+	User = "foo"
+	Password = "foo"
+	server := NewLumbermillServer(&http.Server{}, nil)
+
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/autoscale/t.a", nil)
+	req.SetBasicAuth("foo", "foo")
+	server.serveAutoscale(recorder, req)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatal("Wrong Response Code: ", recorder.Code)
+	}
+	assertErrorCode(t, recorder, errNotFound)
+}
+
+func TestServeAggregates(t *testing.T) {
+	User = "foo"
+	Password = "foo"
+	server := NewLumbermillServer(&http.Server{}, nil)
+	server.throughput = NewThroughput(time.Minute)
+	server.throughput.Record([]Point{
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10, 0, "web"}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(2), 503, 10, 0, "web"}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(3), 200, 10, 0, "web"}},
+		{Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(4), "H12", "web.2", "/", "web", false}},
+		{Token: "t.a", Type: DynoMem, Points: []interface{}{int64(5), "web.1", 0.0, 0, 0, 0.0, 0.0, 100.0, "web"}},
+		{Token: "t.a", Type: DynoLoad, Points: []interface{}{int64(5), "web.2", 0.1, 0.1, 0.1, "web"}},
+	})
+	server.throughput.Roll()
+
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/aggregates/t.a", nil)
+	req.SetBasicAuth("foo", "foo")
+	server.http.Handler.ServeHTTP(recorder, req)
+
+	var signal ThroughputSignal
+	if err := json.Unmarshal(recorder.Body.Bytes(), &signal); err != nil {
+		t.Fatal(err)
+	}
+	if signal.Requests != 3 || signal.ErrorRate != 0.5 || signal.Dynos != 2 {
+		t.Errorf("Expected 3 requests, half of all failing, on 2 dynos, got %+v", signal)
+	}
+}
+
+func TestPostSignalsTimesOut(t *testing.T) {
+	hung := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hung
+	}))
+	defer server.Close()
+	defer close(hung)
+
+	defer func(client *http.Client) { webhookClient = client }(webhookClient)
+	webhookClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+	if err := postSignals(server.URL, []ThroughputSignal{{Token: "t.a"}}); err == nil {
+		t.Errorf("Expected a hung webhook to time out")
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Watch each token's router connect times for sustained elevation, the
+	// first sign of requests queueing for busy dynos. A window of
+	// BACKPRESSURE_WINDOW (1m) is elevated when its p95 connect time is at
+	// least BACKPRESSURE_CONNECT_MS (50) and BACKPRESSURE_FACTOR (3) times
+	// the token's baseline. BACKPRESSURE_WINDOWS (3) elevated windows in a
+	// row start a backpressure event, and a normal or idle window ends it,
+	// both posted to the events.backpressure series. The baseline starts as
+	// the lowest p95 of a token's first BACKPRESSURE_WARMUP (5) windows, so
+	// a token first seen while backed up doesn't take that as normal.
+	BackpressureDetection = os.Getenv("BACKPRESSURE_DETECTION") == "true"
+	BackpressureWindow    = parseDurationSetting("BACKPRESSURE_WINDOW", os.Getenv("BACKPRESSURE_WINDOW"), time.Minute)
+	BackpressureConnectMs = parseFloatSetting("BACKPRESSURE_CONNECT_MS", os.Getenv("BACKPRESSURE_CONNECT_MS"), 50)
+	BackpressureFactor    = parseFloatSetting("BACKPRESSURE_FACTOR", os.Getenv("BACKPRESSURE_FACTOR"), 3)
+	BackpressureWindows   = parseIntSetting("BACKPRESSURE_WINDOWS", os.Getenv("BACKPRESSURE_WINDOWS"), 3)
+	BackpressureWarmup    = parseIntSetting("BACKPRESSURE_WARMUP", os.Getenv("BACKPRESSURE_WARMUP"), 5)
+
+	backpressureEventsCounter = metrics.GetOrRegisterCounter("lumbermill.backpressure.events", metrics.DefaultRegistry)
+	backpressureActiveGauge   = metrics.GetOrRegisterGauge("lumbermill.backpressure.active", metrics.DefaultRegistry)
+)
+
+const (
+	// Weight of a normal window's p95 connect time in the baseline
+	backpressureBaselineWeight = 0.1
+
+	// Connect times kept per token and window to estimate the p95
+	backpressureSampleSize = 1028
+)
+
+type tokenBackpressure struct {
+	connect  metrics.Sample // Microseconds, of the current window
+	baseline float64        // Milliseconds
+	warmup   int            // Windows the baseline was warmed up with
+	elevated int            // Elevated windows in a row
+	active   bool           // Whether an event is under way
+}
+
+// Sustained router connect time elevation per token
+type Backpressure struct {
+	sync.Mutex
+	tokens map[string]*tokenBackpressure
+}
+
+func NewBackpressure() *Backpressure {
+	return &Backpressure{tokens: make(map[string]*tokenBackpressure)}
+}
+
+// Records the router points of a batch. A nil Backpressure records nothing.
+func (b *Backpressure) Record(points []Point) {
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	for _, point := range points {
+		if point.Type != Router || len(point.Points) < 4 {
+			continue
+		}
+		connect, ok := routerDurationMicros(point.Points[3])
+		if !ok {
+			continue
+		}
+		tb := b.tokens[point.Token]
+		if tb == nil {
+			tb = &tokenBackpressure{connect: metrics.NewUniformSample(backpressureSampleSize)}
+			b.tokens[point.Token] = tb
+		}
+		tb.connect.Update(connect)
+	}
+}
+
+// Ends the current window, returning the events.backpressure points of the
+// events that started or ended with it
+func (b *Backpressure) Roll(now time.Time) []Point {
+	b.Lock()
+	defer b.Unlock()
+
+	timestamp := now.UnixNano() / int64(time.Microsecond)
+	var points []Point
+	active := 0
+	for token, tb := range b.tokens {
+		if tb.connect.Count() == 0 {
+			// Idle tokens are forgotten, ending their event if they were
+			// backed up, as there's no telling when they'll be back
+			if tb.active {
+				log.Printf("at=backpressure_end token=%s idle=true baseline_ms=%.1f windows=%d\n", token, tb.baseline, tb.elevated)
+				points = append(points, Point{Token: token, Type: EventsBackpressure, Points: []interface{}{timestamp, "end", nil, tb.baseline, tb.elevated}})
+			}
+			delete(b.tokens, token)
+			continue
+		}
+		p95 := tb.connect.Percentile(0.95) / 1000
+		tb.connect.Clear()
+
+		switch {
+		case tb.warmup < BackpressureWarmup:
+			if tb.warmup == 0 || p95 < tb.baseline {
+				tb.baseline = p95
+			}
+			tb.warmup++
+		case p95 >= BackpressureConnectMs && p95 >= BackpressureFactor*tb.baseline:
+			tb.elevated++
+			if !tb.active && tb.elevated >= BackpressureWindows {
+				tb.active = true
+				backpressureEventsCounter.Inc(1)
+				log.Printf("at=backpressure_start token=%s connect_p95_ms=%.1f baseline_ms=%.1f\n", token, p95, tb.baseline)
+				points = append(points, Point{Token: token, Type: EventsBackpressure, Points: []interface{}{timestamp, "start", p95, tb.baseline, tb.elevated}})
+			}
+		default:
+			if tb.active {
+				tb.active = false
+				log.Printf("at=backpressure_end token=%s connect_p95_ms=%.1f baseline_ms=%.1f windows=%d\n", token, p95, tb.baseline, tb.elevated)
+				points = append(points, Point{Token: token, Type: EventsBackpressure, Points: []interface{}{timestamp, "end", p95, tb.baseline, tb.elevated}})
+			}
+			tb.elevated = 0
+			tb.baseline += backpressureBaselineWeight * (p95 - tb.baseline)
+		}
+		if tb.active {
+			active++
+		}
+	}
+	backpressureActiveGauge.Update(int64(active))
+	return points
+}
+
+// Rolls the window every so often, posting the events, until stop is closed
+func (b *Backpressure) Run(routes *derivedRoutes, every time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		for _, point := range b.Roll(time.Now()) {
+			routes.Post(point)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackpressureEvents(t *testing.T) {
+	defer func(unit string) { RouterDurationUnit = unit }(RouterDurationUnit)
+	RouterDurationUnit = "ms"
+	defer func(warmup int) { BackpressureWarmup = warmup }(BackpressureWarmup)
+	BackpressureWarmup = 2
+	backpressure := NewBackpressure()
+	now := time.Unix(1500000000, 0)
+	window := func(connect int) []Point {
+		backpressure.Record([]Point{
+			{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10, connect, "web"}},
+			{Token: "t.b", Type: Router, Points: []interface{}{int64(1), 200, 10, 1, "web"}},
+		})
+		return backpressure.Roll(now)
+	}
+
+	for _, connect := range []int{2, 2, 200, 200} {
+		if points := window(connect); len(points) != 0 {
+			t.Fatalf("Expected no event before %d elevated windows, got %v", BackpressureWindows, points)
+		}
+	}
+	points := window(200)
+	if len(points) != 1 || points[0].Token != "t.a" || points[0].Type != EventsBackpressure || points[0].Points[1] != "start" {
+		t.Fatalf("Expected a backpressure start event, got %v", points)
+	}
+	if points := window(300); len(points) != 0 {
+		t.Errorf("Expected a single start event, got %v", points)
+	}
+	points = window(3)
+	if len(points) != 1 || points[0].Points[1] != "end" || points[0].Points[4] != 4 {
+		t.Errorf("Expected an end event after 4 windows, got %v", points)
+	}
+
+	// Slow but steady connect times become the baseline
+	backpressure = NewBackpressure()
+	for i := 0; i < 5; i++ {
+		if points := window(60); len(points) != 0 {
+			t.Errorf("Expected steady connect times not to be backpressure, got %v", points)
+		}
+	}
+}
+
+func TestBackpressureWarmupAndIdle(t *testing.T) {
+	defer func(unit string) { RouterDurationUnit = unit }(RouterDurationUnit)
+	RouterDurationUnit = "ms"
+	defer func(warmup int) { BackpressureWarmup = warmup }(BackpressureWarmup)
+	BackpressureWarmup = 4
+	backpressure := NewBackpressure()
+	now := time.Unix(1500000000, 0)
+	window := func(connect int) []Point {
+		backpressure.Record([]Point{{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10, connect, "web"}}})
+		return backpressure.Roll(now)
+	}
+
+	// A token first seen while backed up gets the baseline of its quieter
+	// warmup windows
+	for _, connect := range []int{200, 200, 2, 2} {
+		if points := window(connect); len(points) != 0 {
+			t.Fatalf("Expected no event while warming up, got %v", points)
+		}
+	}
+	for i := 0; i < BackpressureWindows-1; i++ {
+		window(200)
+	}
+	points := window(200)
+	if len(points) != 1 || points[0].Points[1] != "start" || points[0].Points[3] != 2.0 {
+		t.Fatalf("Expected a start event against the warmed up baseline, got %v", points)
+	}
+
+	// A backed up token going idle ends its event and is forgotten
+	points = backpressure.Roll(now)
+	if len(points) != 1 || points[0].Points[1] != "end" || points[0].Points[2] != nil {
+		t.Errorf("Expected an end event when the token went idle, got %v", points)
+	}
+	if len(backpressure.tokens) != 0 || backpressureActiveGauge.Value() != 0 {
+		t.Errorf("Expected the idle token to be forgotten")
+	}
+}
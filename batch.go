@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// Points of batches dropped without being offered to a destination, as
+// their drain request was canceled or lumbermill ran out of time shutting
+// down
+var canceledPointsCounter = metrics.GetOrRegisterCounter("lumbermill.points.canceled", metrics.DefaultRegistry)
+
+// Points parsed from a single drain request. They are held until the whole
+// body has been read, so they can be discarded if reading it fails part way.
+type pointBatch struct {
+	points []Point
+}
+
+func (b *pointBatch) PostPoint(point Point) {
+	b.points = append(b.points, point)
+}
+
+// Hands the points to the destinations of their tokens. Most batches carry
+// a single token, so destinations are only looked up when the token changes.
+// When ctx is done while waiting for room, the rest are dropped and ctx's
+// error returned.
+func (b *pointBatch) Flush(ctx context.Context, hashRing *HashRing) error {
+	defer b.Discard()
+	var token string
+	var destination *Destination
+	for i, point := range b.points {
+		if i == 0 || point.Token != token {
+			token, destination = point.Token, hashRing.Get(point.Token)
+		}
+		if destination == nil {
+			acks.Fail(point.RequestId)
+			continue
+		}
+		if err := destination.PostPointContext(ctx, point); err != nil {
+			canceledPointsCounter.Inc(int64(len(b.points) - i - 1))
+			return err
+		}
+	}
+	return nil
+}
+
+// Labels the points the drain request parsed, rather than e.g. dyno error
+// summaries of earlier requests, with its ack id, returning how many
+func (b *pointBatch) Relabel(requestId, ackId string) int {
+	n := 0
+	for i := range b.points {
+		if b.points[i].RequestId == requestId {
+			b.points[i].RequestId = ackId
+			n++
+		}
+	}
+	return n
+}
+
+// Whether any of the points are for a destination in maintenance
+func (b *pointBatch) InMaintenance(hashRing *HashRing) bool {
+	var token string
+	for i, point := range b.points {
+		if i == 0 || point.Token != token {
+			token = point.Token
+			if destination := hashRing.Get(token); destination != nil && destination.Maintenance.On() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Drops the points, returning how many there were
+func (b *pointBatch) Discard() int {
+	n := len(b.points)
+	b.points = b.points[:0]
+	return n
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Stream points into BigQuery tables of this project and dataset instead
+	// of delivering them to InfluxDB. Each series has its own table, named
+	// BIGQUERY_TABLE_PREFIX and the series' name with dots as underscores
+	// (e.g. "events_router_status"), which must exist already. Points with a
+	// series prefix, e.g. a vhost tenant's, go to tables of their own.
+	BigQueryProject     = os.Getenv("BIGQUERY_PROJECT")
+	BigQueryDataset     = os.Getenv("BIGQUERY_DATASET")
+	BigQueryTablePrefix = os.Getenv("BIGQUERY_TABLE_PREFIX")
+	BigQueryURL         = stringSetting(os.Getenv("BIGQUERY_URL"), "https://bigquery.googleapis.com/bigquery/v2")
+
+	// OAuth access token for the API, which should be rotated through the
+	// secrets provider. The GCE metadata server's is used when unset.
+	BigQueryAccessToken = os.Getenv("BIGQUERY_ACCESS_TOKEN")
+	gceMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+	// Rows per insert request, how long points wait for a full batch, and
+	// how often a failed request is tried again before its points fail
+	BigQueryBatchSize     = parseIntSetting("BIGQUERY_BATCH_SIZE", os.Getenv("BIGQUERY_BATCH_SIZE"), 500)
+	BigQueryBatchInterval = parseDurationSetting("BIGQUERY_BATCH_INTERVAL", os.Getenv("BIGQUERY_BATCH_INTERVAL"), time.Second)
+	BigQueryRetries       = parseIntSetting("BIGQUERY_RETRIES", os.Getenv("BIGQUERY_RETRIES"), 3)
+	bigQueryRetryDelay    = time.Second
+)
+
+// A row of a streaming insert
+type bigQueryRow struct {
+	InsertId string                 `json:"insertId"`
+	JSON     map[string]interface{} `json:"json"`
+}
+
+type bigQueryInsert struct {
+	SkipInvalidRows bool          `json:"skipInvalidRows"`
+	Rows            []bigQueryRow `json:"rows"`
+}
+
+// Rows BigQuery rejected, by index in the request
+type bigQueryInsertResponse struct {
+	InsertErrors []struct {
+		Index  int `json:"index"`
+		Errors []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"insertErrors"`
+}
+
+// The table of a point's series, its prefix included
+func bigQueryTable(point Point) string {
+	return BigQueryTablePrefix + strings.Replace(point.Prefix+point.Type.Name(), ".", "_", -1)
+}
+
+// A point as row index of an insert into its series' table, with its token.
+// Times are microseconds, which BigQuery takes as seconds.
+func bigQueryRowFor(point Point, index int) bigQueryRow {
+	row := make(map[string]interface{}, len(point.Points)+1)
+	row["token"] = point.Token
+	for i, column := range point.Type.Columns() {
+		if i >= len(point.Points) {
+			break
+		}
+		if v, ok := point.Points[i].(int64); ok && column == "time" {
+			row[column] = float64(v) / 1e6
+		} else {
+			row[column] = point.Points[i]
+		}
+	}
+
+	// Ids BigQuery drops duplicate rows by, when requests are tried again.
+	// The request and index keep identical points of a request apart.
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%v\x00%s\x00%d", point.Token, point.Prefix, point.Type.Name(), point.Points, point.RequestId, index)
+	return bigQueryRow{InsertId: fmt.Sprintf("%x", h.Sum64()), JSON: row}
+}
+
+// Streams points into BigQuery, a table per series
+type BigQueryPoster struct {
+	destination   *Destination
+	project       string
+	dataset       string
+	client        *http.Client
+	pointsCounter metrics.Counter
+	errorsCounter metrics.Counter
+	retryCounter  metrics.Counter
+	rejectCounter metrics.Counter
+	waitGroup     *sync.WaitGroup
+	ctx           context.Context // Retries stop when it's done
+	token         string          // The metadata server's, until tokenExpires
+	tokenExpires  time.Time
+}
+
+func NewBigQueryPoster(project, dataset string, destination *Destination, waitGroup *sync.WaitGroup) *BigQueryPoster {
+	waitGroup.Add(1)
+	return &BigQueryPoster{
+		destination:   destination,
+		project:       project,
+		dataset:       dataset,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		pointsCounter: metrics.GetOrRegisterCounter("lumbermill.poster.bigquery.points", metrics.DefaultRegistry),
+		errorsCounter: metrics.GetOrRegisterCounter("lumbermill.poster.bigquery.errors", metrics.DefaultRegistry),
+		retryCounter:  metrics.GetOrRegisterCounter("lumbermill.poster.bigquery.retries", metrics.DefaultRegistry),
+		rejectCounter: metrics.GetOrRegisterCounter("lumbermill.poster.bigquery.rejected", metrics.DefaultRegistry),
+		waitGroup:     waitGroup,
+		ctx:           context.Background(),
+	}
+}
+
+// The API's access token: BIGQUERY_ACCESS_TOKEN, or the metadata server's,
+// which is kept until a minute before it expires
+func (p *BigQueryPoster) accessToken() (string, error) {
+	if token := secrets.Get("BIGQUERY_ACCESS_TOKEN", BigQueryAccessToken); token != "" {
+		return token, nil
+	}
+	if p.token != "" && time.Now().Before(p.tokenExpires) {
+		return p.token, nil
+	}
+	req, err := http.NewRequest("GET", gceMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %d for an access token", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	p.token = token.AccessToken
+	p.tokenExpires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
+
+// Inserts rows into table once, returning the indexes of rejected rows.
+// Errors of requests worth trying again are retryable.
+func (p *BigQueryPoster) insert(table string, rows []bigQueryRow) (rejected map[int]string, retryable bool, err error) {
+	body, err := json.Marshal(bigQueryInsert{SkipInvalidRows: true, Rows: rows})
+	if err != nil {
+		return nil, false, err
+	}
+	token, err := p.accessToken()
+	if err != nil {
+		return nil, true, err
+	}
+	url := fmt.Sprintf("%s/projects/%s/datasets/%s/tables/%s/insertAll", BigQueryURL, p.project, p.dataset, table)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := p.client.Do(req.WithContext(p.ctx))
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		// Revoked before it expired: the next try gets a new one
+		p.token = ""
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("BigQuery returned %d for %s", resp.StatusCode, table)
+		return nil, resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 || resp.StatusCode == http.StatusUnauthorized, err
+	}
+
+	var response bigQueryInsertResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, true, err
+	}
+	for _, insertError := range response.InsertErrors {
+		if rejected == nil {
+			rejected = make(map[int]string)
+		}
+		for _, e := range insertError.Errors {
+			rejected[insertError.Index] = e.Reason + ": " + e.Message
+		}
+	}
+	return rejected, false, nil
+}
+
+// Writes the points of a table, trying failed requests again with backoff.
+// Drain requests learn whether their points were inserted.
+func (p *BigQueryPoster) write(table string, points []Point) {
+	rows := make([]bigQueryRow, len(points))
+	for i, point := range points {
+		rows[i] = bigQueryRowFor(point, i)
+	}
+
+	delay := bigQueryRetryDelay
+	rejected, retryable, err := p.insert(table, rows)
+	for attempt := 0; err != nil && retryable && attempt < BigQueryRetries && p.ctx.Err() == nil; attempt++ {
+		p.retryCounter.Inc(1)
+		time.Sleep(delay)
+		delay *= 2
+		rejected, retryable, err = p.insert(table, rows)
+	}
+
+	if err != nil {
+		p.errorsCounter.Inc(1)
+		log.Printf("at=bigquery_error table=%s points=%d err=%q\n", table, len(points), err)
+		for _, point := range points {
+			p.destination.fail(point.RequestId)
+		}
+		return
+	}
+	for i, point := range points {
+		if reason, ok := rejected[i]; ok {
+			p.rejectCounter.Inc(1)
+			log.Printf("at=bigquery_rejected table=%s request_id=%s err=%q\n", table, point.RequestId, reason)
+			p.destination.fail(point.RequestId)
+			continue
+		}
+		p.pointsCounter.Inc(1)
+		p.destination.ack(point.RequestId, 1)
+	}
+}
+
+// Writes the batch of each table, emptying them
+func (p *BigQueryPoster) flush(batches map[string][]Point) {
+	for table, points := range batches {
+		if len(points) > 0 {
+			p.write(table, points)
+			batches[table] = points[:0]
+		}
+	}
+}
+
+func (p *BigQueryPoster) Run() {
+	defer p.waitGroup.Done()
+
+	batches := make(map[string][]Point)
+	flush := time.NewTicker(BigQueryBatchInterval)
+	defer flush.Stop()
+
+	for {
+		select {
+		case point, open := <-p.destination.points:
+			if !open {
+				p.flush(batches)
+				return
+			}
+			table := bigQueryTable(point)
+			batches[table] = append(batches[table], point)
+			if len(batches[table]) >= BigQueryBatchSize {
+				p.write(table, batches[table])
+				batches[table] = batches[table][:0]
+			}
+		case <-flush.C:
+			p.flush(batches)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBigQueryPoster(t *testing.T) {
+	var requests int
+	var inserts []bigQueryInsert
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer bq-token" || r.URL.Path != "/projects/p/datasets/d/tables/lm_events_router_status/insertAll" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var insert bigQueryInsert
+		json.NewDecoder(r.Body).Decode(&insert)
+		inserts = append(inserts, insert)
+		w.Write([]byte(`{"insertErrors":[{"index":1,"errors":[{"reason":"invalid","message":"no such field"}]}]}`))
+	}))
+	defer api.Close()
+	defer func(url, token, prefix string, delay time.Duration) {
+		BigQueryURL, BigQueryAccessToken, BigQueryTablePrefix, bigQueryRetryDelay = url, token, prefix, delay
+	}(BigQueryURL, BigQueryAccessToken, BigQueryTablePrefix, bigQueryRetryDelay)
+	BigQueryURL, BigQueryAccessToken, BigQueryTablePrefix, bigQueryRetryDelay = api.URL, "bq-token", "lm_", time.Millisecond
+
+	destination := NewDestination("bigquery-test", 10)
+	poster := NewBigQueryPoster("p", "d", destination, new(sync.WaitGroup))
+	points, rejected, retries := poster.pointsCounter.Count(), poster.rejectCounter.Count(), poster.retryCounter.Count()
+	destination.PostPoint(Point{Token: "t.a", Type: EventsRouterStatus, Points: []interface{}{int64(1425579721500000), 503, "web.1", "/", "web", nil}})
+	destination.PostPoint(Point{Token: "t.b", Type: EventsRouterStatus, Points: []interface{}{int64(1425579722000000), 500, "web.2", "/", "web", nil}})
+	destination.Close()
+	poster.Run()
+
+	if requests != 2 || poster.retryCounter.Count() != retries+1 {
+		t.Fatalf("Expected the unavailable request to be tried again, got %d requests", requests)
+	}
+	if len(inserts) != 1 || len(inserts[0].Rows) != 2 || !inserts[0].SkipInvalidRows {
+		t.Fatalf("Expected both points in one insert, got %v", inserts)
+	}
+	row := inserts[0].Rows[0]
+	if row.JSON["token"] != "t.a" || row.JSON["time"] != 1425579721.5 || row.JSON["status"] != float64(503) || row.InsertId == "" || row.InsertId == inserts[0].Rows[1].InsertId {
+		t.Errorf("Unexpected row: %v", row)
+	}
+	if poster.pointsCounter.Count() != points+1 || poster.rejectCounter.Count() != rejected+1 {
+		t.Errorf("Expected a point inserted and one rejected")
+	}
+}
+
+func TestBigQueryTablePrefixedSeries(t *testing.T) {
+	defer func(prefix string) { BigQueryTablePrefix = prefix }(BigQueryTablePrefix)
+	BigQueryTablePrefix = "lm_"
+
+	point := Point{Token: "t.a", Type: EventsRouterStatus, Points: []interface{}{int64(1), 503, "web.1", "/", "web", nil}}
+	prefixed := point
+	prefixed.Prefix = "staging."
+	if table := bigQueryTable(prefixed); table != "lm_staging_events_router_status" {
+		t.Errorf("Expected a prefixed series to have its own table, got %s", table)
+	}
+	if table := bigQueryTable(point); table != "lm_events_router_status" {
+		t.Errorf("Expected an unprefixed series to keep its table, got %s", table)
+	}
+	if bigQueryRowFor(prefixed, 0).InsertId == bigQueryRowFor(point, 0).InsertId {
+		t.Errorf("Expected the prefix to be part of the insert id")
+	}
+}
+
+func TestBigQueryMetadataTokenCached(t *testing.T) {
+	var tokens int
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		w.Write([]byte(`{"access_token":"md-token","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	defer metadata.Close()
+	var ids []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer md-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var insert bigQueryInsert
+		json.NewDecoder(r.Body).Decode(&insert)
+		for _, row := range insert.Rows {
+			ids = append(ids, row.InsertId)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	defer func(url, token, metadataURL string) {
+		BigQueryURL, BigQueryAccessToken, gceMetadataTokenURL = url, token, metadataURL
+	}(BigQueryURL, BigQueryAccessToken, gceMetadataTokenURL)
+	BigQueryURL, BigQueryAccessToken, gceMetadataTokenURL = api.URL, "", metadata.URL
+
+	poster := NewBigQueryPoster("p", "d", NewDestination("bigquery-token-test", 10), new(sync.WaitGroup))
+	point := Point{Token: "t.a", Type: EventsRouterStatus, Points: []interface{}{int64(1425579721500000), 503, "web.1", "/", "web", nil}, RequestId: "req-1"}
+	poster.write("events_router_status", []Point{point, point})
+	poster.write("events_router_status", []Point{point})
+
+	if tokens != 1 {
+		t.Errorf("Expected the metadata server's token to be kept, got %d tokens", tokens)
+	}
+	if len(ids) != 3 || ids[0] == ids[1] {
+		t.Errorf("Expected identical points of a request to have their own insert ids, got %v", ids)
+	}
+}
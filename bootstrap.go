@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Create the database, its retention and downsampling continuous queries
+	// on every InfluxDB host at startup, so fresh hosts are usable right away
+	InfluxDBBootstrap = os.Getenv("INFLUXDB_BOOTSTRAP") == "true"
+
+	// Retention of the lumbermill shard space (e.g. "30d"), and the duration
+	// of its shards
+	InfluxDBRetention     = os.Getenv("INFLUXDB_RETENTION")
+	InfluxDBShardDuration = os.Getenv("INFLUXDB_SHARD_DURATION")
+
+	// Interval to downsample series to with continuous queries (e.g. "1h"),
+	// into series prefixed with it. More continuous queries can be given,
+	// separated by ";".
+	InfluxDBDownsample        = os.Getenv("INFLUXDB_DOWNSAMPLE")
+	InfluxDBContinuousQueries = os.Getenv("INFLUXDB_CONTINUOUS_QUERIES")
+
+	// Cluster admin creating databases and shard spaces, when the usual
+	// credentials can't
+	InfluxDBAdminUser     = os.Getenv("INFLUXDB_ADMIN_USER")
+	InfluxDBAdminPassword = os.Getenv("INFLUXDB_ADMIN_PWD")
+
+	bootstrapErrorCounter = metrics.GetOrRegisterCounter("lumbermill.errors.influxdb.bootstrap", metrics.DefaultRegistry)
+
+	// Hosts are bootstrapped one at a time, as destinations sharing a host
+	// would otherwise race to create the same database
+	bootstrapMu sync.Mutex
+)
+
+// Name of the shard space holding retention for lumbermill's series
+const bootstrapShardSpace = "lumbermill"
+
+// What to create on a host
+type bootstrapConfig struct {
+	retention     string
+	shardDuration string
+	queries       []string
+}
+
+func bootstrapConfigFromEnv() bootstrapConfig {
+	config := bootstrapConfig{retention: InfluxDBRetention, shardDuration: InfluxDBShardDuration}
+	if config.shardDuration == "" {
+		config.shardDuration = "7d"
+	}
+	if InfluxDBDownsample != "" {
+		config.queries = append(config.queries, downsampleQueries(InfluxDBDownsample)...)
+	}
+	for _, query := range strings.Split(InfluxDBContinuousQueries, ";") {
+		if query = strings.TrimSpace(query); query != "" {
+			config.queries = append(config.queries, query)
+		}
+	}
+	return config
+}
+
+// Continuous queries rolling the router and dyno series up to interval
+func downsampleQueries(interval string) []string {
+	return []string{
+		fmt.Sprintf(`select mean(service) as service, mean(connect) as connect, count(status) as requests from /^router\..*/ group by time(%s), dynoType into %s.:series_name`, interval, interval),
+		fmt.Sprintf(`select count(code) as count from /^events\..*/ group by time(%s), code into %s.:series_name`, interval, interval),
+		fmt.Sprintf(`select mean(memory_total) as memory_total, mean(memory_rss) as memory_rss from /^dyno\.mem\..*/ group by time(%s), source into %s.:series_name`, interval, interval),
+		fmt.Sprintf(`select mean(load_avg_1m) as load_avg_1m from /^dyno\.load\..*/ group by time(%s), source into %s.:series_name`, interval, interval),
+	}
+}
+
+// Bootstraps the writer's host, logging failures. Delivery is attempted
+// either way, as the host may have been set up by hand, so it's run in the
+// background rather than holding up startup.
+func bootstrapHost(w *seriesWriter) {
+	bootstrapMu.Lock()
+	defer bootstrapMu.Unlock()
+	if err := w.Bootstrap(bootstrapConfigFromEnv()); err != nil {
+		bootstrapErrorCounter.Inc(1)
+		log.Printf("Error bootstrapping %s: %s\n", w.config.Host, err)
+	}
+}
+
+// Creates the database, shard space and continuous queries that don't
+// exist yet. It's safe to run against a host that's already set up.
+func (w *seriesWriter) Bootstrap(config bootstrapConfig) error {
+	db := w.config.Database
+
+	var databases []struct{ Name string }
+	if err := w.api("GET", "/db", nil, nil, &databases); err != nil {
+		return err
+	}
+	found := false
+	for _, database := range databases {
+		found = found || database.Name == db
+	}
+	if !found {
+		log.Printf("Creating database %s on %s\n", db, w.config.Host)
+		if err := w.api("POST", "/db", nil, map[string]string{"name": db}, nil); err != nil {
+			return err
+		}
+	}
+
+	if config.retention != "" {
+		var spaces []struct{ Name, Database string }
+		if err := w.api("GET", "/cluster/shard_spaces", nil, nil, &spaces); err != nil {
+			return err
+		}
+		found = false
+		for _, space := range spaces {
+			found = found || (space.Database == db && space.Name == bootstrapShardSpace)
+		}
+		if !found {
+			log.Printf("Creating shard space %s (%s) on %s\n", bootstrapShardSpace, config.retention, w.config.Host)
+			space := map[string]interface{}{
+				"name":              bootstrapShardSpace,
+				"regex":             "/.*/",
+				"retentionPolicy":   config.retention,
+				"shardDuration":     config.shardDuration,
+				"replicationFactor": 1,
+				"split":             1,
+			}
+			if err := w.api("POST", "/cluster/shard_spaces/"+db, nil, space, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(config.queries) == 0 {
+		return nil
+	}
+
+	var existing []struct{ Points [][]interface{} }
+	if err := w.api("GET", "/db/"+db+"/series", url.Values{"q": {"list continuous queries"}}, nil, &existing); err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, series := range existing {
+		for _, point := range series.Points {
+			if len(point) > 2 {
+				known[normalizeQuery(fmt.Sprint(point[2]))] = true
+			}
+		}
+	}
+	for _, query := range config.queries {
+		if known[normalizeQuery(query)] {
+			continue
+		}
+		log.Printf("Creating continuous query on %s: %s\n", w.config.Host, query)
+		if err := w.api("GET", "/db/"+db+"/series", url.Values{"q": {query}}, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InfluxDB doesn't list continuous queries exactly as they were given
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// Calls the InfluxDB 0.8 HTTP API, decoding the response into out when it's
+// not nil. Calls outside of the database are made as the cluster admin, if
+// there is one.
+func (w *seriesWriter) api(method, path string, query url.Values, in, out interface{}) error {
+	scheme := "http"
+	if w.config.IsSecure {
+		scheme = "https"
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	user, password := secrets.Get("INFLUXDB_USER", w.config.Username), secrets.Get("INFLUXDB_PWD", w.config.Password)
+	if admin := secrets.Get("INFLUXDB_ADMIN_USER", InfluxDBAdminUser); admin != "" && !strings.HasPrefix(path, "/db/") {
+		user, password = admin, secrets.Get("INFLUXDB_ADMIN_PWD", InfluxDBAdminPassword)
+	}
+	query.Set("u", user)
+	query.Set("p", password)
+
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s://%s%s?%s", scheme, w.config.Host, path, query.Encode()), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.config.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Server returned (%d): %s", resp.StatusCode, string(respBody))
+	}
+	if out != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, out)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	influx "github.com/influxdb/influxdb-go"
+)
+
+// A bare bones InfluxDB 0.8 admin API, recording the changes made to it
+type fakeInfluxAdmin struct {
+	sync.Mutex
+	databases []string
+	spaces    []string
+	queries   []string
+}
+
+func (f *fakeInfluxAdmin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	var in map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&in)
+
+	switch {
+	case r.URL.Path == "/db" && r.Method == "GET":
+		out := make([]map[string]string, 0)
+		for _, db := range f.databases {
+			out = append(out, map[string]string{"name": db})
+		}
+		json.NewEncoder(w).Encode(out)
+	case r.URL.Path == "/db":
+		f.databases = append(f.databases, in["name"].(string))
+	case r.URL.Path == "/cluster/shard_spaces":
+		out := make([]map[string]string, 0)
+		for _, space := range f.spaces {
+			out = append(out, map[string]string{"name": space, "database": "test"})
+		}
+		json.NewEncoder(w).Encode(out)
+	case strings.HasPrefix(r.URL.Path, "/cluster/shard_spaces/"):
+		f.spaces = append(f.spaces, in["name"].(string))
+	case r.URL.Query().Get("q") == "list continuous queries":
+		points := make([][]interface{}, 0)
+		for i, query := range f.queries {
+			points = append(points, []interface{}{0, i, query})
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{{"name": "continuous queries", "points": points}})
+	default:
+		f.queries = append(f.queries, r.URL.Query().Get("q"))
+	}
+}
+
+func TestBootstrapIsIdempotent(t *testing.T) {
+	admin := new(fakeInfluxAdmin)
+	server := httptest.NewServer(admin)
+	defer server.Close()
+
+	writer := newSeriesWriter(influx.ClientConfig{Host: strings.TrimPrefix(server.URL, "http://"), Database: "test"}, "bootstrap-test", "")
+	config := bootstrapConfig{retention: "30d", shardDuration: "7d", queries: downsampleQueries("1h")}
+
+	for i := 0; i < 2; i++ {
+		if err := writer.Bootstrap(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(admin.databases) != 1 || admin.databases[0] != "test" {
+		t.Errorf("Unexpected databases: %v", admin.databases)
+	}
+	if len(admin.spaces) != 1 || admin.spaces[0] != bootstrapShardSpace {
+		t.Errorf("Unexpected shard spaces: %v", admin.spaces)
+	}
+	if len(admin.queries) != len(config.queries) {
+		t.Errorf("Expected %d continuous queries, got %v", len(config.queries), admin.queries)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Drain CANARY_RATE (10) synthetic router lines for this token every
+	// CANARY_INTERVAL (10s) through the whole pipeline, and check that as
+	// many points reach InfluxDB, for a continuous end to end signal.
+	CanaryToken    = os.Getenv("CANARY_TOKEN")
+	CanaryRate     = parseIntSetting("CANARY_RATE", os.Getenv("CANARY_RATE"), 10)
+	CanaryInterval = parseDurationSetting("CANARY_INTERVAL", os.Getenv("CANARY_INTERVAL"), 10*time.Second)
+
+	canary *Canary
+
+	canarySentCounter      = metrics.GetOrRegisterCounter("lumbermill.canary.sent", metrics.DefaultRegistry)
+	canaryDeliveredCounter = metrics.GetOrRegisterCounter("lumbermill.canary.delivered", metrics.DefaultRegistry)
+	canaryRejectedCounter  = metrics.GetOrRegisterCounter("lumbermill.canary.rejected", metrics.DefaultRegistry)
+	canaryMissingGauge     = metrics.GetOrRegisterGauge("lumbermill.canary.missing", metrics.DefaultRegistry)
+)
+
+// Synthetic batches for the canary token, and how many of their points were
+// delivered
+type Canary struct {
+	sync.Mutex
+	token   string
+	server  *LumbermillServer
+	seq     int
+	batches int
+	pending map[string]*canaryBatch // By the batch's request id
+}
+
+// A batch sent, and how many of its router points have been delivered
+type canaryBatch struct {
+	sent      int
+	delivered int
+	checked   bool // Sent before the previous check, so delivered by now
+}
+
+func NewCanary(token string, server *LumbermillServer) *Canary {
+	return &Canary{token: token, server: server, pending: make(map[string]*canaryBatch)}
+}
+
+// Whether the point is one of the canary's router points. Points derived
+// from them by aggregators share the token, but aren't counted.
+func (c *Canary) Owns(point Point) bool {
+	return c != nil && point.Type == Router && point.Token == c.token
+}
+
+// Counts n points of the canary batch with this request id delivered. A nil
+// Canary counts nothing, as do batches already checked.
+func (c *Canary) Delivered(requestId string, n int) {
+	if c == nil || n == 0 {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	canaryDeliveredCounter.Inc(int64(n))
+	if batch, found := c.pending[pointRequestId(requestId)]; found {
+		batch.delivered += n
+	}
+}
+
+// Drains a batch of lines lines for the canary token, as logplex would
+func (c *Canary) Send(lines int) {
+	c.Lock()
+	c.batches++
+	requestId := fmt.Sprintf("canary-%d", c.batches)
+	now := time.Now().UTC().Format("2006-01-02T15:04:05.000000+00:00")
+	var body strings.Builder
+	for i := 0; i < lines; i++ {
+		c.seq++
+		line := fmt.Sprintf("<158>1 %s lumbermill heroku router - at=info method=GET path=\"/canary\" host=canary dyno=web.1 connect=1ms service=%dms status=200 bytes=0 request_id=canary-%d", now, c.seq%100, c.seq)
+		body.WriteString(strconv.Itoa(len(line)) + " " + line)
+	}
+
+	// Posters may deliver the points before serveDrain returns, or, at
+	// least once, always do
+	c.pending[requestId] = &canaryBatch{sent: lines}
+	c.Unlock()
+
+	req, _ := http.NewRequest("POST", DrainPath, strings.NewReader(body.String()))
+	req = req.WithContext(context.WithValue(req.Context(), canaryRequestKey{}, true))
+	req.Header.Set("Content-Type", "application/logplex-1")
+	req.Header.Set("Logplex-Msg-Count", strconv.Itoa(lines))
+	req.Header.Set("Logplex-Frame-Id", newRequestId())
+	req.Header.Set(requestIdHeader, requestId)
+	req.Header.Set("Logplex-Drain-Token", c.token)
+	resp := &canaryResponse{header: make(http.Header), status: http.StatusOK}
+	c.server.serveDrain(resp, req)
+
+	c.Lock()
+	defer c.Unlock()
+	if resp.status >= 300 {
+		delete(c.pending, requestId)
+		canaryRejectedCounter.Inc(1)
+		log.Printf("at=canary_rejected status=%d\n", resp.status)
+		return
+	}
+	canarySentCounter.Inc(int64(lines))
+}
+
+// Marks the canary's own drain requests, which can't come from outside
+type canaryRequestKey struct{}
+
+// Whether r is one of the canary's drain requests. They're made in process,
+// so needn't authenticate, but may only send lines for the canary token.
+func canaryRequest(r *http.Request) bool {
+	fromCanary, _ := r.Context().Value(canaryRequestKey{}).(bool)
+	return fromCanary
+}
+
+// Checks that the points of batches sent before the previous check were all
+// delivered, returning how many are missing. Each batch is checked once, so
+// points delivered later, or twice, can't make up for those lost.
+func (c *Canary) Check() int64 {
+	c.Lock()
+	defer c.Unlock()
+	var sent, missing int64
+	for id, batch := range c.pending {
+		if !batch.checked {
+			batch.checked = true
+			continue
+		}
+		sent += int64(batch.sent)
+		if batch.delivered < batch.sent {
+			missing += int64(batch.sent - batch.delivered)
+		}
+		delete(c.pending, id)
+	}
+	canaryMissingGauge.Update(missing)
+	if missing > 0 {
+		log.Printf("at=canary_mismatch sent=%d missing=%d\n", sent, missing)
+	}
+	return missing
+}
+
+// Checks and sends a batch every so often
+func (c *Canary) Run(every time.Duration, lines int) {
+	for {
+		time.Sleep(every)
+		c.Check()
+		c.Send(lines)
+	}
+}
+
+// Records the status serveDrain answers the canary with
+type canaryResponse struct {
+	header http.Header
+	status int
+}
+
+func (r *canaryResponse) Header() http.Header         { return r.header }
+func (r *canaryResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (r *canaryResponse) WriteHeader(status int)      { r.status = status }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestCanaryCountsDeliveredPoints(t *testing.T) {
+	destination := NewDestination("canary-test", 100)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	poster := NewPoster(newTestWriter(db, ""), "canary-test", destination, new(sync.WaitGroup))
+
+	defer func(c *Canary) { canary = c }(canary)
+	canary = NewCanary("t.canary", server)
+	deliver := func() {
+		d := newDelivery()
+		for len(destination.points) > 0 {
+			d.add(<-destination.points)
+		}
+		poster.deliver(d)
+	}
+
+	canary.Send(5)
+	deliver()
+	canary.Check()
+	if missing := canary.Check(); missing != 0 || len(db.Points("router.t.canary")) != 5 {
+		t.Errorf("Expected all 5 canary points to be delivered, %d missing", missing)
+	}
+
+	canary.Send(3)
+	for len(destination.points) > 0 {
+		<-destination.points
+	}
+	canary.Check()
+	if missing := canary.Check(); missing != 3 {
+		t.Errorf("Expected 3 lost canary points to be missing, got %d", missing)
+	}
+
+	// A batch delivered twice doesn't hide one that was lost
+	canary.Send(4)
+	points := make([]Point, 0, 4)
+	for len(destination.points) > 0 {
+		points = append(points, <-destination.points)
+	}
+	canary.Send(4)
+	for len(destination.points) > 0 {
+		<-destination.points
+	}
+	for i := 0; i < 2; i++ {
+		d := newDelivery()
+		for _, point := range points {
+			d.add(point)
+		}
+		poster.deliver(d)
+	}
+	canary.Check()
+	if missing := canary.Check(); missing != 4 {
+		t.Errorf("Expected the lost batch's 4 points to be missing, got %d", missing)
+	}
+}
+
+func TestCanaryOwnsOnlyRouterPoints(t *testing.T) {
+	c := NewCanary("t.canary", nil)
+	if !c.Owns(Point{Token: "t.canary", Type: Router}) {
+		t.Error("Expected the canary to own its router points")
+	}
+	if c.Owns(Point{Token: "t.canary", Type: EventsRouterStatus}) {
+		t.Error("Expected the canary not to own points derived from its router points")
+	}
+}
+
+func TestCanaryAtLeastOnceWithAllowlists(t *testing.T) {
+	defer func(tokens map[string]bool, allowed map[string]map[string]bool) {
+		AtLeastOnceTokens, UserAllowedTokens = tokens, allowed
+	}(AtLeastOnceTokens, UserAllowedTokens)
+	AtLeastOnceTokens = parseSet("t.canary")
+	UserAllowedTokens = parseAllowedTokens("tenant=t.a")
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	destination := NewDestination("canary-acks-test", 100)
+	defer destination.Close()
+	go NewPoster(newTestWriter(db, ""), "canary-acks-test", destination, new(sync.WaitGroup)).Run()
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+
+	defer func(c *Canary) { canary = c }(canary)
+	canary = NewCanary("t.canary", NewLumbermillServer(&http.Server{}, hashRing))
+	rejected := canaryRejectedCounter.Count()
+	canary.Send(5)
+	if canaryRejectedCounter.Count() != rejected || db.PointCount() != 5 {
+		t.Fatalf("Expected the canary's batch to be accepted despite the allowlists, got %d points", db.PointCount())
+	}
+	canary.Check()
+	if missing := canary.Check(); missing != 0 {
+		t.Errorf("Expected the points delivered while the batch waited to count, %d missing", missing)
+	}
+}
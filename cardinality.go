@@ -0,0 +1,206 @@
+package main
+
+import (
+	"hash/fnv"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Count the distinct series and tag combinations each token writes per
+	// hour, alerting when a token reaches CARDINALITY_ALERT_THRESHOLD (1000)
+	// combinations and CARDINALITY_GROWTH_FACTOR (10) times its count of the
+	// previous hour
+	CardinalityMonitoring     = os.Getenv("CARDINALITY_MONITORING") == "true"
+	CardinalityAlertThreshold = parseIntSetting("CARDINALITY_ALERT_THRESHOLD", os.Getenv("CARDINALITY_ALERT_THRESHOLD"), 1000)
+	CardinalityGrowthFactor   = parseFloatSetting("CARDINALITY_GROWTH_FACTOR", os.Getenv("CARDINALITY_GROWTH_FACTOR"), 10)
+
+	// Columns identifying what a point is about, rather than measuring it
+	seriesTagColumns = [][]string{
+		{"dynoType"},               // Router
+		{"code", "dyno", "path"},   // EventsRouter
+		{"source"},                 // DynoMem
+		{"source"},                 // DynoLoad
+		{"what", "code"},           // EventsDyno
+		{"status", "dyno", "path"}, // EventsRouterStatus
+		{"code"},                   // LogplexHealth
+		{"pattern", "dynoType"},    // LogPatterns
+		{},                         // SLO
+		{"dynoType"},               // DynoConcurrency
+		{"state"},                  // EventsBackpressure
+		{"dynoType", "state"},      // OneOffDyno
+		{"dynoType"},               // DynoType
+	}
+	seriesTagIndexes = columnIndexes(seriesTagColumns)
+
+	cardinalityAlertCounter = metrics.GetOrRegisterCounter("lumbermill.cardinality.alerts", metrics.DefaultRegistry)
+	cardinalityMaxGauge     = metrics.GetOrRegisterGauge("lumbermill.cardinality.max", metrics.DefaultRegistry)
+)
+
+const (
+	cardinalityWindow = time.Hour
+
+	// Combinations remembered per token and window, so one exploding token
+	// can't exhaust memory. Counts stop there.
+	cardinalityMaxCombinations = 100000
+)
+
+func columnIndexes(names [][]string) [][]int {
+	indexes := make([][]int, numSeries)
+	for st := SeriesType(0); st < numSeries; st++ {
+		for _, name := range names[st] {
+			for i, column := range st.Columns() {
+				if column == name {
+					indexes[st] = append(indexes[st], i)
+				}
+			}
+		}
+	}
+	return indexes
+}
+
+// Hashes the series and tag values of a point
+func tagCombination(point Point) uint64 {
+	h := fnv.New64a()
+	var buf [20]byte
+	h.Write(append(buf[:0], byte(point.Type)))
+	for _, i := range seriesTagIndexes[point.Type] {
+		if i >= len(point.Points) {
+			continue
+		}
+		switch v := point.Points[i].(type) {
+		case string:
+			h.Write([]byte(v))
+		case int:
+			h.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+		}
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// Distinct combinations of a token in the current and previous windows
+type TokenCardinality struct {
+	Token    string `json:"token"`
+	Current  int    `json:"current"`
+	Previous int    `json:"previous"`
+}
+
+type tokenCombinations struct {
+	seen    map[uint64]struct{}
+	alerted bool
+}
+
+// Distinct (series, tag combination) counts per token and hour, to catch a
+// token exploding cardinality, e.g. after it starts tagging paths, before
+// the storage tier suffers.
+type Cardinality struct {
+	sync.Mutex
+	current  map[string]*tokenCombinations
+	previous map[string]int
+}
+
+func NewCardinality() *Cardinality {
+	return &Cardinality{current: make(map[string]*tokenCombinations), previous: make(map[string]int)}
+}
+
+// Records the points of a batch. A nil Cardinality records nothing.
+func (c *Cardinality) Record(points []Point) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	var token string
+	var combinations *tokenCombinations
+	for i, point := range points {
+		if i == 0 || point.Token != token {
+			token = point.Token
+			combinations = c.current[token]
+			if combinations == nil {
+				combinations = &tokenCombinations{seen: make(map[uint64]struct{})}
+				c.current[token] = combinations
+			}
+		}
+		if len(combinations.seen) >= cardinalityMaxCombinations {
+			continue
+		}
+		combinations.seen[tagCombination(point)] = struct{}{}
+
+		n := len(combinations.seen)
+		if !combinations.alerted && n >= CardinalityAlertThreshold && float64(n) >= CardinalityGrowthFactor*float64(c.previous[token]) {
+			combinations.alerted = true
+			cardinalityAlertCounter.Inc(1)
+			log.Printf("at=cardinality_alert token=%s combinations=%d previous=%d\n", token, n, c.previous[token])
+		}
+	}
+}
+
+// Ends the current window
+func (c *Cardinality) Roll() {
+	c.Lock()
+	defer c.Unlock()
+	previous := make(map[string]int, len(c.current))
+	max := 0
+	for token, combinations := range c.current {
+		previous[token] = len(combinations.seen)
+		if len(combinations.seen) > max {
+			max = len(combinations.seen)
+		}
+	}
+	c.previous = previous
+	c.current = make(map[string]*tokenCombinations)
+	cardinalityMaxGauge.Update(int64(max))
+}
+
+// Counts of every token seen in either window, highest current count first
+func (c *Cardinality) Counts() []TokenCardinality {
+	c.Lock()
+	defer c.Unlock()
+	counts := make(map[string]*TokenCardinality)
+	for token, n := range c.previous {
+		counts[token] = &TokenCardinality{Token: token, Previous: n}
+	}
+	for token, combinations := range c.current {
+		if counts[token] == nil {
+			counts[token] = &TokenCardinality{Token: token}
+		}
+		counts[token].Current = len(combinations.seen)
+	}
+
+	list := make([]TokenCardinality, 0, len(counts))
+	for _, count := range counts {
+		list = append(list, *count)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Current > list[j].Current })
+	return list
+}
+
+// Rolls the window every so often
+func (c *Cardinality) Run(every time.Duration) {
+	for {
+		time.Sleep(every)
+		c.Roll()
+	}
+}
+
+// GET /admin/cardinality, the distinct combinations of each token this hour
+// and last
+func (s *LumbermillServer) serveAdminCardinality(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.adminActor(w, r, ReadOnly); !ok {
+		return
+	}
+	if s.cardinality == nil {
+		writeError(w, r, http.StatusNotFound, errNotFound, "Cardinality monitoring is disabled")
+		return
+	}
+	writeJSON(w, s.cardinality.Counts())
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCardinality(t *testing.T) {
+	c := NewCardinality()
+	routerError := func(token, path string) Point {
+		return Point{Token: token, Type: EventsRouter, Points: []interface{}{int64(0), "H12", "web.1", path, "web", false}}
+	}
+
+	// A steady hour, the same paths over and over
+	for i := 0; i < 2000; i++ {
+		c.Record([]Point{routerError("t.steady", fmt.Sprintf("/%d", i%10)), routerError("t.exploding", "/")})
+	}
+	c.Roll()
+
+	alerts := cardinalityAlertCounter.Count()
+	for i := 0; i < 2000; i++ {
+		c.Record([]Point{routerError("t.steady", fmt.Sprintf("/%d", i%10)), routerError("t.exploding", fmt.Sprintf("/users/%d", i))})
+	}
+	if n := cardinalityAlertCounter.Count() - alerts; n != 1 {
+		t.Errorf("Expected one alert, for t.exploding, got %d", n)
+	}
+
+	counts := c.Counts()
+	if len(counts) != 2 || counts[0] != (TokenCardinality{"t.exploding", 2000, 1}) || counts[1] != (TokenCardinality{"t.steady", 10, 10}) {
+		t.Errorf("Unexpected counts: %+v", counts)
+	}
+}
+
+func TestTagCombination(t *testing.T) {
+	a := Point{Token: "t.test", Type: Router, Points: []interface{}{int64(1), 200, 10, 1, "web"}}
+	b := Point{Token: "t.test", Type: Router, Points: []interface{}{int64(2), 503, 30, 2, "web"}}
+	c := Point{Token: "t.test", Type: Router, Points: []interface{}{int64(1), 200, 10, 1, "worker"}}
+	if tagCombination(a) != tagCombination(b) {
+		t.Errorf("Expected measurements not to count towards cardinality")
+	}
+	if tagCombination(a) == tagCombination(c) {
+		t.Errorf("Expected tags to count towards cardinality")
+	}
+}
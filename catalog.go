@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Tag the points of CATALOG_SERIES (error events by default) with the
+	// team, service tier and PagerDuty service a catalog service knows each
+	// token by, fetched as JSON from CATALOG_URL/<token> and cached for
+	// CATALOG_TTL, so alerts can be routed downstream. CATALOG_FETCHERS
+	// entries are fetched at a time.
+	CatalogURL      = os.Getenv("CATALOG_URL")
+	CatalogTTL      = parseDurationSetting("CATALOG_TTL", os.Getenv("CATALOG_TTL"), 10*time.Minute)
+	CatalogSeries   = parseSet(stringSetting(os.Getenv("CATALOG_SERIES"), "events.router,events.dyno,events.router.status"))
+	CatalogFetchers = parseIntSetting("CATALOG_FETCHERS", os.Getenv("CATALOG_FETCHERS"), 4)
+
+	catalog *Catalog
+
+	catalogColumns = []string{"team", "tier", "pagerduty_service"}
+
+	catalogHitsCounter    = metrics.GetOrRegisterCounter("lumbermill.catalog.hits", metrics.DefaultRegistry)
+	catalogMissesCounter  = metrics.GetOrRegisterCounter("lumbermill.catalog.misses", metrics.DefaultRegistry)
+	catalogErrorsCounter  = metrics.GetOrRegisterCounter("lumbermill.catalog.errors", metrics.DefaultRegistry)
+	catalogDroppedCounter = metrics.GetOrRegisterCounter("lumbermill.catalog.dropped", metrics.DefaultRegistry)
+)
+
+// Tokens waiting to be fetched. Lookups finding it full leave their token
+// for the next lookup rather than wait.
+const catalogQueueSize = 1024
+
+// What the catalog knows about a token
+type catalogEntry struct {
+	Team             string `json:"team"`
+	Tier             string `json:"tier"`
+	PagerDutyService string `json:"pagerduty_service"`
+}
+
+type cachedCatalogEntry struct {
+	values  []interface{} // nil when the catalog doesn't know the token
+	fetched time.Time
+}
+
+// Tags of tokens from a catalog service. Lookups never wait for it: a token
+// not cached yet, or cached too long ago, is fetched in the background by
+// one of a fixed number of fetchers while its points go without, or with
+// the tags it had.
+type Catalog struct {
+	sync.Mutex
+	url      string
+	ttl      time.Duration
+	client   *http.Client
+	entries  map[string]*cachedCatalogEntry
+	fetching map[string]bool
+	queue    chan string
+}
+
+func NewCatalog(url string, ttl time.Duration, fetchers int) *Catalog {
+	if fetchers < 1 {
+		log.Printf("Invalid number of catalog fetchers (%d), using 1\n", fetchers)
+		fetchers = 1
+	}
+	c := &Catalog{
+		url:      url,
+		ttl:      ttl,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		entries:  make(map[string]*cachedCatalogEntry),
+		fetching: make(map[string]bool),
+		queue:    make(chan string, catalogQueueSize),
+	}
+	for i := 0; i < fetchers; i++ {
+		go c.fetchQueued()
+	}
+	return c
+}
+
+// The values of catalogColumns for points of token in st, nil without
+// any. A nil Catalog has none.
+func (c *Catalog) Tags(token string, st SeriesType) []interface{} {
+	if c == nil || !CatalogSeries[st.Name()] {
+		return nil
+	}
+	c.Lock()
+	defer c.Unlock()
+	entry := c.entries[token]
+	if entry == nil || time.Since(entry.fetched) > c.ttl {
+		if !c.fetching[token] {
+			select {
+			case c.queue <- token:
+				c.fetching[token] = true
+			default:
+				catalogDroppedCounter.Inc(1)
+			}
+		}
+	}
+	if entry == nil {
+		catalogMissesCounter.Inc(1)
+		return nil
+	}
+	catalogHitsCounter.Inc(1)
+	return entry.values
+}
+
+// Fetches the tokens queued, one at a time
+func (c *Catalog) fetchQueued() {
+	for token := range c.queue {
+		c.fetch(token)
+	}
+}
+
+// Fetches token's entry into the cache. An entry that can't be fetched
+// is tried again on the next lookup, keeping the one cached.
+func (c *Catalog) fetch(token string) {
+	values, err := c.get(token)
+	c.Lock()
+	defer c.Unlock()
+	delete(c.fetching, token)
+	if err != nil {
+		catalogErrorsCounter.Inc(1)
+		log.Printf("at=catalog_error token=%s err=%q\n", token, err)
+		return
+	}
+	c.entries[token] = &cachedCatalogEntry{values: values, fetched: time.Now()}
+}
+
+func (c *Catalog) get(token string) ([]interface{}, error) {
+	resp, err := c.client.Get(c.url + "/" + url.PathEscape(token))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("catalog returned %d", resp.StatusCode)
+	}
+	var entry catalogEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return []interface{}{entry.Team, entry.Tier, entry.PagerDutyService}, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCatalogTags(t *testing.T) {
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tokens/t.shop" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"team":"checkout","tier":"1","pagerduty_service":"PSHOP"}`))
+	}))
+	defer service.Close()
+	defer func(c *Catalog) { catalog = c }(catalog)
+	catalog = NewCatalog(service.URL+"/tokens", time.Minute, 1)
+
+	// The first lookups fetch the entries in the background
+	if catalog.Tags("t.shop", EventsRouter) != nil || catalog.Tags("t.other", EventsRouter) != nil {
+		t.Fatalf("Expected no tags before the entries are fetched")
+	}
+	fetched := func() int {
+		catalog.Lock()
+		defer catalog.Unlock()
+		return len(catalog.entries)
+	}
+	for i := 0; i < 100 && fetched() < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	expected := []interface{}{"checkout", "1", "PSHOP"}
+	if tags := catalog.Tags("t.shop", EventsRouter); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tags)
+	}
+	if catalog.Tags("t.other", EventsRouter) != nil || catalog.Tags("t.shop", Router) != nil {
+		t.Errorf("Expected no tags for unknown tokens, or series that aren't tagged")
+	}
+
+	d := newDelivery()
+	d.add(Point{Token: "t.shop", Type: EventsRouter, Points: []interface{}{int64(1), "H12", "web.1", "/", "web", false, ""}})
+	d.add(Point{Token: "t.shop", Type: Router, Points: []interface{}{int64(1), 200, 10, 1, "web", "", "", ""}})
+	events := d.series["events.router.t.shop"]
+	if columns := events.Columns; columns[len(columns)-1] != "pagerduty_service" || len(events.Points[0]) != len(columns) {
+		t.Errorf("Expected the events to be tagged, got %v %v", columns, events.Points[0])
+	}
+	if len(seriesColumns[EventsRouter]) == len(events.Columns) {
+		t.Errorf("Expected the series' columns not to be changed")
+	}
+	if router := d.series["router.t.shop"]; len(router.Columns) != len(seriesColumns[Router]) {
+		t.Errorf("Expected router points not to be tagged")
+	}
+}
+
+func TestCatalogFetchersBounded(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, most, served int
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		served++
+		mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer service.Close()
+	c := NewCatalog(service.URL, time.Minute, 2)
+
+	for i := 0; i < 20; i++ {
+		c.Tags(fmt.Sprintf("t.bounded%d", i), EventsRouter)
+	}
+	done := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return served == 20
+	}
+	for i := 0; i < 200 && !done(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !done() {
+		t.Fatalf("Expected every token to be fetched")
+	}
+	if most > 2 {
+		t.Errorf("Expected at most 2 fetches at a time, got %d", most)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/tls"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Serves a client certificate for mutual TLS, reloading it when the
+// certificate or key file changes so certificates can be rotated without a
+// restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	sync.RWMutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	c := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Loads the certificate if either file changed since it was last loaded.
+// Until both files of a rotation are written, the pair doesn't match and
+// the previous certificate is kept.
+func (c *certReloader) reload() error {
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	c.RLock()
+	unchanged := c.cert != nil && certInfo.ModTime().Equal(c.certModTime) && keyInfo.ModTime().Equal(c.keyModTime)
+	c.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	c.cert = &cert
+	c.certModTime = certInfo.ModTime()
+	c.keyModTime = keyInfo.ModTime()
+	c.Unlock()
+	return nil
+}
+
+// Check for a rotated certificate every so often
+func (c *certReloader) Watch(every time.Duration) {
+	for {
+		time.Sleep(every)
+		if err := c.reload(); err != nil {
+			log.Printf("Error reloading client certificate %s: %s\n", c.certFile, err)
+		}
+	}
+}
+
+func (c *certReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cert, nil
+}
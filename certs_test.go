@@ -0,0 +1,117 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Writes a self signed certificate for cn to certFile and keyFile
+func writeTestCert(t *testing.T, cn, certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	if err := ioutil.WriteFile(certFile, certPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, keyPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func certCommonName(t *testing.T, c *certReloader) string {
+	cert, err := c.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestCertReloaderRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumbermill-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	writeTestCert(t, "first", certFile, keyFile)
+
+	certs, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cn := certCommonName(t, certs); cn != "first" {
+		t.Fatalf("Expected first certificate, got %q", cn)
+	}
+
+	writeTestCert(t, "second", certFile, keyFile)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(certFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := certs.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if cn := certCommonName(t, certs); cn != "second" {
+		t.Fatalf("Expected rotated certificate, got %q", cn)
+	}
+
+	// A rotation whose key is written last: the certificate alone doesn't
+	// match the old key, and is picked up once the key changes too
+	third := filepath.Join(dir, "third.key")
+	writeTestCert(t, "third", certFile, third)
+	later = later.Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	if err := certs.reload(); err == nil {
+		t.Fatalf("Expected the certificate not to match the old key")
+	}
+	if cn := certCommonName(t, certs); cn != "second" {
+		t.Fatalf("Expected the previous certificate to be kept, got %q", cn)
+	}
+	if err := os.Rename(third, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	later = later.Add(time.Minute)
+	os.Chtimes(keyFile, later, later)
+	if err := certs.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if cn := certCommonName(t, certs); cn != "third" {
+		t.Fatalf("Expected the certificate to be reloaded with its key, got %q", cn)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// What a column's values are written as
+type ColumnType string
+
+const (
+	ColumnInt      ColumnType = "int"
+	ColumnFloat    ColumnType = "float"
+	ColumnString   ColumnType = "string"
+	ColumnBool     ColumnType = "bool"
+	ColumnDuration ColumnType = "duration" // Milliseconds, as an int
+)
+
+var (
+	// Types of columns as "<series>.<column>=<type>,...", e.g.
+	// "router.status=int,events.dyno.code=string". Values are coerced to
+	// them, so a column never ends up with mixed types in InfluxDB. Values
+	// that can't be are nulled, or their point dropped when
+	// COLUMN_VALIDATION_POLICY is "drop".
+	columnTypes             = parseColumnTypes(os.Getenv("COLUMN_TYPES"))
+	ColumnValidationPolicy  = os.Getenv("COLUMN_VALIDATION_POLICY")
+	columnValidationCounter = metrics.GetOrRegisterCounter("lumbermill.errors.column.validation", metrics.DefaultRegistry)
+	columnValidationDropped = metrics.GetOrRegisterCounter("lumbermill.points.column.validation.dropped", metrics.DefaultRegistry)
+)
+
+// Parses COLUMN_TYPES into the type of each column of each series, "" for
+// columns left alone
+func parseColumnTypes(list string) [][]ColumnType {
+	declared := parseKeyValueList(list)
+	if len(declared) == 0 {
+		return nil
+	}
+
+	types := make([][]ColumnType, numSeries)
+	for key, t := range declared {
+		dot := strings.LastIndex(key, ".")
+		if dot == -1 {
+			log.Printf("Invalid column type declaration (%q), expected <series>.<column>\n", key)
+			continue
+		}
+		switch ColumnType(t) {
+		case ColumnInt, ColumnFloat, ColumnString, ColumnBool, ColumnDuration:
+		default:
+			log.Printf("Unknown column type (%q) for %s\n", t, key)
+			continue
+		}
+
+		found := false
+		for st := SeriesType(0); st < numSeries; st++ {
+			if st.Name() != key[:dot] {
+				continue
+			}
+			for i, column := range st.Columns() {
+				// Timestamps are always microseconds
+				if column == key[dot+1:] && column != "time" {
+					if types[st] == nil {
+						types[st] = make([]ColumnType, len(st.Columns()))
+					}
+					types[st][i] = ColumnType(t)
+					found = true
+				}
+			}
+		}
+		if !found {
+			log.Printf("Unknown column (%q) in column types\n", key)
+		}
+	}
+	return types
+}
+
+// Coerces the values of the batch's points to their columns' types
+func (b *pointBatch) Coerce() {
+	if columnTypes == nil {
+		return
+	}
+	kept := b.points[:0]
+	for _, point := range b.points {
+		if coercePoint(point) || ColumnValidationPolicy != "drop" {
+			kept = append(kept, point)
+		} else {
+			columnValidationDropped.Inc(1)
+		}
+	}
+	b.points = kept
+}
+
+// Coerces point's values in place, nulling those that can't be. Returns
+// whether all could.
+func coercePoint(point Point) bool {
+	types := columnTypes[point.Type]
+	valid := true
+	for i, t := range types {
+		if t == "" || i >= len(point.Points) || point.Points[i] == nil {
+			continue
+		}
+		v, ok := coerceValue(point.Points[i], t)
+		if !ok {
+			columnValidationCounter.Inc(1)
+			valid = false
+		}
+		point.Points[i] = v
+	}
+	return valid
+}
+
+// Converts v to t, returning nil and false when it can't be
+func coerceValue(v interface{}, t ColumnType) (interface{}, bool) {
+	switch t {
+	case ColumnInt:
+		switch v := v.(type) {
+		case int:
+			return v, true
+		case int64:
+			return int(v), true
+		case float64:
+			if v == math.Trunc(v) {
+				return int(v), true
+			}
+		case string:
+			if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return i, true
+			}
+		}
+
+	case ColumnFloat:
+		switch v := v.(type) {
+		case int:
+			return float64(v), true
+		case int64:
+			return float64(v), true
+		case float64:
+			return v, true
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, true
+			}
+		}
+
+	case ColumnString:
+		switch v := v.(type) {
+		case string:
+			return v, true
+		case int:
+			return strconv.Itoa(v), true
+		case int64:
+			return strconv.FormatInt(v, 10), true
+		case float64:
+			return strconv.FormatFloat(v, 'g', -1, 64), true
+		case bool:
+			return strconv.FormatBool(v), true
+		}
+
+	case ColumnBool:
+		switch v := v.(type) {
+		case bool:
+			return v, true
+		case int:
+			if v == 0 || v == 1 {
+				return v == 1, true
+			}
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b, true
+			}
+		}
+
+	case ColumnDuration:
+		switch v := v.(type) {
+		case int:
+			return v, true
+		case int64:
+			return int(v), true
+		case float64:
+			return int(math.Round(v)), true
+		case string:
+			// Bare numbers are milliseconds already
+			s := strings.TrimSpace(v)
+			if i, err := strconv.Atoi(s); err == nil {
+				return i, true
+			}
+			if d, err := time.ParseDuration(s); err == nil {
+				return int(d / time.Millisecond), true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestCoerceValue(t *testing.T) {
+	cases := []struct {
+		v    interface{}
+		t    ColumnType
+		want interface{}
+		ok   bool
+	}{
+		{"200", ColumnInt, 200, true},
+		{2.0, ColumnInt, 2, true},
+		{2.5, ColumnInt, nil, false},
+		{"abc", ColumnInt, nil, false},
+		{3, ColumnFloat, 3.0, true},
+		{"1.5", ColumnFloat, 1.5, true},
+		{14, ColumnString, "14", true},
+		{false, ColumnString, "false", true},
+		{"true", ColumnBool, true, true},
+		{1, ColumnBool, true, true},
+		{2, ColumnBool, nil, false},
+		{"12ms", ColumnDuration, 12, true},
+		{"1.5s", ColumnDuration, 1500, true},
+		{"30", ColumnDuration, 30, true},
+		{"soon", ColumnDuration, nil, false},
+	}
+	for _, c := range cases {
+		got, ok := coerceValue(c.v, c.t)
+		if got != c.want || ok != c.ok {
+			t.Errorf("coerceValue(%#v, %s) = %#v, %t; want %#v, %t", c.v, c.t, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestBatchCoerce(t *testing.T) {
+	defer func(types [][]ColumnType, policy string) {
+		columnTypes, ColumnValidationPolicy = types, policy
+	}(columnTypes, ColumnValidationPolicy)
+	columnTypes = parseColumnTypes("events.router.status.status=string,events.dyno.code=string,router.time=string,nope.x=int,router.service=bogus")
+
+	batch := new(pointBatch)
+	batch.PostPoint(Point{Token: "t.test", Type: EventsRouterStatus, Points: []interface{}{int64(1), 503, "web.1", "/", "web"}})
+	batch.PostPoint(Point{Token: "t.test", Type: EventsDyno, Points: []interface{}{int64(1), "web.1", "R", 14, "Error R14", "web", false, 1, nil, nil}})
+	batch.PostPoint(Point{Token: "t.test", Type: Router, Points: []interface{}{int64(1), 200, 10, 1, "web"}})
+	batch.Coerce()
+
+	if batch.points[0].Points[1] != "503" || batch.points[1].Points[3] != "14" {
+		t.Errorf("Expected status and code to be strings, got %#v and %#v", batch.points[0].Points[1], batch.points[1].Points[3])
+	}
+	if !reflect.DeepEqual(batch.points[2].Points, []interface{}{int64(1), 200, 10, 1, "web"}) {
+		t.Errorf("Expected time and undeclared columns to be left alone, got %#v", batch.points[2].Points)
+	}
+
+	columnTypes = parseColumnTypes("router.dynoType=int")
+	ColumnValidationPolicy = "drop"
+	failures := columnValidationCounter.Count()
+	batch.Coerce()
+	if len(batch.points) != 2 || columnValidationCounter.Count()-failures != 1 {
+		t.Errorf("Expected the invalid router point to be dropped, got %d points", len(batch.points))
+	}
+}
+
+func TestCoerceAfterAggregates(t *testing.T) {
+	defer func(types [][]ColumnType) { columnTypes = types }(columnTypes)
+	columnTypes = parseColumnTypes("router.status=string,router.service=float")
+
+	destination := NewDestination("coerce-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.throughput = NewThroughput(time.Minute)
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=20ms status=500 bytes=3`
+	req := lumbermilltest.NewDrainRequest("/drain", "t.coerce", lumbermilltest.SyslogLine("t.coerce", "router", router))
+	server.serveDrain(httptest.NewRecorder(), req)
+	server.throughput.Roll()
+
+	signals := server.throughput.Signals()
+	if len(signals) != 1 || signals[0].Requests != 1 || signals[0].ErrorRate != 1 || signals[0].P95Service == 0 {
+		t.Errorf("Expected the aggregates to see the columns' own types, got %+v", signals)
+	}
+	if point := <-destination.points; point.Points[1] != "500" {
+		t.Errorf("Expected the delivered status to be coerced, got %#v", point.Points[1])
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parses a comma separated list of key=value pairs, as used by the
+// per token / per destination environment settings, e.g.
+// "t.abc=staging.{series},t.def=prod.{series}"
+func parseKeyValueList(list string) map[string]string {
+	kv := make(map[string]string)
+	for _, pair := range strings.Split(list, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.Trim(parts[0], "\t ")
+		if key != "" {
+			kv[key] = strings.Trim(parts[1], "\t ")
+		}
+	}
+	return kv
+}
+
+// Parses a comma separated list into a set
+func parseSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.Trim(item, "\t "); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
+
+// Parses "<user>=<token>|<token>,..." into each user's set of tokens
+func parseAllowedTokens(list string) map[string]map[string]bool {
+	allowed := make(map[string]map[string]bool)
+	for user, tokens := range parseKeyValueList(list) {
+		allowed[user] = parseSet(strings.Replace(tokens, "|", ",", -1))
+	}
+	return allowed
+}
+
+// Returns the setting for key from a per token / per destination list,
+// falling back to the global value when key has no entry.
+func settingFor(perKey map[string]string, key, global string) string {
+	if v, found := perKey[key]; found {
+		return v
+	}
+	return global
+}
+
+// value, or def when it's unset
+func stringSetting(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// Parses an integer setting, using def when it's empty or invalid
+func parseIntSetting(name, value string, def int) int {
+	if value == "" {
+		return def
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s (%q), using %d: %s\n", name, value, def, err)
+		return def
+	}
+	return i
+}
+
+// Parses a floating point setting, using def when it's empty or invalid
+func parseFloatSetting(name, value string, def float64) float64 {
+	if value == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid %s (%q), using %g: %s\n", name, value, def, err)
+		return def
+	}
+	return f
+}
+
+// Parses a duration setting (e.g. "5s"), using def when it's empty or invalid
+func parseDurationSetting(name, value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s (%q), using %s: %s\n", name, value, def, err)
+		return def
+	}
+	return d
+}
@@ -17,6 +17,8 @@ Changelog:
 
  - 2014-07-02 (apg): Modified to support storing a Destination instead
    of string key
+ - 2026-10-15: Optionally bound the load of each destination, handing
+   the stretches of the ring over its share to the next destinations
 
 */
 
@@ -26,15 +28,47 @@ import (
 	"hash/fnv"
 	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
 )
 
+// Share of the tokens the last change of destinations moved
+var hashRingRemappedGauge = metrics.GetOrRegisterGaugeFloat64("lumbermill.hashring.remapped", metrics.DefaultRegistry)
+
+// Size of the space keys hash to
+const hashRingSpace = 1 << 32
+
 type HashFn func(data []byte) uint32
 
 type HashRing struct {
+	sync.RWMutex
 	hash     HashFn
 	replicas int
 	keys     []int // Sorted
 	hashMap  map[int]*Destination
+	bounds   []int          // Sorted ends of the stretches of the ring...
+	owners   []*Destination // ...and the destination of each
+
+	// When above 0, no destination is given more than LoadFactor times its
+	// share of the ring; the next destinations on the ring get the rest.
+	// Like the ring, that only depends on the destinations, so rings of the
+	// same destinations agree on where every key goes.
+	LoadFactor float64
+
+	// Lookups cached without taking the lock, see EnableCache
+	cacheEnabled bool
+	cache        sync.Map // key -> routeCacheEntry
+	generation   uint64   // Bumped to invalidate the cache
+
+	members map[*Destination]bool
+}
+
+type routeCacheEntry struct {
+	destination *Destination
+	generation  uint64
 }
 
 func NewHashRing(replicas int, fn HashFn) *HashRing {
@@ -42,6 +76,7 @@ func NewHashRing(replicas int, fn HashFn) *HashRing {
 		replicas: replicas,
 		hash:     fn,
 		hashMap:  make(map[int]*Destination),
+		members:  make(map[*Destination]bool),
 	}
 	if m.hash == nil {
 		// Default to fnv1a since it provides a better distribution
@@ -57,11 +92,18 @@ func NewHashRing(replicas int, fn HashFn) *HashRing {
 
 // Returns true if there are no items available.
 func (m *HashRing) IsEmpty() bool {
+	m.RLock()
+	defer m.RUnlock()
 	return len(m.keys) == 0
 }
 
-// Adds some keys to the hash.
+// Adds some keys to the hash. Only the keys that now hash to one of the new
+// destinations (or past one that's full) move.
 func (m *HashRing) Add(destinations ...*Destination) {
+	m.Lock()
+	defer m.Unlock()
+
+	bounds, owners := m.bounds, m.owners
 	for _, destination := range destinations {
 		for i := 0; i < m.replicas; i++ {
 			hash := int(m.hash([]byte(strconv.Itoa(i) + destination.Name)))
@@ -69,24 +111,201 @@ func (m *HashRing) Add(destinations ...*Destination) {
 			m.hashMap[hash] = destination
 		}
 		sort.Ints(m.keys)
+		m.members[destination] = true
+	}
+	m.assignOwners()
+	hashRingRemappedGauge.Update(m.remapped(bounds, owners))
+	m.invalidateCache()
+}
+
+// Removes destinations from the hash, moving their keys to the remaining
+// destinations.
+func (m *HashRing) Remove(destinations ...*Destination) {
+	m.Lock()
+	defer m.Unlock()
+
+	removed := make(map[*Destination]bool)
+	for _, destination := range destinations {
+		removed[destination] = true
+		delete(m.members, destination)
 	}
+	bounds, owners := m.bounds, m.owners
+	keys := m.keys[:0]
+	for _, hash := range m.keys {
+		if removed[m.hashMap[hash]] {
+			delete(m.hashMap, hash)
+		} else {
+			keys = append(keys, hash)
+		}
+	}
+	m.keys = keys
+	m.assignOwners()
+	hashRingRemappedGauge.Update(m.remapped(bounds, owners))
+	m.invalidateCache()
+}
+
+// Names of the destinations in the ring, sorted
+func (m *HashRing) Members() []string {
+	m.RLock()
+	defer m.RUnlock()
+	names := make([]string, 0, len(m.members))
+	for destination := range m.members {
+		names = append(names, destination.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *HashRing) Contains(destination *Destination) bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.members[destination]
 }
 
 // Gets the closest item in the hash to the provided key.
 func (m *HashRing) Get(key string) *Destination {
-	if m.IsEmpty() {
+	if !m.cacheEnabled {
+		return m.get(key)
+	}
+
+	generation := atomic.LoadUint64(&m.generation)
+	if e, found := m.cache.Load(key); found {
+		if entry := e.(routeCacheEntry); entry.generation == generation {
+			return entry.destination
+		}
+	}
+
+	destination := m.get(key)
+	if destination != nil {
+		m.cache.Store(key, routeCacheEntry{destination, generation})
+	}
+	return destination
+}
+
+func (m *HashRing) get(key string) *Destination {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.bounds) == 0 {
 		return nil
 	}
+	return m.owners[search(m.bounds, int(m.hash([]byte(key))))]
+}
 
-	hash := int(m.hash([]byte(key)))
+// Caches lookups, for at most ttl, in a map that's read without locking.
+// Batches of thousands of lines from the same token then don't contend on
+// the ring's lock. The whole cache expires at once, rather than checking the
+// clock on every lookup, which costs more than the lookup itself.
+func (m *HashRing) EnableCache(ttl time.Duration) {
+	m.cacheEnabled = true
+	go func() {
+		for range time.Tick(ttl) {
+			m.invalidateCache()
+		}
+	}()
+}
 
+// Drops every cached lookup. Entries cached concurrently with the old
+// generation are ignored by Get.
+func (m *HashRing) invalidateCache() {
+	atomic.AddUint64(&m.generation, 1)
+	m.cache.Range(func(key, _ interface{}) bool {
+		m.cache.Delete(key)
+		return true
+	})
+}
+
+// Index of the first of bounds at or after hash
+func search(bounds []int, hash int) int {
 	// Binary search for appropriate replica.
-	idx := sort.Search(len(m.keys), func(i int) bool { return m.keys[i] >= hash })
+	idx := sort.Search(len(bounds), func(i int) bool { return bounds[i] >= hash })
 
 	// Means we have cycled back to the first replica.
-	if idx == len(m.keys) {
+	if idx == len(bounds) {
 		idx = 0
 	}
+	return idx
+}
+
+// A stretch of the ring, up to end, and its destination
+type ringStretch struct {
+	end         int
+	destination *Destination
+}
+
+// Works out the destination of each stretch of the ring: the replica's
+// after it, or, past the replica's share, the next ones on the ring with
+// room. Stretches only depend on the destinations, never on the keys seen.
+func (m *HashRing) assignOwners() {
+	m.bounds, m.owners = nil, nil
+	if len(m.keys) == 0 {
+		return
+	}
+	if m.LoadFactor <= 0 {
+		for _, hash := range m.keys {
+			m.bounds = append(m.bounds, hash)
+			m.owners = append(m.owners, m.hashMap[hash])
+		}
+		return
+	}
+
+	capacity := int(m.LoadFactor * hashRingSpace / float64(len(m.members)))
+	loads := make(map[*Destination]int, len(m.members))
+	var stretches []ringStretch
+	for i, hash := range m.keys {
+		start := m.keys[(i+len(m.keys)-1)%len(m.keys)]
+		if i == 0 {
+			start -= hashRingSpace
+		}
+		for j := 0; j < len(m.keys) && start < hash; j++ {
+			destination := m.hashMap[m.keys[(i+j)%len(m.keys)]]
+			if room := capacity - loads[destination]; room > 0 {
+				end := hash
+				if start+room < end {
+					end = start + room
+				}
+				loads[destination] += end - start
+				stretches = append(stretches, ringStretch{end, destination})
+				start = end
+			}
+		}
+		if start < hash {
+			// No room left anywhere
+			stretches = append(stretches, ringStretch{hash, m.hashMap[hash]})
+		}
+	}
+
+	// The first replica's stretch may begin before 0
+	for i := range stretches {
+		if stretches[i].end < 0 {
+			stretches[i].end += hashRingSpace
+		}
+	}
+	sort.SliceStable(stretches, func(i, j int) bool { return stretches[i].end < stretches[j].end })
+	for _, stretch := range stretches {
+		m.bounds = append(m.bounds, stretch.end)
+		m.owners = append(m.owners, stretch.destination)
+	}
+}
+
+// Share of the ring whose destination differs from the one it had with the
+// previous bounds and owners
+func (m *HashRing) remapped(bounds []int, owners []*Destination) float64 {
+	if len(bounds) == 0 || len(m.bounds) == 0 {
+		return 0
+	}
 
-	return m.hashMap[m.keys[idx]]
+	// Between consecutive bounds of either, keys go to the same destination
+	all := append(append([]int(nil), bounds...), m.bounds...)
+	sort.Ints(all)
+	moved := 0
+	for i, hash := range all {
+		arc := hash - all[(i+len(all)-1)%len(all)]
+		if i == 0 {
+			arc += hashRingSpace
+		}
+		if owners[search(bounds, hash)] != m.owners[search(m.bounds, hash)] {
+			moved += arc
+		}
+	}
+	return float64(moved) / hashRingSpace
 }
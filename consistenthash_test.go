@@ -21,6 +21,7 @@ import (
 	"hash/crc32"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestHashing(t *testing.T) {
@@ -53,7 +54,7 @@ func TestHashing(t *testing.T) {
 
 	for k, v := range testCases {
 		if hash.Get(k) != v {
-			t.Errorf("Asking for %s, should have yielded %s", k, v)
+			t.Errorf("Asking for %s, should have yielded %s", k, v.Name)
 		}
 	}
 
@@ -97,3 +98,106 @@ func TestConsistency(t *testing.T) {
 		t.Errorf("Direct matches should always return the same entry")
 	}
 }
+
+func TestBoundedLoads(t *testing.T) {
+	hash := NewHashRing(HashRingReplication, nil)
+	hash.LoadFactor = 1.25
+	destinations := []*Destination{NewDestination("a", 1), NewDestination("b", 1), NewDestination("c", 1), NewDestination("d", 1)}
+	hash.Add(destinations...)
+
+	// Unbounded, b would have over 1450 of them
+	loads := make(map[*Destination]int)
+	for i := 0; i < 4000; i++ {
+		loads[hash.Get("t."+strconv.Itoa(i))]++
+	}
+
+	for _, d := range destinations {
+		if load := loads[d]; load > 1300 {
+			t.Errorf("%s has %d keys, more than its bound of 1250", d.Name, load)
+		}
+	}
+}
+
+func TestBoundedLoadsIgnoreKeyOrder(t *testing.T) {
+	hash1, hash2 := NewHashRing(HashRingReplication, nil), NewHashRing(HashRingReplication, nil)
+	hash1.LoadFactor, hash2.LoadFactor = 1.1, 1.1
+	hash1.Add(NewDestination("a", 1), NewDestination("b", 1), NewDestination("c", 1))
+	hash2.Add(NewDestination("c", 1), NewDestination("a", 1), NewDestination("b", 1))
+
+	for i := 0; i < 300; i++ {
+		hash1.Get("t." + strconv.Itoa(i))
+	}
+	for i := 299; i >= 0; i-- {
+		key := "t." + strconv.Itoa(i)
+		if d1, d2 := hash1.Get(key), hash2.Get(key); d1.Name != d2.Name {
+			t.Errorf("%s went to %s and %s", key, d1.Name, d2.Name)
+		}
+	}
+}
+
+func TestRemapping(t *testing.T) {
+	hash := NewHashRing(HashRingReplication, nil)
+	a, b, c := NewDestination("a", 1), NewDestination("b", 1), NewDestination("c", 1)
+	hash.Add(a, b)
+
+	before := make(map[string]*Destination)
+	for i := 0; i < 3000; i++ {
+		key := "t." + strconv.Itoa(i)
+		before[key] = hash.Get(key)
+	}
+
+	hash.Add(c)
+	moved := 0
+	for key, destination := range before {
+		if now := hash.Get(key); now != destination {
+			moved++
+			if now != c {
+				t.Errorf("%s moved from %s to %s rather than the new destination", key, destination.Name, now.Name)
+			}
+		}
+	}
+	if remapped, share := hashRingRemappedGauge.Value(), float64(moved)/3000; remapped < share-0.05 || remapped > share+0.05 {
+		t.Errorf("Reported %.2f of the keys remapped, but %.2f moved", remapped, share)
+	}
+
+	hash.Remove(c)
+	for key, destination := range before {
+		if now := hash.Get(key); now != destination {
+			t.Errorf("%s didn't go back to %s after removing c", key, destination.Name)
+		}
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	hash := NewHashRing(3, func(key []byte) uint32 {
+		i, _ := strconv.Atoi(string(key))
+		return uint32(i)
+	})
+	hash.EnableCache(time.Minute)
+	two, eight := NewDestination("2", 1), NewDestination("8", 1)
+	hash.Add(two)
+
+	if hash.Get("27") != two {
+		t.Fatal("Expected 27 to map to 2")
+	}
+	hash.Add(eight)
+	if hash.Get("27") != eight {
+		t.Errorf("Cached lookup survived adding a destination")
+	}
+}
+
+func benchmarkHashRingGet(b *testing.B, ttl time.Duration) {
+	hash := NewHashRing(HashRingReplication, nil)
+	if ttl > 0 {
+		hash.EnableCache(ttl)
+	}
+	hash.Add(NewDestination("a", 1), NewDestination("b", 1), NewDestination("c", 1))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			hash.Get("t.b5ef0c3c-3d0b-4a4d-a9f4-e9c4b0c1a2f3")
+		}
+	})
+}
+
+func BenchmarkHashRingGet(b *testing.B)       { benchmarkHashRingGet(b, 0) }
+func BenchmarkHashRingGetCached(b *testing.B) { benchmarkHashRingGet(b, time.Minute) }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ANSI colors per series type
+var seriesColors = []string{
+	"\x1b[36m", // Router
+	"\x1b[31m", // EventsRouter
+	"\x1b[32m", // DynoMem
+	"\x1b[33m", // DynoLoad
+	"\x1b[35m", // EventsDyno
+	"\x1b[91m", // EventsRouterStatus
+	"\x1b[93m", // LogplexHealth
+	"\x1b[94m", // LogPatterns
+	"\x1b[96m", // SLO
+	"\x1b[92m", // DynoConcurrency
+	"\x1b[95m", // EventsBackpressure
+	"\x1b[37m", // OneOffDyno
+	"\x1b[34m", // DynoType
+}
+
+const colorReset = "\x1b[0m"
+
+// Pretty prints the selected series, for demos and debugging extraction
+type ConsolePoster struct {
+	destination *Destination
+	out         *bufio.Writer
+	series      map[SeriesType]bool // Series to print, all when empty
+	color       bool
+	waitGroup   *sync.WaitGroup
+}
+
+// Creates a poster printing the series named in the comma separated filter
+// (e.g. "router,events.dyno"), or all of them if it's empty.
+func NewConsolePoster(out io.Writer, filter string, color bool, destination *Destination, waitGroup *sync.WaitGroup) *ConsolePoster {
+	series := make(map[SeriesType]bool)
+	for _, name := range strings.Split(filter, ",") {
+		name = strings.Trim(name, "\t ")
+		for st := SeriesType(0); st < numSeries; st++ {
+			if st.Name() == name {
+				series[st] = true
+			}
+		}
+	}
+
+	waitGroup.Add(1)
+	return &ConsolePoster{
+		destination: destination,
+		out:         bufio.NewWriter(out),
+		series:      series,
+		color:       color,
+		waitGroup:   waitGroup,
+	}
+}
+
+func (p *ConsolePoster) print(point Point) {
+	if len(p.series) > 0 && !p.series[point.Type] {
+		return
+	}
+
+	if p.color {
+		p.out.WriteString(seriesColors[point.Type])
+	}
+	fmt.Fprintf(p.out, "%-12s", point.Type.Name())
+	if p.color {
+		p.out.WriteString(colorReset)
+	}
+	fmt.Fprintf(p.out, " %s", point.Token)
+
+	for i, column := range point.Type.Columns() {
+		if i < len(point.Points) {
+			fmt.Fprintf(p.out, " %s=%v", column, point.Points[i])
+		}
+	}
+	p.out.WriteByte('\n')
+}
+
+func (p *ConsolePoster) Run() {
+	defer p.waitGroup.Done()
+
+	flush := time.NewTicker(100 * time.Millisecond)
+	defer flush.Stop()
+	defer p.out.Flush()
+
+	for {
+		select {
+		case point, open := <-p.destination.points:
+			if !open {
+				return
+			}
+			p.print(point)
+			p.destination.ack(point.RequestId, 1)
+		case <-flush.C:
+			p.out.Flush()
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestConsolePosterFiltering(t *testing.T) {
+	var out bytes.Buffer
+	destination := NewDestination("console-test", 10)
+	poster := NewConsolePoster(&out, "events.router, dyno.load", false, destination, new(sync.WaitGroup))
+
+	destination.PostPoint(pointAt(1))
+	destination.PostPoint(Point{Token: "t.test", Type: EventsRouter, Points: []interface{}{int64(2), "H12"}})
+	destination.Close()
+	poster.Run()
+
+	expected := "events.router t.test time=2 code=H12\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestConsolePosterColor(t *testing.T) {
+	var out bytes.Buffer
+	destination := NewDestination("console-color-test", 10)
+	poster := NewConsolePoster(&out, "", true, destination, new(sync.WaitGroup))
+
+	destination.PostPoint(pointAt(1))
+	destination.Close()
+	poster.Run()
+
+	expected := seriesColors[Router] + "router      " + colorReset + " t.test time=1 status=200 service=1\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Window over which identical dyno error lines (e.g. R14s, which repeat
+	// every 20 seconds) of a dyno are coalesced. Off when unset.
+	DynoErrorDedupWindow = parseDurationSetting("DYNO_ERROR_DEDUP_WINDOW", os.Getenv("DYNO_ERROR_DEDUP_WINDOW"), 0)
+
+	dynoErrorDedupedCounter = metrics.GetOrRegisterCounter("lumbermill.lines.dyno.error.deduped", metrics.DefaultRegistry)
+)
+
+// Index of the repeats column in events.dyno points
+const dynoErrorRepeats = 7
+
+type dedupEntry struct {
+	start   int64 // Time of the line that opened the window, in microseconds
+	repeats int   // Lines suppressed since
+	last    Point // Latest suppressed line
+}
+
+// Coalesces identical dyno error lines. The first line of a window is
+// delivered as usual, and the repeats suppressed after it are delivered as
+// one point, with their count in the repeats column, when the window ends.
+type DynoErrorDedup struct {
+	sync.Mutex
+	window  int64 // In microseconds
+	entries map[string]*dedupEntry
+}
+
+func NewDynoErrorDedup(window time.Duration) *DynoErrorDedup {
+	return &DynoErrorDedup{window: int64(window / time.Microsecond), entries: make(map[string]*dedupEntry)}
+}
+
+func dedupKey(point Point) string {
+	return point.Token + "\x00" + point.Points[1].(string) + "\x00" + point.Points[4].(string)
+}
+
+// Whether an events.dyno point repeats one of the current window, and should
+// be dropped. Closing a window returns the point summarizing its repeats,
+// which should be delivered. A nil dedup suppresses nothing.
+func (d *DynoErrorDedup) Check(point Point) (bool, *Point) {
+	if d == nil {
+		return false, nil
+	}
+	timestamp, _ := point.Timestamp()
+	key := dedupKey(point)
+
+	d.Lock()
+	defer d.Unlock()
+	entry, found := d.entries[key]
+	if found && timestamp-entry.start < d.window {
+		entry.repeats++
+		entry.last = point
+		return true, nil
+	}
+	d.entries[key] = &dedupEntry{start: timestamp}
+	if found {
+		return false, entry.summary()
+	}
+	return false, nil
+}
+
+// Forgets the windows that ended by now, returning the points summarizing
+// their repeats
+func (d *DynoErrorDedup) Expire(now time.Time) []Point {
+	nowMicros := now.UnixNano() / int64(time.Microsecond)
+	summaries := make([]Point, 0)
+
+	d.Lock()
+	defer d.Unlock()
+	for key, entry := range d.entries {
+		if nowMicros-entry.start < d.window {
+			continue
+		}
+		if summary := entry.summary(); summary != nil {
+			summaries = append(summaries, *summary)
+		}
+		delete(d.entries, key)
+	}
+	return summaries
+}
+
+// Delivers the summaries of ended windows every so often, until stop is
+// closed
+func (d *DynoErrorDedup) Run(routes *derivedRoutes, every time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		for _, summary := range d.Expire(time.Now()) {
+			routes.Post(summary)
+		}
+	}
+}
+
+func (e *dedupEntry) summary() *Point {
+	if e.repeats == 0 {
+		return nil
+	}
+	summary := e.last
+	summary.Points = append([]interface{}(nil), e.last.Points...)
+	summary.Points[dynoErrorRepeats] = e.repeats
+	return &summary
+}
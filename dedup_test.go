@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDynoErrorDedup(t *testing.T) {
+	dedup := NewDynoErrorDedup(time.Minute)
+	start := time.Unix(1500000000, 0)
+	r14 := func(dyno string, at time.Duration) Point {
+		timestamp := start.Add(at).UnixNano() / int64(time.Microsecond)
+		return Point{Token: "t.test", Type: EventsDyno, Points: []interface{}{timestamp, dyno, "R", 14, "Error R14 (Memory quota exceeded)", "web", false, 1}}
+	}
+
+	delivered := make([]Point, 0)
+	for at := time.Duration(0); at < 2*time.Minute; at += 20 * time.Second {
+		for _, dyno := range []string{"web.1", "web.2"} {
+			suppressed, summary := dedup.Check(r14(dyno, at))
+			if summary != nil {
+				delivered = append(delivered, *summary)
+			}
+			if !suppressed {
+				delivered = append(delivered, r14(dyno, at))
+			}
+		}
+	}
+	delivered = append(delivered, dedup.Expire(start.Add(3*time.Minute))...)
+
+	// Per dyno and window: the first line, then the 2 repeats after it
+	repeats := make(map[string]int)
+	for _, point := range delivered {
+		repeats[point.Points[1].(string)] += point.Points[dynoErrorRepeats].(int)
+	}
+	if len(delivered) != 8 || repeats["web.1"] != 6 || repeats["web.2"] != 6 {
+		t.Errorf("Expected 4 points per dyno accounting for its 6 lines, got %d: %v", len(delivered), repeats)
+	}
+	if len(dedup.entries) != 0 {
+		t.Errorf("Expected ended windows to be forgotten")
+	}
+
+	var off *DynoErrorDedup
+	if suppressed, _ := off.Check(r14("web.1", 0)); suppressed {
+		t.Errorf("Expected nothing to be suppressed when off")
+	}
+}
+
+func TestDynoRestarts(t *testing.T) {
+	restarts := NewDynoRestarts()
+	start := time.Unix(1500000000, 0)
+	at := func(d time.Duration) int64 { return start.Add(d).UnixNano() / int64(time.Microsecond) }
+
+	restarts.Record("t.a", at(0))
+	for _, tc := range []struct {
+		token, code string
+		at          time.Duration
+		related     bool
+	}{
+		{"t.a", "H13", 5 * time.Second, true},
+		{"t.a", "H18", RestartCorrelationWindow, true},
+		{"t.a", "H12", 5 * time.Second, false},
+		{"t.a", "H13", -5 * time.Second, false},
+		{"t.a", "H13", RestartCorrelationWindow + time.Second, false},
+		{"t.b", "H13", 5 * time.Second, false},
+	} {
+		if related := restarts.Related(tc.token, tc.code, at(tc.at)); related != tc.related {
+			t.Errorf("%s %s at %s: Expected related=%t", tc.token, tc.code, tc.at, tc.related)
+		}
+	}
+
+	restarts.Expire(start.Add(time.Hour))
+	if len(restarts.latest) != 0 {
+		t.Errorf("Expected old restarts to be forgotten")
+	}
+}
@@ -1,49 +1,262 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	metrics "github.com/rcrowley/go-metrics"
 )
 
+var errDestinationClosed = errors.New("destination closed")
+
+// What a destination does with a point when its channel is full
+type DropPolicy string
+
+const (
+	DropNewest DropPolicy = "drop-newest" // Drop the point being posted
+	DropOldest DropPolicy = "drop-oldest" // Make room by dropping the oldest pending point
+	Block      DropPolicy = "block"       // Wait for room, applying backpressure to the drain
+)
+
 // A channel of points and related sampling
 type Destination struct {
-	Name       string
-	points     chan Point
-	depthGauge metrics.Gauge
+	Name             string
+	DropPolicy       DropPolicy
+	Shadow           *Destination // Candidate backend that also gets a share of the points
+	ShadowPercent    int          // Percentage of tokens whose points are shadowed
+	Archive          *Destination // Archival sink that also gets every point, as delivered
+	SanitizeUTF8     bool         // Clean up invalid UTF-8 and control characters in values
+	ScrubPII         bool         // Redact emails, tokens and query strings from raw messages and paths
+	Maintenance      *toggle      // Batches with points for it get a 503 while on
+	candidate        bool         // A shadow or archive, whose deliveries don't ack drain requests
+	points           chan Point
+	events           chan Point    // Priority lane for error events, nil without one
+	closing          chan struct{} // Closed when Close starts, waking blocked posts
+	closeMu          sync.RWMutex  // Held by posts, so lanes aren't closed under them
+	closeOnce        sync.Once
+	closed           bool
+	highWatermark    int64  // Most points pending since the last sample
+	enqueued         int64  // Points added to the channel since the last sample
+	drainRate        uint64 // Points delivered per second over the last sample, as float64 bits
+	lastDepth        int64
+	depthGauge       metrics.Gauge
+	watermarkGauge   metrics.Gauge
+	droppedCounter   metrics.Counter
+	sanitizedCounter metrics.Counter
+	scrubbedCounter  metrics.Counter
 }
 
 func NewDestination(name string, chanCap int) *Destination {
-	destination := &Destination{Name: name}
+	destination := &Destination{Name: name, DropPolicy: DropNewest, Maintenance: newToggle(false), closing: make(chan struct{})}
 	destination.points = make(chan Point, chanCap)
 	destination.depthGauge = metrics.GetOrRegisterGauge(
 		"lumbermill.points.pending."+name,
 		metrics.DefaultRegistry,
 	)
+	destination.watermarkGauge = metrics.GetOrRegisterGauge(
+		"lumbermill.points.pending.highwatermark."+name,
+		metrics.DefaultRegistry,
+	)
+	destination.droppedCounter = metrics.GetOrRegisterCounter(
+		"lumbermill.errors.dropped."+name,
+		metrics.DefaultRegistry,
+	)
+	destination.sanitizedCounter = metrics.GetOrRegisterCounter(
+		"lumbermill.sanitized.values."+name,
+		metrics.DefaultRegistry,
+	)
+	destination.scrubbedCounter = metrics.GetOrRegisterCounter(
+		"lumbermill.scrubbed.values."+name,
+		metrics.DefaultRegistry,
+	)
 
 	go destination.Sample(10 * time.Second)
 
 	return destination
 }
 
+// Gives the destination a priority lane for error events, holding up to
+// chanCap of them, so posters deliver them ahead of bulk metrics when behind.
+// Only Posters read the lane.
+func (d *Destination) EnablePriorityLane(chanCap int) {
+	if chanCap > 0 {
+		d.events = make(chan Point, chanCap)
+	}
+}
+
+// Whether points of the series type take the priority lane
+func (st SeriesType) priority() bool {
+	return st == EventsRouter || st == EventsRouterStatus || st == EventsDyno
+}
+
+// Points pending in both lanes
+func (d *Destination) depth() int {
+	return len(d.points) + len(d.events)
+}
+
 // Update depth guages every so often
 func (d *Destination) Sample(every time.Duration) {
 	for {
 		time.Sleep(every)
-		d.depthGauge.Update(int64(len(d.points)))
+		d.sample(every)
 	}
 }
 
-// Post the point, or increment a counter if channel is full
+func (d *Destination) sample(every time.Duration) {
+	depth := int64(d.depth())
+	d.depthGauge.Update(depth)
+	d.watermarkGauge.Update(atomic.SwapInt64(&d.highWatermark, depth))
+
+	drained := d.lastDepth + atomic.SwapInt64(&d.enqueued, 0) - depth
+	atomic.StoreUint64(&d.drainRate, math.Float64bits(float64(drained)/every.Seconds()))
+	d.lastDepth = depth
+}
+
+// Points delivered per second, as of the last sample
+func (d *Destination) DrainRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&d.drainRate))
+}
+
+// Post the point, or apply the drop policy if channel is full
 func (d *Destination) PostPoint(point Point) {
+	d.PostPointContext(context.Background(), point)
+}
+
+// Like PostPoint, giving up waiting for room (with the Block policy) when
+// ctx is done, in which case the point is dropped and ctx's error returned
+func (d *Destination) PostPointContext(ctx context.Context, point Point) error {
+	if d.Shadow != nil && tokenInPercentage(point.Token, d.ShadowPercent) {
+		d.Shadow.PostPointContext(ctx, point)
+	}
+
+	if d.SanitizeUTF8 {
+		var changed int
+		if point, changed = sanitizePoint(point); changed > 0 {
+			d.sanitizedCounter.Inc(int64(changed))
+		}
+	}
+
+	if d.ScrubPII {
+		var changed int
+		if point, changed = scrubPoint(point); changed > 0 {
+			d.scrubbedCounter.Inc(int64(changed))
+		}
+	}
+
+	if d.Archive != nil {
+		d.Archive.PostPointContext(ctx, point)
+	}
+
+	lane := d.points
+	if d.events != nil && point.Type.priority() {
+		lane = d.events
+	}
+
+	d.closeMu.RLock()
+	defer d.closeMu.RUnlock()
+	atomic.AddInt64(&d.enqueued, 1)
+	if d.closed {
+		d.dropped(point)
+		return errDestinationClosed
+	}
 	select {
-	case d.points <- point:
+	case lane <- point:
 	default:
-		droppedErrorCounter.Inc(1)
+		switch d.DropPolicy {
+		case Block:
+			select {
+			case lane <- point:
+			case <-ctx.Done():
+				d.dropped(point)
+				return ctx.Err()
+			case <-d.closing:
+				d.dropped(point)
+				return errDestinationClosed
+			}
+		case DropOldest:
+			select {
+			case old := <-lane:
+				d.dropped(old)
+			default:
+			}
+			select {
+			case lane <- point:
+			default:
+				d.dropped(point)
+			}
+		default:
+			d.dropped(point)
+		}
+	}
+	d.updateHighWatermark()
+	return nil
+}
+
+func (d *Destination) dropped(point Point) {
+	d.fail(point.RequestId)
+	atomic.AddInt64(&d.enqueued, -1)
+	droppedErrorCounter.Inc(1)
+	d.droppedCounter.Inc(1)
+}
+
+// Records that n points of a drain request were delivered
+func (d *Destination) ack(requestId string, n int) {
+	if !d.candidate {
+		acks.Ack(requestId, n)
+	}
+}
+
+// Records that points of a drain request were dropped, or couldn't be
+// delivered
+func (d *Destination) fail(requestId string) {
+	if !d.candidate {
+		acks.Fail(requestId)
+	}
+}
+
+func (d *Destination) updateHighWatermark() {
+	depth := int64(d.depth())
+	for {
+		hwm := atomic.LoadInt64(&d.highWatermark)
+		if depth <= hwm || atomic.CompareAndSwapInt64(&d.highWatermark, hwm, depth) {
+			return
+		}
 	}
 }
 
+// Closes the lanes once posts in flight are done. Points posted afterwards
+// are dropped.
 func (d *Destination) Close() error {
+	d.closeOnce.Do(func() { close(d.closing) })
+	d.closeMu.Lock()
+	defer d.closeMu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	// Events first, so posters have drained them when points close
+	if d.events != nil {
+		close(d.events)
+	}
 	close(d.points)
 	return nil
 }
+
+// Deterministically picks percent% of tokens, so a token is either always or
+// never picked and its series stay complete.
+func tokenInPercentage(token string, percent int) bool {
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(token))
+	return int(h.Sum32()%100) < percent
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+func pointAt(timestamp int64) Point {
+	return Point{Token: "t.test", Type: Router, Points: []interface{}{timestamp, 200, 1}}
+}
+
+func TestDestinationDropNewest(t *testing.T) {
+	destination := NewDestination("drop-newest-test", 2)
+
+	for i := int64(1); i <= 3; i++ {
+		destination.PostPoint(pointAt(i))
+	}
+
+	if ts := (<-destination.points).Points[0]; ts != int64(1) {
+		t.Errorf("Expected oldest point to be kept, got %v", ts)
+	}
+	if destination.droppedCounter.Count() != 1 {
+		t.Errorf("Expected 1 dropped point, got %d", destination.droppedCounter.Count())
+	}
+	if destination.highWatermark != 2 {
+		t.Errorf("Expected high watermark of 2, got %d", destination.highWatermark)
+	}
+}
+
+func TestDestinationDropOldest(t *testing.T) {
+	destination := NewDestination("drop-oldest-test", 2)
+	destination.DropPolicy = DropOldest
+
+	for i := int64(1); i <= 3; i++ {
+		destination.PostPoint(pointAt(i))
+	}
+
+	if ts := (<-destination.points).Points[0]; ts != int64(2) {
+		t.Errorf("Expected oldest point to be dropped, got %v", ts)
+	}
+	if destination.droppedCounter.Count() != 1 {
+		t.Errorf("Expected 1 dropped point, got %d", destination.droppedCounter.Count())
+	}
+}
+
+func TestCreateDestinationSettings(t *testing.T) {
+	defer func(capacities, policies map[string]string) {
+		DestinationChannelCapacities = capacities
+		DestinationDropPolicies = policies
+	}(DestinationChannelCapacities, DestinationDropPolicies)
+
+	DestinationChannelCapacities = parseKeyValueList("a=10,b=bogus,c=-1")
+	DestinationDropPolicies = parseKeyValueList("a=block,b=bogus")
+
+	a := createDestination("a")
+	if cap(a.points) != 10 || a.DropPolicy != Block {
+		t.Errorf("Expected a to have capacity 10 and block, got %d and %s", cap(a.points), a.DropPolicy)
+	}
+
+	b := createDestination("b")
+	if cap(b.points) != PointChannelCapacity || b.DropPolicy != DropNewest {
+		t.Errorf("Expected b to fall back to defaults, got %d and %s", cap(b.points), b.DropPolicy)
+	}
+	if c := createDestination("c"); cap(c.points) != PointChannelCapacity {
+		t.Errorf("Expected a negative capacity to fall back to the default, got %d", cap(c.points))
+	}
+}
+
+func TestDryRunRoutes(t *testing.T) {
+	defer func(dryRun bool) { DryRun = dryRun }(DryRun)
+	DryRun = true
+
+	hashRing, destinations, _ := createMessageRoutes(context.Background(), "dry-run.example.com:8086", true)
+	destination := hashRing.Get("t.test")
+	if destination == nil || destination.Name != "dry-run.example.com:8086" {
+		t.Fatalf("Expected tokens to be routed to the configured host, got %v", destination)
+	}
+
+	counter := metrics.GetOrRegisterCounter("lumbermill.poster.null.points."+destination.Name, metrics.DefaultRegistry)
+	before := counter.Count()
+	destination.PostPoint(pointAt(1))
+	destinations[0].Close()
+
+	for i := 0; i < 100 && counter.Count() == before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if counter.Count()-before != 1 {
+		t.Errorf("Expected the point to be counted and discarded")
+	}
+}
+
+func TestShadowDestination(t *testing.T) {
+	destination := NewDestination("shadowed-test", 100)
+	destination.Shadow = NewDestination("shadow-test", 100)
+	destination.ShadowPercent = 50
+
+	shadowed := 0
+	for i := 0; i < 100; i++ {
+		token := fmt.Sprintf("t.%d", i)
+		destination.PostPoint(Point{Token: token, Type: Router, Points: []interface{}{int64(i), 200, 1}})
+		destination.PostPoint(Point{Token: token, Type: Router, Points: []interface{}{int64(i), 200, 1}})
+		if tokenInPercentage(token, 50) {
+			shadowed++
+		}
+	}
+
+	if len(destination.points) != 100 {
+		t.Errorf("Expected all points on the destination, got %d", len(destination.points))
+	}
+	if len(destination.Shadow.points) != 2*shadowed {
+		t.Errorf("Expected both points of %d tokens to be shadowed, got %d points", shadowed, len(destination.Shadow.points))
+	}
+	if shadowed < 30 || shadowed > 70 {
+		t.Errorf("Expected roughly half the tokens to be shadowed, got %d", shadowed)
+	}
+
+	if tokenInPercentage("t.any", 0) || !tokenInPercentage("t.any", 100) {
+		t.Error("Expected 0% and 100% to pick no and all tokens")
+	}
+}
+
+func TestDestinationPriorityLane(t *testing.T) {
+	destination := NewDestination("priority-test", 10)
+	destination.EnablePriorityLane(10)
+	poster := &Poster{destination: destination, reorder: newReorderBuffer(reorderWindows)}
+
+	for i := int64(1); i <= 3; i++ {
+		destination.PostPoint(pointAt(i))
+	}
+	destination.PostPoint(Point{Token: "t.test", Type: EventsRouter, Points: []interface{}{int64(4), "H12", "web.1", "/", "web", false, ""}})
+	if len(destination.points) != 3 || len(destination.events) != 1 || destination.depth() != 4 {
+		t.Fatalf("Expected the event in the priority lane, got %d and %d", len(destination.points), len(destination.events))
+	}
+
+	destination.Close()
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	d, last := poster.nextDelivery(ticker)
+	if !last || len(d.series["router.t.test"].Points) != 3 || len(d.series["events.router.t.test"].Points) != 1 {
+		t.Errorf("Expected both lanes to be delivered, got %d series", len(d.series))
+	}
+
+	writes := splitWrites(d.series, d.priority, 1, 0)
+	if len(writes) != 4 || writes[0][0].Name != "events.router.t.test" {
+		t.Errorf("Expected the event to be written first, got %s", writes[0][0].Name)
+	}
+	writes = splitWrites(d.series, d.priority, 0, 0)
+	if len(writes) != 1 || writes[0][0].Name != "events.router.t.test" {
+		t.Errorf("Expected the event to lead the write without limits, got %s", writes[0][0].Name)
+	}
+}
+
+func TestSeriesPriority(t *testing.T) {
+	for st, priority := range map[SeriesType]bool{
+		EventsRouter:       true,
+		EventsRouterStatus: true,
+		EventsDyno:         true,
+		Router:             false,
+		DynoMem:            false,
+	} {
+		if st.priority() != priority {
+			t.Errorf("Expected %s to take the priority lane: %t", st.Name(), priority)
+		}
+	}
+
+	destination := NewDestination("priority-status-test", 10)
+	destination.EnablePriorityLane(10)
+	destination.PostPoint(Point{Token: "t.test", Type: EventsRouterStatus, Points: []interface{}{int64(1), 503, "web.1", "/", "web", nil}})
+	if len(destination.events) != 1 {
+		t.Errorf("Expected router status events in the priority lane")
+	}
+}
+
+func TestDestinationBlockGivesUp(t *testing.T) {
+	destination := NewDestination("block-context-test", 1)
+	destination.DropPolicy = Block
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	batch := new(pointBatch)
+	for i := int64(1); i <= 3; i++ {
+		batch.PostPoint(pointAt(i))
+	}
+	canceled := canceledPointsCounter.Count()
+	if err := batch.Flush(ctx, hashRing); err != context.DeadlineExceeded {
+		t.Fatalf("Expected the flush to give up waiting for room, got %v", err)
+	}
+	if destination.droppedCounter.Count() != 1 || canceledPointsCounter.Count()-canceled != 1 {
+		t.Errorf("Expected the point waiting for room to be dropped and the last canceled, got %d and %d",
+			destination.droppedCounter.Count(), canceledPointsCounter.Count()-canceled)
+	}
+	if len(destination.points) != 1 {
+		t.Errorf("Expected the first point to be posted, got %d", len(destination.points))
+	}
+}
+
+func TestDestinationClosedWhileBlocked(t *testing.T) {
+	destination := NewDestination("block-close-test", 1)
+	destination.DropPolicy = Block
+	destination.PostPoint(pointAt(1))
+
+	blocked := make(chan error)
+	go func() { blocked <- destination.PostPointContext(context.Background(), pointAt(2)) }()
+	time.Sleep(10 * time.Millisecond)
+	destination.Close()
+
+	if err := <-blocked; err != errDestinationClosed {
+		t.Errorf("Expected the blocked post to give up once closed, got %v", err)
+	}
+	if err := destination.PostPointContext(context.Background(), pointAt(3)); err != errDestinationClosed {
+		t.Errorf("Expected posts after closing to be dropped, got %v", err)
+	}
+	if destination.droppedCounter.Count() != 2 || len(destination.points) != 1 {
+		t.Errorf("Expected 2 dropped points and 1 pending, got %d and %d", destination.droppedCounter.Count(), len(destination.points))
+	}
+	destination.Close()
+}
+
+func TestArchiveRoutes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumbermill-archive-routes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(archive, shadow, policy string) {
+		ArchivePath, ShadowInfluxDBHost, DestinationDropPolicy = archive, shadow, policy
+	}(ArchivePath, ShadowInfluxDBHost, DestinationDropPolicy)
+	ArchivePath, ShadowInfluxDBHost, DestinationDropPolicy = dir, "shadow.example.com:8086", string(Block)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hashRing, destinations, posterGroup := createMessageRoutes(ctx, "", true)
+	defer func() {
+		for _, destination := range destinations {
+			destination.Close()
+		}
+		posterGroup.Wait()
+	}()
+
+	destination := hashRing.Get("t.test")
+	if destination.Archive == nil || destination.Shadow == nil {
+		t.Fatalf("Expected the ring's destination to be archived and shadowed")
+	}
+	if archive := destination.Archive; archive.Shadow != nil || archive.DropPolicy != DropNewest {
+		t.Errorf("Expected the archive not to shadow points again, nor to block, got %v and %s", archive.Shadow, archive.DropPolicy)
+	}
+}
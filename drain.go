@@ -1,14 +1,21 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lineparser"
 	"github.com/kr/logfmt"
 	metrics "github.com/rcrowley/go-metrics"
 )
@@ -17,6 +24,28 @@ var (
 	TokenPrefix = []byte("t.")
 	Heroku      = []byte("heroku")
 
+	// What to do with the points already parsed from a body that could not
+	// be read completely (e.g. the client went away): "keep" or "discard".
+	BodyReadErrorPolicy = os.Getenv("BODY_READ_ERROR_POLICY")
+
+	// Longest message parsed, in bytes, and what to do with longer ones:
+	// "truncate" (the default) or "drop". 0 turns the limit off.
+	MaxLineBytes  = parseIntSetting("MAX_LINE_BYTES", os.Getenv("MAX_LINE_BYTES"), 10240)
+	MaxLinePolicy = os.Getenv("MAX_LINE_POLICY")
+
+	// Users allowed to override the drain's token with the syslog name, as
+	// "<user>,...". Anyone may when it's unset.
+	TokenOverridePrincipals = parseSet(os.Getenv("TOKEN_OVERRIDE_PRINCIPALS"))
+
+	// Largest logplex frame accepted, in bytes
+	MaxFrameBytes = int64(parseIntSetting("MAX_FRAME_BYTES", os.Getenv("MAX_FRAME_BYTES"), 1<<20))
+
+	// How dyno error messages are stored, globally and per token as
+	// "<token>=<mode>,...": "raw" (the default) or "fingerprint", which
+	// keeps only their first 64 characters and a hash of the whole message.
+	DynoMessageMode  = os.Getenv("DYNO_MESSAGE_MODE")
+	DynoMessageModes = parseKeyValueList(os.Getenv("DYNO_MESSAGE_MODES"))
+
 	// go-metrics Instruments
 	wrongMethodErrorCounter    = metrics.GetOrRegisterCounter("lumbermill.errors.drain.wrong.method", metrics.DefaultRegistry)
 	authFailureCounter         = metrics.GetOrRegisterCounter("lumbermill.errors.auth.failure", metrics.DefaultRegistry)
@@ -26,6 +55,9 @@ var (
 	timeParsingErrorCounter    = metrics.GetOrRegisterCounter("lumbermill.errors.time.parse", metrics.DefaultRegistry)
 	logfmtParsingErrorCounter  = metrics.GetOrRegisterCounter("lumbermill.errors.logfmt.parse", metrics.DefaultRegistry)
 	droppedErrorCounter        = metrics.GetOrRegisterCounter("lumbermill.errors.dropped", metrics.DefaultRegistry)
+	bodyReadErrorCounter       = metrics.GetOrRegisterCounter("lumbermill.errors.body.read", metrics.DefaultRegistry)
+	bodyReadDiscardedCounter   = metrics.GetOrRegisterCounter("lumbermill.errors.body.read.discarded", metrics.DefaultRegistry)
+	frameTooLargeCounter       = metrics.GetOrRegisterCounter("lumbermill.errors.frame.too_large", metrics.DefaultRegistry)
 	batchCounter               = metrics.GetOrRegisterCounter("lumbermill.batch", metrics.DefaultRegistry)
 	linesCounter               = metrics.GetOrRegisterCounter("lumbermill.lines", metrics.DefaultRegistry)
 	routerErrorLinesCounter    = metrics.GetOrRegisterCounter("lumbermill.lines.router.error", metrics.DefaultRegistry)
@@ -36,10 +68,70 @@ var (
 	dynoLoadLinesCounter       = metrics.GetOrRegisterCounter("lumbermill.lines.dyno.load", metrics.DefaultRegistry)
 	unknownHerokuLinesCounter  = metrics.GetOrRegisterCounter("lumbermill.lines.unknown.heroku", metrics.DefaultRegistry)
 	unknownUserLinesCounter    = metrics.GetOrRegisterCounter("lumbermill.lines.unknown.user", metrics.DefaultRegistry)
+	truncatedLinesCounter      = metrics.GetOrRegisterCounter("lumbermill.lines.truncated", metrics.DefaultRegistry)
+	tooLongLinesCounter        = metrics.GetOrRegisterCounter("lumbermill.lines.too_long.dropped", metrics.DefaultRegistry)
+	tokenOverrideLinesCounter  = metrics.GetOrRegisterCounter("lumbermill.lines.token.override", metrics.DefaultRegistry)
+	multiTokenBatchCounter     = metrics.GetOrRegisterCounter("lumbermill.batches.multi_token", metrics.DefaultRegistry)
 	parseTimer                 = metrics.GetOrRegisterTimer("lumbermill.batches.parse.time", metrics.DefaultRegistry)
 	batchSizeHistogram         = metrics.GetOrRegisterHistogram("lumbermill.batches.sizes", metrics.DefaultRegistry, metrics.NewUniformSample(100))
+
+	overrideQuarantinedLinesCounter = metrics.GetOrRegisterCounter("lumbermill.lines.token.override.quarantined", metrics.DefaultRegistry)
+	tokenNotAllowedLinesCounter     = metrics.GetOrRegisterCounter("lumbermill.lines.token.not_allowed", metrics.DefaultRegistry)
+	dynoRestartLinesCounter         = metrics.GetOrRegisterCounter("lumbermill.lines.dyno.restart", metrics.DefaultRegistry)
+	dynoKillLinesCounter            = metrics.GetOrRegisterCounter("lumbermill.lines.dyno.kill", metrics.DefaultRegistry)
+	oneOffLinesCounter              = metrics.GetOrRegisterCounter("lumbermill.lines.dyno.oneoff", metrics.DefaultRegistry)
+	logplexErrorLinesCounter        = metrics.GetOrRegisterCounter("lumbermill.lines.logplex.error", metrics.DefaultRegistry)
 )
 
+// Methods a drain URL answers, POST for batches and the rest for probes
+const drainAllowedMethods = "POST, HEAD, GET, OPTIONS"
+
+// Answers the probes a drain URL gets when it's added or checked (HEAD, GET
+// and OPTIONS requests, and empty POSTs), returning whether r was one.
+// Probes are counted as lumbermill.drain.probes.<method>.
+func serveDrainProbe(w http.ResponseWriter, r *http.Request) bool {
+	switch r.Method {
+	case "HEAD", "GET", "OPTIONS":
+	case "POST":
+		if r.Body != nil && r.Body != http.NoBody {
+			return false
+		}
+	default:
+		return false
+	}
+	metrics.GetOrRegisterCounter("lumbermill.drain.probes."+strings.ToLower(r.Method), metrics.DefaultRegistry).Inc(1)
+	w.Header().Set(requestIdHeader, requestId(r))
+	w.Header().Set("Allow", drainAllowedMethods)
+	w.Header().Set("Content-Length", "0")
+	if r.Method == "HEAD" || r.Method == "GET" {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	return true
+}
+
+// What parseLines needs to know about the drain request
+type drainContext struct {
+	token          string // The drain's token, which lines may override
+	requestId      string
+	allowOverrides bool            // Whether lines may override the token
+	allowedTokens  map[string]bool // Tokens lines may be for, any when nil
+	dedup          *DynoErrorDedup // Coalesces repeated dyno errors, nil when off
+	memory         *MemorySamples  // Latest memory samples of dynos, attached to their errors
+	restarts       *DynoRestarts   // Latest dyno restarts, which router errors are related to
+	formations     *Formations     // Dyno sizes of apps, nil unless they're looked up
+	geoip          *GeoIP          // Locates router clients, nil unless configured
+	patterns       *SeenPatterns   // Fingerprints user lines, nil unless configured
+	reports        *Reports        // Counts deploys, nil without daily reports
+}
+
+// Whether lines of a request authenticated as principal may override the
+// drain's token
+func overridesAllowed(principal string) bool {
+	return len(TokenOverridePrincipals) == 0 || (principal != "" && TokenOverridePrincipals[principal])
+}
+
 // Dyno's are generally reported as "<type>.<#>"
 // Extract the <type> and return it
 func dynoType(what string) string {
@@ -47,41 +139,422 @@ func dynoType(what string) string {
 	return s[0]
 }
 
-func handleLogFmtParsingError(msg []byte, err error) {
+// The message stored for a dyno error of token, per its message mode
+func dynoMessage(token string, msg []byte) string {
+	if settingFor(DynoMessageModes, token, DynoMessageMode) != "fingerprint" {
+		return string(msg)
+	}
+	return fingerprintMessage(msg)
+}
+
+// The first 64 characters of msg and the start of its SHA-256, so equal
+// messages can still be grouped without storing them
+func fingerprintMessage(msg []byte) string {
+	sum := sha256.Sum256(msg)
+	prefix := string(msg)
+	if utf8.RuneCountInString(prefix) > 64 {
+		prefix = string([]rune(prefix)[:64])
+	}
+	return prefix + " sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// Per batch tallies of the hot path line counters. They're added to the
+// shared counters once per batch, rather than contending on them per line.
+type lineCounts struct {
+	tokenMissing  int64
+	routerError   int64
+	router        int64
+	routerBlank   int64
+	dynoError     int64
+	dynoMem       int64
+	dynoLoad      int64
+	unknownHeroku int64
+	unknownUser   int64
+	truncated     int64
+	tooLong       int64
+	tokenOverride int64
+	multiToken    bool // The token changed part way through the batch
+
+	overrideQuarantined int64
+	tokenNotAllowed     int64
+	dynoErrorDeduped    int64
+	dynoRestart         int64
+	dynoKill            int64
+	oneOff              int64
+	skipped             [numSeries]int64 // Lines skipped per series by the token's series filter
+	logplexError        int64
+	plugin              int64
+	userPattern         int64
+
+	runs []tokenRun // Lines per token, for the top tokens
+}
+
+// Tallies a line for token
+func (c *lineCounts) line(token string) {
+	if n := len(c.runs); n == 0 || c.runs[n-1].token != token {
+		c.runs = append(c.runs, tokenRun{token: token})
+	}
+	c.runs[len(c.runs)-1].lines++
+}
+
+// Tallies a parse error of the last line
+func (c *lineCounts) parseError() {
+	if n := len(c.runs); n > 0 {
+		c.runs[n-1].errors++
+	}
+}
+
+// Lines that couldn't be parsed
+func (c *lineCounts) parseErrors() int {
+	n := 0
+	for _, run := range c.runs {
+		n += int(run.errors)
+	}
+	return n
+}
+
+func incIfNonZero(counter metrics.Counter, n int64) {
+	if n != 0 {
+		counter.Inc(n)
+	}
+}
+
+func (c *lineCounts) flush() {
+	incIfNonZero(tokenMissingCounter, c.tokenMissing)
+	incIfNonZero(routerErrorLinesCounter, c.routerError)
+	incIfNonZero(routerLinesCounter, c.router)
+	incIfNonZero(routerBlankLinesCounter, c.routerBlank)
+	incIfNonZero(dynoErrorLinesCounter, c.dynoError)
+	incIfNonZero(dynoMemLinesCounter, c.dynoMem)
+	incIfNonZero(dynoLoadLinesCounter, c.dynoLoad)
+	incIfNonZero(unknownHerokuLinesCounter, c.unknownHeroku)
+	incIfNonZero(unknownUserLinesCounter, c.unknownUser)
+	incIfNonZero(truncatedLinesCounter, c.truncated)
+	incIfNonZero(tooLongLinesCounter, c.tooLong)
+	incIfNonZero(tokenOverrideLinesCounter, c.tokenOverride)
+	incIfNonZero(overrideQuarantinedLinesCounter, c.overrideQuarantined)
+	incIfNonZero(tokenNotAllowedLinesCounter, c.tokenNotAllowed)
+	incIfNonZero(dynoErrorDedupedCounter, c.dynoErrorDeduped)
+	incIfNonZero(dynoRestartLinesCounter, c.dynoRestart)
+	incIfNonZero(dynoKillLinesCounter, c.dynoKill)
+	incIfNonZero(oneOffLinesCounter, c.oneOff)
+	for st, n := range c.skipped {
+		incIfNonZero(skippedSeriesCounters[st], n)
+	}
+	incIfNonZero(logplexErrorLinesCounter, c.logplexError)
+	incIfNonZero(pluginLinesCounter, c.plugin)
+	incIfNonZero(userPatternLinesCounter, c.userPattern)
+	if c.multiToken {
+		multiTokenBatchCounter.Inc(1)
+	}
+	topTokens.Record(c.runs)
+}
+
+// Syslog timestamp layouts, with and without microseconds
+var timestampLayouts = []string{"2006-01-02T15:04:05.000000+00:00", "2006-01-02T15:04:05+00:00"}
+
+// Parses a syslog timestamp, with or without microseconds, into
+// microseconds since the epoch
+func parseTimestamp(b []byte) (int64, error) {
+	var layout int
+	return parseTimestampLayout(b, &layout)
+}
+
+// Like parseTimestamp, trying the layout that worked for the previous line
+// of the batch first, as a batch's lines are usually formatted alike.
+func parseTimestampLayout(b []byte, layout *int) (int64, error) {
+	timeStr := string(b)
+	t, e := time.Parse(timestampLayouts[*layout], timeStr)
+	if e != nil {
+		other := 1 - *layout
+		t, e = time.Parse(timestampLayouts[other], timeStr)
+		if e != nil {
+			return 0, e
+		}
+		*layout = other
+	}
+	return t.UnixNano() / int64(time.Microsecond), nil
+}
+
+func handleLogFmtParsingError(reqId string, msg []byte, err error, counts *lineCounts) {
 	logfmtParsingErrorCounter.Inc(1)
-	log.Printf("logfmt unmarshal error(%q): %q\n", string(msg), err)
+	counts.parseError()
+	log.Printf("request_id=%s logfmt unmarshal error(%q): %q\n", reqId, string(msg), err)
+}
+
+// Response writers told how many points a drain request queued and how many
+// of its lines couldn't be parsed, e.g. the gRPC ingestion service's
+type drainResultWriter interface {
+	drainResult(points, rejected int)
 }
 
-// "Parse tree" from hell
 func (s *LumbermillServer) serveDrain(w http.ResponseWriter, r *http.Request) {
 
-	s.Add(1)
-	defer s.Done()
+	// Probes are answered even while shutting down or in maintenance, so
+	// adding a drain never fails verification
+	if r.Method != "POST" && serveDrainProbe(w, r) {
+		return
+	}
 
-	w.Header().Set("Content-Length", "0")
+	// Shutting down waits for the requests it let in
+	if !s.shutdown.unlessStopped(func() { s.Add(1) }) {
+		writeRetryableError(w, r, http.StatusServiceUnavailable, errShuttingDown, "Shutting Down", RetryAfterMin)
+		return
+	}
+	defer s.Done()
 
 	if r.Method != "POST" {
-		w.WriteHeader(http.StatusMethodNotAllowed)
+		w.Header().Set("Allow", drainAllowedMethods)
+		writeError(w, r, http.StatusMethodNotAllowed, errMethodNotAllowed, "Only POST is accepted")
 		wrongMethodErrorCounter.Inc(1)
 		return
 	}
 
 	id := r.Header.Get("Logplex-Drain-Token")
 
-	if id == "" {
-		if err := s.checkAuth(r); err != nil {
-			w.WriteHeader(http.StatusForbidden)
+	// Drains with a token needn't authenticate, but may to override it
+	var principal string
+	var resource *AddonResource
+	if id == "" || r.Header.Get("Authorization") != "" {
+		user, addon, err := s.authenticateDrain(r)
+		if err != nil && id == "" {
+			writeError(w, r, http.StatusForbidden, errAuthFailed, err.Error())
 			authFailureCounter.Inc(1)
 			return
 		}
+		principal, resource = user, addon
+	}
+
+	// With allowlists, lines are only taken from drains known to be allowed
+	// them, so leaving out the Authorization header can't get around them
+	fromCanary := canaryRequest(r)
+	if len(UserAllowedTokens) > 0 && principal == "" && !fromCanary {
+		writeError(w, r, http.StatusForbidden, errAuthFailed, "Authentication required")
+		authFailureCounter.Inc(1)
+		return
+	}
+
+	tenant := s.vhostTenant(r)
+	if tenant != nil && !tenant.allows(principal) {
+		writeError(w, r, http.StatusForbidden, errAuthFailed, "Not allowed to drain to "+r.Host)
+		authFailureCounter.Inc(1)
+		return
+	}
+
+	// An empty body (a Content-Length of 0, unlike a chunked body whose
+	// length isn't known until it's read) has no points to wait for
+	if serveDrainProbe(w, r) {
+		return
+	}
+
+	if Maintenance.On() {
+		maintenanceRejectedCounter.Inc(1)
+		writeRetryableError(w, r, http.StatusServiceUnavailable, errMaintenance, "Down for maintenance", RetryAfterMax)
+		return
 	}
 
+	// Bodies that could never fit are refused for good, rather than
+	// retried forever
+	if s.memoryBudget.TooLarge(r.ContentLength) {
+		writeError(w, r, http.StatusRequestEntityTooLarge, errTooLarge, "Body larger than the memory budget")
+		return
+	}
+	if !s.memoryBudget.Reserve(r.ContentLength) {
+		writeRetryableError(w, r, http.StatusServiceUnavailable, errOverCapacity, "Memory budget exceeded", backlogRetryAfter(s.destinations))
+		return
+	}
+	defer s.memoryBudget.Release(r.ContentLength)
+
+	// Follows the batch's points through to delivery
+	reqId := requestId(r)
+	// Done when the client goes away, or shutting down runs out of time
+	ctx := r.Context()
+	// The destinations of the drain route
+	ring := s.drainRing(r)
+
 	batchCounter.Inc(1)
 
 	parseStart := time.Now()
-	lp := lpx.NewReader(bufio.NewReader(r.Body))
+	// Sampled batches are kept to be parsed again by the candidate parser
+	var body io.Reader = r.Body
+	var sample *bytes.Buffer
+	if sampleParserDiff() {
+		sample = getBuffer()
+		body = io.TeeReader(r.Body, sample)
+	}
+	br := getBufioReader(body)
+	defer putBufioReader(br)
+	lp := lpx.NewReader(newFrameLimitReader(br, MaxFrameBytes))
+	batch := new(pointBatch)
+	counts := lineCounts{}
+
+	drain := drainContext{token: id, requestId: reqId, allowOverrides: overridesAllowed(principal), dedup: s.dedup, memory: s.memorySamples, restarts: s.restarts, formations: s.formations, geoip: s.geoip, patterns: s.patterns, reports: s.reports}
+	if len(UserAllowedTokens) > 0 {
+		// Users without an allowlist may send no lines at all
+		drain.allowedTokens = UserAllowedTokens[principal]
+		if drain.allowedTokens == nil {
+			drain.allowedTokens = map[string]bool{}
+		}
+	}
+	if fromCanary {
+		drain.allowedTokens = map[string]bool{id: true}
+	}
+	if resource != nil {
+		// An add-on's lines are all for its own token
+		drain.token, drain.allowOverrides = resource.Token, false
+		drain.allowedTokens = map[string]bool{resource.Token: true}
+	}
+	linesCounterInc, err := parseLinesSafely(lp, drain, batch, &counts)
+	if err != nil {
+		// The points parsed so far are dropped, as logplex will retry the batch
+		batch.Discard()
+		writeError(w, r, http.StatusInternalServerError, errInternal, err.Error())
+		return
+	}
+
+	linesCounter.Inc(int64(linesCounterInc))
+	counts.flush()
+
+	batchSizeHistogram.Update(int64(linesCounterInc))
+
+	faults.Delay()
+	parseTimer.UpdateSince(parseStart)
+	Flags.TimeSince("lumbermill.batches.parse.time", drain.token, parseStart)
+	if sample != nil {
+		go func() {
+			defer putBuffer(sample)
+			diffParsers(sample.Bytes(), drain)
+		}()
+	}
+
+	// Malformed frames end the batch like they always have, but failing to
+	// read the body is accounted for separately.
+	var readErr error
+	if err := lp.Err(); err != nil {
+		if err == errFrameTooLarge {
+			// The points parsed so far are dropped, as logplex will retry
+			// the batch, and they haven't been through the checks below
+			frameTooLargeCounter.Inc(1)
+			batch.Discard()
+			writeError(w, r, http.StatusRequestEntityTooLarge, errTooLarge, err.Error())
+			return
+		}
+		if _, malformed := err.(*strconv.NumError); !malformed {
+			bodyReadErrorCounter.Inc(1)
+			if BodyReadErrorPolicy == "discard" || len(batch.points) == 0 {
+				// Nothing is delivered, so the client may send it all again
+				bodyReadDiscardedCounter.Inc(int64(batch.Discard()))
+				writeError(w, r, http.StatusBadRequest, errBodyRead, err.Error())
+				return
+			}
+			// The points read are delivered as usual. Sending the batch
+			// again would deliver them twice, so the response says how much
+			// of it was.
+			readErr = err
+		}
+	}
+
+	// Tenants may only send lines for their own tokens
+	if counts.tokenNotAllowed > 0 && AllowedTokensPolicy == "reject" {
+		batch.Discard()
+		writeError(w, r, http.StatusForbidden, errTokenNotAllowed, "Lines for tokens the user may not send")
+		return
+	}
+
+	// logplex holds on to batches for destinations in maintenance, while
+	// other tokens flow as usual
+	if batch.InMaintenance(ring) {
+		maintenanceRejectedCounter.Inc(1)
+		batch.Discard()
+		writeRetryableError(w, r, http.StatusServiceUnavailable, errMaintenance, "A destination of the batch is down for maintenance", RetryAfterMax)
+		return
+	}
+
+	// Paused tokens' points are dropped, or the batch refused so logplex
+	// holds on to it until they're resumed
+	if s.paused.Filter(batch) {
+		pausedRejectedCounter.Inc(1)
+		batch.Discard()
+		writeRetryableError(w, r, http.StatusServiceUnavailable, errPaused, "Ingestion is paused for a token of the batch", RetryAfterMax)
+		return
+	}
+
+	faults.Drop(batch)
+	batch.RunScripts()
+	batch.PrefixSeries(tenant.seriesPrefix())
+	s.throughput.Record(batch.points)
+	s.cardinality.Record(batch.points)
+	s.slos.Record(batch.points)
+	s.reports.Record(batch.points)
+	s.backpressure.Record(batch.points)
+	s.processTypes.Record(batch.points)
+	// After the aggregators, which expect the columns' own types
+	batch.Coerce()
+	delivered := len(batch.points)
+	s.derived.Record(counts.runs, ring, tenant.seriesPrefix())
+	var pending *pendingAck
+	var ackId string
+	if atLeastOnce(drain.token) {
+		ackId = newAckId(reqId)
+		pending = acks.Expect(ackId, batch.Relabel(reqId, ackId))
+	}
+	if err := batch.Flush(ctx, ring); err != nil {
+		if pending != nil {
+			acks.Forget(ackId)
+		}
+		writeRetryableError(w, r, http.StatusServiceUnavailable, errCanceled, err.Error(), RetryAfterMin)
+		return
+	}
+	if pending != nil {
+		if err := acks.Wait(ctx, ackId, pending, AckTimeout); err != nil {
+			writeRetryableError(w, r, http.StatusServiceUnavailable, errUndelivered, err.Error(), RetryAfterMin)
+			return
+		}
+	}
+
+	if rw, ok := w.(drainResultWriter); ok {
+		rw.drainResult(delivered, counts.parseErrors())
+	}
+	w.Header().Set(requestIdHeader, reqId)
+	if readErr != nil {
+		writePartialBatch(w, reqId, linesCounterInc, delivered, readErr)
+		return
+	}
+	w.Header().Set("Content-Length", "0")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Body of the response to a batch only partly read
+type partialBatchResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestId string `json:"request_id"`
+	Lines     int    `json:"lines"`  // Lines read
+	Points    int    `json:"points"` // Points delivered from them
+}
+
+// Accepts a batch whose body couldn't be read completely, saying how many
+// of its lines were read and delivered
+func writePartialBatch(w http.ResponseWriter, reqId string, lines, points int, err error) {
+	response, _ := json.Marshal(partialBatchResponse{Code: errBodyRead, Message: err.Error(), RequestId: reqId, Lines: lines, Points: points})
+	log.Printf("request_id=%s at=partial_batch lines=%d points=%d err=%q\n", reqId, lines, points, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(response)))
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(response)
+}
 
+// "Parse tree" from hell
+//
+// Parses the lines of a drain body into batch, returning how many lines
+// there were.
+func parseLines(lp *lpx.Reader, drain drainContext, batch *pointBatch, counts *lineCounts) int {
+	reqId := drain.requestId
 	linesCounterInc := 0
+	timestampLayout := 0
+	override := ""
+	previous := ""
+	var userPatterns patternCounts
 
 	for lp.Next() {
 		linesCounterInc += 1
@@ -89,139 +562,279 @@ func (s *LumbermillServer) serveDrain(w http.ResponseWriter, r *http.Request) {
 
 		// If the syslog Name Header field contains what looks like a log token,
 		// let's assume it's an override of the id and we're getting the data from the magic
-		// channel
+		// channel. The override only applies to its own line.
+		id := drain.token
 		if bytes.HasPrefix(header.Name, TokenPrefix) {
-			id = string(header.Name)
+			counts.tokenOverride++
+			if !drain.allowOverrides {
+				counts.overrideQuarantined++
+				if Debug.On() {
+					log.Printf("request_id=%s Quarantined token override to %s\n", reqId, header.Name)
+				}
+				continue
+			}
+			// Most batches carry one token, so only allocate it when it changes
+			if string(header.Name) != override {
+				override = string(header.Name)
+			}
+			id = override
+		}
+		if linesCounterInc > 1 && id != previous {
+			counts.multiToken = true
 		}
+		previous = id
 
 		// If we still don't have an id, throw an error and try the next line
 		if id == "" {
-			tokenMissingCounter.Inc(1)
+			counts.tokenMissing++
 			continue
 		}
 
-		destination := s.hashRing.Get(id)
+		if drain.allowedTokens != nil && !drain.allowedTokens[id] {
+			counts.tokenNotAllowed++
+			continue
+		}
+		counts.line(id)
+		series := seriesFilters[id]
 
 		msg := lp.Bytes()
+		truncated := false
+		if MaxLineBytes > 0 && len(msg) > MaxLineBytes {
+			if MaxLinePolicy == "drop" {
+				counts.tooLong++
+				continue
+			}
+			counts.truncated++
+			msg = msg[:MaxLineBytes]
+			truncated = true
+		}
+
+		// Tokens with a parsing plugin have it try their lines first
+		if parser := tokenLineParsers[id]; parser != nil {
+			timestamp, e := parseTimestampLayout(header.Time, &timestampLayout)
+			if e != nil {
+				timeParsingErrorCounter.Inc(1)
+				counts.parseError()
+				log.Printf("request_id=%s Error Parsing Time(%s): %q\n", reqId, string(header.Time), e)
+				continue
+			}
+			line := lineparser.Line{
+				Token:     id,
+				Hostname:  string(header.Hostname),
+				Name:      string(header.Name),
+				Procid:    string(header.Procid),
+				Timestamp: timestamp,
+				Msg:       msg,
+			}
+			if points, handled := parser.ParseLine(line); handled {
+				counts.plugin++
+				postPluginPoints(id, reqId, timestamp, points, series, batch, counts)
+				continue
+			}
+		}
+
 		switch {
 		case bytes.Equal(header.Name, Heroku), bytes.HasPrefix(header.Name, TokenPrefix):
-			timeStr := string(lp.Header().Time)
-			t, e := time.Parse("2006-01-02T15:04:05.000000+00:00", timeStr)
+			timestamp, e := parseTimestampLayout(header.Time, &timestampLayout)
 			if e != nil {
-				t, e = time.Parse("2006-01-02T15:04:05+00:00", timeStr)
-				if e != nil {
-					timeParsingErrorCounter.Inc(1)
-					log.Printf("Error Parsing Time(%s): %q\n", string(lp.Header().Time), e)
-					continue
-				}
+				timeParsingErrorCounter.Inc(1)
+				counts.parseError()
+				log.Printf("request_id=%s Error Parsing Time(%s): %q\n", reqId, string(header.Time), e)
+				continue
 			}
 
-			timestamp := t.UnixNano() / int64(time.Microsecond)
-
 			pid := string(header.Procid)
 			switch pid {
 			case "router":
 
-				switch {
+				switch routerLineKind(msg) {
 				// router logs with a H error code in them
-				case bytes.Contains(msg, keyCodeH):
-					routerErrorLinesCounter.Inc(1)
+				case routerErrorLine:
+					counts.routerError++
+					if series.skips(EventsRouter) {
+						counts.skipped[EventsRouter]++
+						continue
+					}
 					re := routerError{}
-					err := logfmt.Unmarshal(msg, &re)
+					err := parseRouterError(msg, &re)
 					if err != nil {
-						handleLogFmtParsingError(msg, err)
+						handleLogFmtParsingError(reqId, msg, err, counts)
 						continue
 					}
-					destination.PostPoint(Point{id, EventsRouter, []interface{}{timestamp, re.Code}})
+					restartRelated := drain.restarts.Related(id, re.Code, timestamp)
+					batch.PostPoint(Point{Token: id, Type: EventsRouter, Points: []interface{}{timestamp, re.Code, re.Dyno, re.Path, dynoType(re.Dyno), restartRelated, sampledRequestId(re.RequestId, true)}, RequestId: reqId})
 
 				// If the app is blank (not pushed) we don't care
 				// do nothing atm, increment a counter
-				case bytes.Contains(msg, keyCodeBlank), bytes.Contains(msg, keyDescBlank):
-					routerBlankLinesCounter.Inc(1)
+				case routerBlankLine:
+					counts.routerBlank++
 
 				// likely a standard router log
 				default:
-					routerLinesCounter.Inc(1)
+					counts.router++
+					if series.skips(Router, EventsRouterStatus) {
+						counts.skipped[Router]++
+						continue
+					}
 					rm := routerMsg{}
-					err := logfmt.Unmarshal(msg, &rm)
+					err := parseRouterMsg(msg, &rm)
 					if err != nil {
-						handleLogFmtParsingError(msg, err)
+						handleLogFmtParsingError(reqId, msg, err, counts)
 						continue
 					}
 
-					destination.PostPoint(Point{id, Router, []interface{}{timestamp, rm.Status, rm.Service}})
+					if series.skips(Router) {
+						counts.skipped[Router]++
+					} else {
+						country, continent := drain.geoip.Locate(id, rm.Fwd)
+						batch.PostPoint(Point{Token: id, Type: Router, Points: []interface{}{timestamp, rm.Status, routerDurationValue(rm.Service), routerDurationValue(rm.Connect), dynoType(rm.Dyno), optionalString(rm.TLS), optionalString(rm.Protocol), sampledRequestId(rm.RequestId, rm.Status >= 500), country, continent}, RequestId: reqId})
+					}
+
+					// Some errors only show up as a status, without an H code
+					if statusEvent(rm.Status) && !series.skips(EventsRouterStatus) {
+						batch.PostPoint(Point{Token: id, Type: EventsRouterStatus, Points: []interface{}{timestamp, rm.Status, rm.Dyno, rm.Path, dynoType(rm.Dyno), sampledRequestId(rm.RequestId, true)}, RequestId: reqId})
+					}
 				}
 
 				// Non router logs, so either dynos, runtime, etc
 			default:
-				switch {
+				// One-off and scheduler dynos' lifecycles and errors get
+				// their own series, away from the app's dynos
+				if what := string(header.Procid); isOneOffDyno(what) {
+					if state, exitStatus, errorCode, ok := parseOneOffLine(msg); ok {
+						counts.oneOff++
+						if series.skips(OneOffDyno) {
+							counts.skipped[OneOffDyno]++
+							continue
+						}
+						batch.PostPoint(Point{Token: id, Type: OneOffDyno, Points: []interface{}{timestamp, what, dynoType(what), state, exitStatus, errorCode}, RequestId: reqId})
+						continue
+					}
+				}
+
+				switch kind := dynoLineKind(msg); kind {
 				// Dyno error messages
-				case bytes.HasPrefix(msg, dynoErrorSentinel):
-					dynoErrorLinesCounter.Inc(1)
-					de, err := parseBytesToDynoError(msg)
-					if err != nil {
-						handleLogFmtParsingError(msg, err)
+				// and dynos killed after failing to stop, which also go down
+				case dynoErrorLine, dynoKillLine:
+					if kind == dynoKillLine {
+						counts.dynoKill++
+						drain.restarts.Record(id, timestamp)
+					} else {
+						counts.dynoError++
+					}
+
+					if series.skips(EventsDyno) {
+						counts.skipped[EventsDyno]++
 						continue
 					}
 
+					de := killedDynoError
+					if kind == dynoErrorLine {
+						var err error
+						if de, err = parseBytesToDynoError(msg); err != nil {
+							handleLogFmtParsingError(reqId, msg, err, counts)
+							continue
+						}
+					}
+
 					what := string(lp.Header().Procid)
-					destination.PostPoint(
-						Point{id, EventsDyno, []interface{}{timestamp, what, "R", de.Code, string(msg), dynoType(what)}},
-					)
+					memoryTotal, memoryPctQuota := drain.memory.At(id, what, timestamp)
+					point := Point{Token: id, Type: EventsDyno, Points: []interface{}{timestamp, what, de.Type, de.Code, dynoMessage(id, msg), dynoType(what), truncated, 1, memoryTotal, memoryPctQuota}, RequestId: reqId}
+					suppressed, summary := drain.dedup.Check(point)
+					if summary != nil {
+						batch.PostPoint(*summary)
+					}
+					if suppressed {
+						counts.dynoErrorDeduped++
+						continue
+					}
+					batch.PostPoint(point)
 
 				// Dyno log-runtime-metrics memory messages
-				case bytes.Contains(msg, dynoMemMsgSentinel):
-					dynoMemLinesCounter.Inc(1)
+				case dynoMemLine:
+					counts.dynoMem++
+					if series.skips(DynoMem) {
+						counts.skipped[DynoMem]++
+						continue
+					}
 					dm := dynoMemMsg{}
 					err := logfmt.Unmarshal(msg, &dm)
 					if err != nil {
-						handleLogFmtParsingError(msg, err)
+						handleLogFmtParsingError(reqId, msg, err, counts)
 						continue
 					}
 					if dm.Source != "" {
-						destination.PostPoint(
+						drain.memory.Record(id, dm.Source, timestamp, memoryValue(dm.MemoryTotal), memoryValue(dm.MemoryQuota))
+						batch.PostPoint(
 							Point{
-								id,
-								DynoMem,
-								[]interface{}{
+								Token: id,
+								Type:  DynoMem,
+								Points: []interface{}{
 									timestamp,
 									dm.Source,
-									dm.MemoryCache,
+									memoryValue(dm.MemoryCache),
 									dm.MemoryPgpgin,
 									dm.MemoryPgpgout,
-									dm.MemoryRSS,
-									dm.MemorySwap,
-									dm.MemoryTotal,
+									memoryValue(dm.MemoryRSS),
+									memoryValue(dm.MemorySwap),
+									memoryValue(dm.MemoryTotal),
 									dynoType(dm.Source),
+									drain.formations.Size(id, dynoType(dm.Source)),
 								},
+								RequestId: reqId,
 							},
 						)
 					}
 
 					// Dyno log-runtime-metrics load messages
-				case bytes.Contains(msg, dynoLoadMsgSentinel):
-					dynoLoadLinesCounter.Inc(1)
+				case dynoLoadLine:
+					counts.dynoLoad++
+					if series.skips(DynoLoad) {
+						counts.skipped[DynoLoad]++
+						continue
+					}
 					dm := dynoLoadMsg{}
 					err := logfmt.Unmarshal(msg, &dm)
 					if err != nil {
-						handleLogFmtParsingError(msg, err)
+						handleLogFmtParsingError(reqId, msg, err, counts)
 						continue
 					}
 					if dm.Source != "" {
-						destination.PostPoint(
+						batch.PostPoint(
 							Point{
-								id,
-								DynoLoad,
-								[]interface{}{timestamp, dm.Source, dm.LoadAvg1Min, dm.LoadAvg5Min, dm.LoadAvg15Min, dynoType(dm.Source)},
+								Token:     id,
+								Type:      DynoLoad,
+								Points:    []interface{}{timestamp, dm.Source, dm.LoadAvg1Min, dm.LoadAvg5Min, dm.LoadAvg15Min, dynoType(dm.Source), drain.formations.Size(id, dynoType(dm.Source))},
+								RequestId: reqId,
 							},
 						)
 					}
 
+				// Dyno restarts, which router errors right after are related to
+				case dynoRestartLine:
+					counts.dynoRestart++
+					drain.restarts.Record(id, timestamp)
+
+				// logplex dropping lines because the drain is too slow
+				case logplexErrorLine:
+					counts.logplexError++
+					if series.skips(LogplexHealth) {
+						counts.skipped[LogplexHealth]++
+						continue
+					}
+					le, err := parseLogplexError(msg)
+					if err != nil {
+						handleLogFmtParsingError(reqId, msg, err, counts)
+						continue
+					}
+					batch.PostPoint(Point{Token: id, Type: LogplexHealth, Points: []interface{}{timestamp, le.Code, le.Dropped, string(msg)}, RequestId: reqId})
+
 				// unknown
 				default:
-					unknownHerokuLinesCounter.Inc(1)
-					if Debug {
-						log.Printf("Unknown Heroku Line - Header: PRI: %s, Time: %s, Hostname: %s, Name: %s, ProcId: %s, MsgId: %s - Body: %s",
+					counts.unknownHeroku++
+					if Debug.On() {
+						log.Printf("request_id=%s Unknown Heroku Line - Header: PRI: %s, Time: %s, Hostname: %s, Name: %s, ProcId: %s, MsgId: %s - Body: %s",
+							reqId,
 							header.PrivalVersion,
 							header.Time,
 							header.Hostname,
@@ -234,11 +847,27 @@ func (s *LumbermillServer) serveDrain(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 
-		// non heroku lines
+		// Heroku API deploy announcements, counted for the daily reports
+		case drain.reports != nil && bytes.Equal(header.Procid, API) && bytes.HasPrefix(msg, deployPrefix):
+			drain.reports.Deploy(id)
+
+		// non heroku lines, fingerprinted for some tokens
+		case drain.patterns.Enabled(id) && !series.skips(LogPatterns):
+			timestamp, e := parseTimestampLayout(header.Time, &timestampLayout)
+			if e != nil {
+				timeParsingErrorCounter.Inc(1)
+				counts.parseError()
+				log.Printf("request_id=%s Error Parsing Time(%s): %q\n", reqId, string(header.Time), e)
+				continue
+			}
+			counts.userPattern++
+			userPatterns.add(id, string(header.Procid), timestamp, msg)
+
 		default:
-			unknownUserLinesCounter.Inc(1)
-			if Debug {
-				log.Printf("Unknown User Line - Header: PRI: %s, Time: %s, Hostname: %s, Name: %s, ProcId: %s, MsgId: %s - Body: %s",
+			counts.unknownUser++
+			if Debug.On() {
+				log.Printf("request_id=%s Unknown User Line - Header: PRI: %s, Time: %s, Hostname: %s, Name: %s, ProcId: %s, MsgId: %s - Body: %s",
+					reqId,
 					header.PrivalVersion,
 					header.Time,
 					header.Hostname,
@@ -251,11 +880,6 @@ func (s *LumbermillServer) serveDrain(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	linesCounter.Inc(int64(linesCounterInc))
-
-	batchSizeHistogram.Update(int64(linesCounterInc))
-
-	parseTimer.UpdateSince(parseStart)
-
-	w.WriteHeader(http.StatusNoContent)
+	userPatterns.post(drain.patterns, reqId, batch)
+	return linesCounterInc
 }
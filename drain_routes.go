@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	// Path drains post their batches to
+	DrainPath = drainPath(os.Getenv("DRAIN_PATH"))
+
+	// Further drain paths, each routing its points to some of the InfluxDB
+	// hosts only, as "<path>=<host>|<host>,...", e.g. "/drain/staging=
+	// staging-influx:8086" beside the default path for production
+	DrainRoutes = parseKeyValueList(os.Getenv("DRAIN_ROUTES"))
+)
+
+// path, with a leading slash, or "/drain" when it's empty
+func drainPath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "/drain"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// A ring of the destinations named in hosts, "<host>|<host>...", for the
+// drain route (a path or vhost). Every host must be one of the destinations.
+func newRouteRing(route, hosts string, destinations []*Destination) *HashRing {
+	byName := make(map[string]*Destination, len(destinations))
+	for _, destination := range destinations {
+		byName[destination.Name] = destination
+	}
+	ring := NewHashRing(HashRingReplication, nil)
+	for _, host := range strings.Split(hosts, "|") {
+		if host = strings.TrimSpace(host); host == "" {
+			continue
+		}
+		destination := byName[host]
+		if destination == nil {
+			log.Fatalf("Unknown host %s for drain route %s\n", host, route)
+		}
+		ring.Add(destination)
+	}
+	return ring
+}
+
+// Serves drains posting to path, routing their points with ring. The path
+// mustn't be DrainPath or another route's.
+func (s *LumbermillServer) AddDrainRoute(path string, ring *HashRing) error {
+	path = drainPath(path)
+	if path == DrainPath || s.drainRings[path] != nil {
+		return fmt.Errorf("Drain route %s is served already", path)
+	}
+	s.drainRings[path] = ring
+	s.mux.HandleFunc(path, s.serveDrainRoute)
+	return nil
+}
+
+// The ring routing the points of drain request r: its vhost's, its
+// route's, or the default one
+func (s *LumbermillServer) drainRing(r *http.Request) *HashRing {
+	if tenant := s.vhostTenant(r); tenant != nil && tenant.ring != nil {
+		return tenant.ring
+	}
+	if ring := s.drainRings[r.URL.Path]; ring != nil {
+		return ring
+	}
+	return s.hashRing
+}
+
+func (s *LumbermillServer) serveDrainRoute(w http.ResponseWriter, r *http.Request) {
+	s.serveDrain(w, r)
+	s.recycleConnection(w)
+}
+
+// Where points derived from a token's lines, such as dedup summaries and
+// aggregates, are posted: with the ring and series prefix its drain requests
+// were last routed with, so they go to the same destinations and series as
+// the lines' own points
+type derivedRoutes struct {
+	fallback *HashRing
+	routes   sync.Map // *derivedRoute by token, for the tokens routed otherwise
+}
+
+type derivedRoute struct {
+	ring   *HashRing
+	prefix string
+}
+
+func newDerivedRoutes(fallback *HashRing) *derivedRoutes {
+	return &derivedRoutes{fallback: fallback}
+}
+
+// Records that the tokens' lines were routed with ring, their series
+// prefixed with prefix
+func (d *derivedRoutes) Record(runs []tokenRun, ring *HashRing, prefix string) {
+	route := derivedRoute{ring: ring, prefix: prefix}
+	for _, run := range runs {
+		current, found := d.routes.Load(run.token)
+		switch {
+		case route == derivedRoute{ring: d.fallback}:
+			if found {
+				d.routes.Delete(run.token)
+			}
+		case !found || *current.(*derivedRoute) != route:
+			d.routes.Store(run.token, &route)
+		}
+	}
+}
+
+// Posts a derived point to its token's destination
+func (d *derivedRoutes) Post(point Point) {
+	ring := d.fallback
+	if r, found := d.routes.Load(point.Token); found {
+		route := r.(*derivedRoute)
+		ring = route.ring
+		if point.Prefix == "" {
+			point.Prefix = route.prefix
+		}
+	}
+	if destination := ring.Get(point.Token); destination != nil {
+		destination.PostPoint(point)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestDrainPath(t *testing.T) {
+	for path, expected := range map[string]string{"": "/drain", "logs": "/logs", "/drain/prod": "/drain/prod"} {
+		if got := drainPath(path); got != expected {
+			t.Errorf("Expected %q to be %s, got %s", path, expected, got)
+		}
+	}
+}
+
+func TestDrainRoutes(t *testing.T) {
+	prod, staging := NewDestination("prod-influx", 10), NewDestination("staging-influx", 10)
+	hashRing := NewHashRing(HashRingReplication, nil)
+	hashRing.Add(prod)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	if err := server.AddDrainRoute("/drain/staging", newRouteRing("/drain/staging", "staging-influx", []*Destination{prod, staging})); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, path := range []string{DrainPath, "drain/staging"} {
+		if err := server.AddDrainRoute(path, hashRing); err == nil {
+			t.Errorf("Expected %s to be refused as a route served already", path)
+		}
+	}
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	for path, destination := range map[string]*Destination{"/drain": prod, "/drain/staging": staging} {
+		req := lumbermilltest.NewDrainRequest(path, "t.routes", lumbermilltest.SyslogLine("heroku", "router", router))
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusNoContent {
+			t.Fatalf("Expected %s to take the batch, got %d", path, recorder.Code)
+		}
+		if len(destination.points) != 1 {
+			t.Errorf("Expected the point drained to %s to go to %s", path, destination.Name)
+		}
+		<-destination.points
+
+		// Points derived from the token's lines follow them
+		server.derived.Post(Point{Token: "t.routes", Type: EventsDyno})
+		if len(destination.points) != 1 {
+			t.Errorf("Expected the derived point to go to %s too", destination.Name)
+		}
+		<-destination.points
+	}
+	server.derived.Post(Point{Token: "t.other", Type: EventsDyno})
+	if len(prod.points) != 1 {
+		t.Errorf("Expected derived points of other tokens to go to the default destinations")
+	}
+
+	req := lumbermilltest.NewDrainRequest("/drain/other", "t.routes")
+	recorder := httptest.NewRecorder()
+	server.http.Handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected an unknown route to be a 404, got %d", recorder.Code)
+	}
+}
@@ -1,61 +1,42 @@
 package main
 
 import (
+	"bufio"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
 	"net/http/httptest"
-	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	lpxgen "github.com/apg/lpxgen"
+	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lumbermilltest"
 	metrics "github.com/rcrowley/go-metrics"
 )
 
-type SleepyHandler struct {
-	Amt time.Duration
-}
-
-func (s *SleepyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	time.Sleep(s.Amt)
-	w.WriteHeader(http.StatusOK)
-}
-
-func SetupInfluxDB(handler http.Handler) *httptest.Server {
-	if handler == nil {
-		handler := http.NewServeMux()
-		handler.HandleFunc("/db", func(w http.ResponseWriter, req *http.Request) {
-			log.Printf("INFLUXDB: Got a request\n")
-			w.WriteHeader(http.StatusOK)
-		})
+// Sets up lumbermill as main does, serving with a test server
+func setupTestLumbermill(t *testing.T, influxHosts string) (*LumbermillServer, *httptest.Server, *sync.WaitGroup) {
+	testServer := httptest.NewUnstartedServer(nil)
+	lumbermill, waitGroup, err := SetupLumbermill(testServer.Config, influxHosts, true)
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	return httptest.NewTLSServer(handler)
-}
-
-func SetupLumbermill(influxHosts string) (*LumbermillServer, *httptest.Server, []*Destination, *sync.WaitGroup) {
-	hashRing, destinations, waitGroup := createMessageRoutes(influxHosts, true)
-	testServer := httptest.NewServer(nil)
-	lumbermill := NewLumbermillServer(testServer.Config, hashRing)
-	return lumbermill, testServer, destinations, waitGroup
-}
-
-func splitUrl(url string) (string, int) {
-	bits := strings.Split(url, ":")
-	port, _ := strconv.ParseInt(bits[1], 10, 16)
-	return bits[0], int(port)
+	testServer.Start()
+	return lumbermill, testServer, waitGroup
 }
 
 func TestLumbermillDrain(t *testing.T) {
 	sendBatchCount := int64(100)
 	sendPointPerBatchCount := int64(10)
 
-	influxdb := SetupInfluxDB(&SleepyHandler{2 * time.Second})
-	influxHost := strings.TrimPrefix(influxdb.URL, "https://")
+	influxdb := lumbermilltest.NewTLSFakeInfluxDB()
+	influxdb.SetDelay(2 * time.Second)
+	influxHost := influxdb.Host()
 
 	// snapshot old values
 	routerErrorsBefore := routerErrorLinesCounter.Count()
@@ -88,13 +69,26 @@ func TestLumbermillDrain(t *testing.T) {
 				t.Errorf("Expected at least one delivery")
 			}
 
-			if pointSuccess == 1 && deliverySizeHistogram.Max() != totalExpectedPoints {
+			if pointSuccess == 1 && deliverySizeHistogram.Max() < totalExpectedPoints {
 				t.Errorf("1 delivery happened, but not all points were published in that delivery. %d/%d", deliverySizeHistogram.Max(), totalExpectedPoints)
 			}
 		} else {
 			t.Errorf("No pointSuccessBefore counter registered")
 		}
 
+		// 5xx responses also emit events.router.status points
+		delivered := int64(0)
+		for _, w := range influxdb.Writes() {
+			for _, s := range w.Series {
+				if strings.HasPrefix(s.Name, "router.") {
+					delivered += int64(len(s.Points))
+				}
+			}
+		}
+		if delivered != totalExpectedPoints {
+			t.Errorf("Expected InfluxDB to receive %d router points, got %d", totalExpectedPoints, delivered)
+		}
+
 		if routerErrors > 0 {
 			t.Errorf("Some router errors were reported during the test: %d errors", routerErrors)
 		}
@@ -106,15 +100,13 @@ func TestLumbermillDrain(t *testing.T) {
 		}
 	}()
 
-	lumbermill, testServer, destinations, waitGroup := SetupLumbermill(influxHost)
-	shutdownChan := make(ShutdownChan)
+	lumbermill, testServer, waitGroup := setupTestLumbermill(t, influxHost)
 
 	defer func() {
 		influxdb.Close()
 		testServer.Close()
 	}()
 
-	go lumbermill.awaitShutdown()
 	go func() {
 		client := &http.Client{
 			Transport: &http.Transport{
@@ -127,18 +119,314 @@ func TestLumbermillDrain(t *testing.T) {
 		drainUrl := fmt.Sprintf("%s/drain", testServer.URL)
 
 		for i := 0; i < int(sendBatchCount); i++ {
-			if _, err := client.Do(gen.Generate(drainUrl)); err != nil {
+			if _, err := client.Do(lumbermilltest.Generate(gen, drainUrl)); err != nil {
 				t.Errorf("Got an error during client.Do: %q", err)
 			}
 		}
 
-		// Shutdown by calling Close() on both shutdownChan and lumbermill
-		shutdownChan.Close()
 		lumbermill.Close()
-		for _, d := range destinations {
-			d.Close()
-		}
 	}()
 
-	awaitShutdown(shutdownChan, lumbermill, waitGroup)
+	lumbermill.awaitShutdown(waitGroup)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestDrainBodyReadError(t *testing.T) {
+	defer func(policy string) { BodyReadErrorPolicy = policy }(BodyReadErrorPolicy)
+
+	line := "<158>1 2014-07-02T20:09:15.000000+00:00 host heroku router - at=info method=GET path=/ host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3"
+
+	for policy, expected := range map[string]struct{ code, points int }{"keep": {http.StatusAccepted, 1}, "discard": {http.StatusBadRequest, 0}} {
+		BodyReadErrorPolicy = policy
+
+		destination := NewDestination("test", 10)
+		hashRing := NewHashRing(1, nil)
+		hashRing.Add(destination)
+		server := NewLumbermillServer(&http.Server{}, hashRing)
+
+		readErrorsBefore := bodyReadErrorCounter.Count()
+
+		body := io.MultiReader(strings.NewReader(lumbermilltest.Frame(line)+"100 <158>1 2014"), failingReader{})
+		req, err := http.NewRequest("POST", "/drain", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Logplex-Drain-Token", "t.test")
+
+		recorder := httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+
+		if recorder.Code != expected.code {
+			t.Errorf("policy=%s: Wrong Response Code: %d", policy, recorder.Code)
+		}
+		var response partialBatchResponse
+		if policy == "keep" && (json.Unmarshal(recorder.Body.Bytes(), &response) != nil || response.Lines != 1 || response.Points != 1 || response.Code != errBodyRead) {
+			t.Errorf("policy=%s: Expected the lines and points delivered, got %s", policy, recorder.Body)
+		}
+		if bodyReadErrorCounter.Count()-readErrorsBefore != 1 {
+			t.Errorf("policy=%s: Expected body read error to be counted", policy)
+		}
+		if len(destination.points) != expected.points {
+			t.Errorf("policy=%s: Expected %d points to be posted, got %d", policy, expected.points, len(destination.points))
+		}
+	}
+}
+
+// Compares incrementing a shared counter per line with tallying per batch,
+// as serveDrain does, under parallel load.
+const benchmarkBatchSize = 100
+
+func BenchmarkLineCounterPerLine(b *testing.B) {
+	counter := metrics.NewCounter()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for i := 0; i < benchmarkBatchSize; i++ {
+				counter.Inc(1)
+			}
+		}
+	})
+}
+
+func BenchmarkLineCounterPerBatch(b *testing.B) {
+	counter := metrics.NewCounter()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counts := lineCounts{}
+			for i := 0; i < benchmarkBatchSize; i++ {
+				counts.router++
+			}
+			incIfNonZero(counter, counts.router)
+		}
+	})
+}
+
+func TestDrainFrameTooLarge(t *testing.T) {
+	destination := NewDestination("too-large-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+
+	router := lumbermilltest.SyslogLine("heroku", "router", `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`)
+	body := lumbermilltest.Body(router) + "99999999999999 <158>1 2015-03-05T18:21:34+00:00 host heroku router - at=info"
+	req, err := http.NewRequest("POST", "/drain", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Logplex-Drain-Token", "t.test")
+
+	recorder := httptest.NewRecorder()
+	server.serveDrain(recorder, req)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Wrong Response Code: %d", recorder.Code)
+	}
+	if len(destination.points) != 0 {
+		t.Errorf("Expected the lines before the frame to be dropped, as logplex sends them again")
+	}
+}
+
+func TestParseLinesSafely(t *testing.T) {
+	before := parsePanicCounter.Count()
+	body := lumbermilltest.Body(lumbermilltest.SyslogLine("heroku", "router", "at=info status=200 service=1ms"))
+	lp := lpx.NewReader(bufio.NewReader(strings.NewReader(body)))
+
+	// A nil batch panics on the first point
+	_, err := parseLinesSafely(lp, drainContext{token: "t.test", requestId: "req"}, nil, &lineCounts{})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if n := parsePanicCounter.Count() - before; n != 1 {
+		t.Errorf("Expected 1 panic to be counted, got %d", n)
+	}
+}
+
+func TestPanicSample(t *testing.T) {
+	body := lumbermilltest.Body(lumbermilltest.SyslogLine("heroku", "router", "at=info status=200 service=1ms"))
+	lp := lpx.NewReader(bufio.NewReader(strings.NewReader(body)))
+	lp.Next()
+
+	if sample := string(panicSample(lp, 1000)); !strings.HasSuffix(sample, "heroku router - at=info status=200 service=1ms") {
+		t.Errorf("Unexpected sample: %q", sample)
+	}
+	if sample := panicSample(lp, 10); len(sample) != 10 {
+		t.Errorf("Sample wasn't truncated: %q", sample)
+	}
+}
+
+func TestDrainQuarantinesUntrustedOverrides(t *testing.T) {
+	defer func(principals map[string]bool) { TokenOverridePrincipals = principals }(TokenOverridePrincipals)
+	TokenOverridePrincipals = map[string]bool{"logplex": true}
+	User, Password = "logplex", "secret"
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	for _, authenticated := range []bool{false, true} {
+		destination := NewDestination("override-test", 10)
+		hashRing := NewHashRing(1, nil)
+		hashRing.Add(destination)
+		server := NewLumbermillServer(&http.Server{}, hashRing)
+		quarantinedBefore := overrideQuarantinedLinesCounter.Count()
+
+		req := lumbermilltest.NewDrainRequest("/drain", "t.drain",
+			lumbermilltest.SyslogLine("heroku", "router", router),
+			lumbermilltest.SyslogLine("t.victim", "router", router),
+		)
+		if authenticated {
+			req.SetBasicAuth("logplex", "secret")
+		}
+		server.serveDrain(httptest.NewRecorder(), req)
+
+		tokens := make([]string, 0)
+		for len(destination.points) > 0 {
+			tokens = append(tokens, (<-destination.points).Token)
+		}
+		quarantined := overrideQuarantinedLinesCounter.Count() - quarantinedBefore
+		if authenticated && (len(tokens) != 2 || tokens[1] != "t.victim" || quarantined != 0) {
+			t.Errorf("Override by a trusted principal wasn't honored: %v", tokens)
+		}
+		if !authenticated && (len(tokens) != 1 || tokens[0] != "t.drain" || quarantined != 1) {
+			t.Errorf("Override by an anonymous drain wasn't quarantined: %v", tokens)
+		}
+	}
+}
+
+func TestDrainAllowedTokens(t *testing.T) {
+	defer func(users string, allowed map[string]map[string]bool, policy string) {
+		DrainUsers, UserAllowedTokens, AllowedTokensPolicy = users, allowed, policy
+	}(DrainUsers, UserAllowedTokens, AllowedTokensPolicy)
+	DrainUsers = "tenant=secret"
+	UserAllowedTokens = parseAllowedTokens("tenant=t.a|t.c")
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	for _, policy := range []string{"", "reject"} {
+		AllowedTokensPolicy = policy
+		destination := NewDestination("allowed-tokens-test", 10)
+		hashRing := NewHashRing(1, nil)
+		hashRing.Add(destination)
+		server := NewLumbermillServer(&http.Server{}, hashRing)
+
+		req := lumbermilltest.NewDrainRequest("/drain", "",
+			lumbermilltest.SyslogLine("t.a", "router", router),
+			lumbermilltest.SyslogLine("t.b", "router", router),
+		)
+		req.SetBasicAuth("tenant", "secret")
+		recorder := httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+
+		switch policy {
+		case "reject":
+			if recorder.Code != http.StatusForbidden || len(destination.points) != 0 {
+				t.Errorf("policy=reject: Expected the batch to be rejected, got %d and %d points", recorder.Code, len(destination.points))
+			}
+			assertErrorCode(t, recorder, errTokenNotAllowed)
+		default:
+			if recorder.Code != http.StatusNoContent || len(destination.points) != 1 || (<-destination.points).Token != "t.a" {
+				t.Errorf("Expected only t.a's line to be kept, got %d", recorder.Code)
+			}
+		}
+
+		// Leaving out the credentials doesn't get around the allowlists
+		req = lumbermilltest.NewDrainRequest("/drain", "t.b", lumbermilltest.SyslogLine("t.b", "router", router))
+		recorder = httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+		if recorder.Code != http.StatusForbidden || len(destination.points) != 0 {
+			t.Errorf("policy=%q: Expected an anonymous drain to be refused, got %d and %d points", policy, recorder.Code, len(destination.points))
+		}
+	}
+}
+
+func TestDrainProbes(t *testing.T) {
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	server.Close() // Probes are answered while shutting down too
+
+	for method, status := range map[string]int{"HEAD": 200, "GET": 200, "OPTIONS": 204} {
+		probes := metrics.GetOrRegisterCounter("lumbermill.drain.probes."+strings.ToLower(method), metrics.DefaultRegistry)
+		before := probes.Count()
+		req, _ := http.NewRequest(method, "/drain", nil)
+		recorder := httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+
+		if recorder.Code != status {
+			t.Errorf("Expected %s to be answered with %d, got %d", method, status, recorder.Code)
+		}
+		if recorder.Header().Get("Allow") != drainAllowedMethods {
+			t.Errorf("Expected %s to be answered with the allowed methods", method)
+		}
+		if probes.Count() != before+1 {
+			t.Errorf("Expected a %s probe to be counted", method)
+		}
+	}
+
+	req, _ := http.NewRequest("PUT", "/drain", strings.NewReader("x"))
+	recorder := httptest.NewRecorder()
+	server.serveDrain(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected a PUT to be refused while shutting down, got %d", recorder.Code)
+	}
+}
+
+func TestDrainEmptyPost(t *testing.T) {
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	probes := metrics.GetOrRegisterCounter("lumbermill.drain.probes.post", metrics.DefaultRegistry)
+	before, batches := probes.Count(), batchCounter.Count()
+
+	req, _ := http.NewRequest("POST", "/drain", strings.NewReader(""))
+	req.Header.Set("Logplex-Drain-Token", "t.probe")
+	recorder := httptest.NewRecorder()
+	server.serveDrain(recorder, req)
+
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected an empty POST to be answered with a 204, got %d", recorder.Code)
+	}
+	if probes.Count() != before+1 || batchCounter.Count() != batches {
+		t.Errorf("Expected an empty POST to be counted as a probe, not a batch")
+	}
+
+	req, _ = http.NewRequest("PUT", "/drain", strings.NewReader("x"))
+	recorder = httptest.NewRecorder()
+	server.serveDrain(recorder, req)
+	if recorder.Code != http.StatusMethodNotAllowed || recorder.Header().Get("Allow") != drainAllowedMethods {
+		t.Errorf("Expected a PUT to be refused with the allowed methods, got %d", recorder.Code)
+	}
+}
+
+func TestDrainContentTypes(t *testing.T) {
+	destination := NewDestination("content-types-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	body := lumbermilltest.Body(lumbermilltest.SyslogLine("heroku", "router", "at=info method=GET path=/ host=test.herokuapp.com request_id=1 fwd=\"1.1.1.1\" dyno=web.1 connect=1ms service=1ms status=200 bytes=20"))
+
+	for _, contentType := range []string{"application/logplex-1", "application/logplex-1; charset=UTF-8", "text/plain", ""} {
+		for _, chunked := range []bool{false, true} {
+			var reader io.Reader = strings.NewReader(body)
+			if chunked {
+				// Hides the length, as a chunked body without a Content-Length
+				reader = io.MultiReader(reader)
+			}
+			req, _ := http.NewRequest("POST", "/drain", reader)
+			req.Header.Set("Logplex-Drain-Token", "t.content-types")
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			if chunked {
+				req.ContentLength = -1
+			}
+			recorder := httptest.NewRecorder()
+			server.serveDrain(recorder, req)
+
+			if recorder.Code != http.StatusNoContent {
+				t.Errorf("Expected %q (chunked %t) to be accepted, got %d", contentType, chunked, recorder.Code)
+			}
+			select {
+			case <-destination.points:
+			default:
+				t.Errorf("Expected a point from %q (chunked %t)", contentType, chunked)
+			}
+		}
+	}
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Let authenticated users mint drain URLs for their apps
+var SelfServiceDrains = os.Getenv("SELF_SERVICE_DRAINS") == "true"
+
+// A self-service drain, as shown to its owner
+type drainURLResponse struct {
+	Id       string `json:"id"`
+	App      string `json:"app"`
+	Token    string `json:"token"`
+	DrainURL string `json:"drain_url"`
+}
+
+func newDrainURLResponse(resource *AddonResource, host string) drainURLResponse {
+	return drainURLResponse{Id: resource.Id, App: resource.App, Token: resource.Token, DrainURL: resource.DrainURL(host)}
+}
+
+// POST /drains?app=<app> mints a drain URL, with credentials only good for
+// sending the app's lines, GET /drains lists the caller's and
+// DELETE /drains/<id> revokes one.
+func (s *LumbermillServer) serveDrainURLs(w http.ResponseWriter, r *http.Request) {
+	if s.addons == nil || !SelfServiceDrains {
+		writeError(w, r, http.StatusNotFound, errNotFound, "Self-service drains are disabled")
+		return
+	}
+
+	user, err := s.authenticate(r)
+	if err != nil {
+		writeError(w, r, http.StatusForbidden, errAuthFailed, err.Error())
+		authFailureCounter.Inc(1)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/drains"), "/")
+	switch {
+	case r.Method == "POST" && id == "":
+		app := r.URL.Query().Get("app")
+		if app == "" {
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "app is required")
+			return
+		}
+		resource, err := s.addons.Mint(app, user)
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, errInternal, err.Error())
+			return
+		}
+		auditLog.Record(user, "drain.mint", nil, map[string]string{"id": resource.Id, "app": app, "token": resource.Token})
+		writeJSON(w, newDrainURLResponse(resource, AddonDrainHost))
+
+	case r.Method == "GET" && id == "":
+		drains := make([]drainURLResponse, 0)
+		for _, resource := range s.addons.Owned(user) {
+			drains = append(drains, newDrainURLResponse(resource, AddonDrainHost))
+		}
+		writeJSON(w, drains)
+
+	case r.Method == "DELETE" && id != "":
+		if resource := s.addons.Get(id); resource == nil || resource.Owner != user {
+			writeError(w, r, http.StatusNotFound, errNotFound, "Unknown drain")
+			return
+		}
+		if _, err := s.addons.Deprovision(id); err != nil {
+			writeError(w, r, http.StatusInternalServerError, errInternal, err.Error())
+			return
+		}
+		auditLog.Record(user, "drain.revoke", id, nil)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, errMethodNotAllowed, "Unsupported method")
+	}
+}
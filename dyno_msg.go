@@ -2,10 +2,56 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"log"
+	"os"
 	"strconv"
 	"strings"
 )
 
+// Unit memory samples are written in, "MB" (the default) or "bytes",
+// whichever unit Heroku reported them in
+var MemoryUnit = memoryUnit(os.Getenv("MEMORY_UNIT"))
+
+func memoryUnit(unit string) string {
+	switch unit {
+	case "MB", "bytes":
+		return unit
+	case "":
+	default:
+		log.Printf("Unknown memory unit (%q), using MB\n", unit)
+	}
+	return "MB"
+}
+
+// Multiples of a byte memory samples may be reported in. MB are binary
+// (MiB), like Heroku's.
+var memoryUnits = []struct {
+	suffix string
+	bytes  float64
+}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"kB", 1 << 10}, {"KB", 1 << 10}, {"B", 1}}
+
+// Parses a memory sample, e.g. "512.32MB" or "1.5GB", into MB. Bare numbers
+// are MB.
+func parseMemory(val []byte) (float64, error) {
+	s := string(val)
+	for _, unit := range memoryUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(s, unit.suffix), 64)
+			return f * unit.bytes / (1 << 20), err
+		}
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// A memory sample in MemoryUnit
+func memoryValue(mb float64) float64 {
+	if MemoryUnit == "bytes" {
+		return mb * (1 << 20)
+	}
+	return mb
+}
+
 var (
 	keySource           = []byte("source")
 	keyDyno             = []byte("dyno")
@@ -15,20 +61,38 @@ var (
 	keyMemorySwap       = []byte("memory_swap")
 	keyMemoryPgpgin     = []byte("memory_pgpgin")
 	keyMemoryPgpgout    = []byte("memory_pgpgout")
+	keyMemoryQuota      = []byte("memory_quota")
 	keyLoadAvg1Min      = []byte("load_avg_1m")
 	keyLoadAvg5Min      = []byte("load_avg_5m")
 	keyLoadAvg15Min     = []byte("load_avg_15m")
 	dynoMemMsgSentinel  = []byte("sample#memory_total")
 	dynoLoadMsgSentinel = []byte("sample#load_avg_1m")
 	dynoErrorSentinel   = []byte("Error R")
+	dynoKillSentinel    = []byte("Stopping process with SIGKILL")
 )
 
+var errDynoErrorTooShort = errors.New("dyno error too short for a code")
+
+// A dyno error's type ("R" for Heroku's R codes) and code, e.g. R10 for a
+// boot timeout
 type dynoError struct {
+	Type string
 	Code int
 }
 
+// Dynos killed after failing to stop are reported as S9, after the signal
+var killedDynoError = dynoError{Type: "S", Code: 9}
+
+// e.g. "R14"
+func (de dynoError) String() string {
+	return de.Type + strconv.Itoa(de.Code)
+}
+
 func parseBytesToDynoError(msg []byte) (dynoError, error) {
-	de := dynoError{}
+	de := dynoError{Type: "R"}
+	if len(msg) < len(dynoErrorSentinel)+2 {
+		return de, errDynoErrorTooShort
+	}
 	byteCode := msg[len(dynoErrorSentinel) : len(dynoErrorSentinel)+2]
 	code, err := strconv.Atoi(string(byteCode))
 	if err != nil {
@@ -38,6 +102,7 @@ func parseBytesToDynoError(msg []byte) (dynoError, error) {
 	return de, nil
 }
 
+// Memory samples are in MB
 type dynoMemMsg struct {
 	Source        string
 	Dyno          string
@@ -47,6 +112,7 @@ type dynoMemMsg struct {
 	MemorySwap    float64
 	MemoryPgpgin  int
 	MemoryPgpgout int
+	MemoryQuota   float64
 }
 
 func (dm *dynoMemMsg) HandleLogfmt(key, val []byte) error {
@@ -56,17 +122,19 @@ func (dm *dynoMemMsg) HandleLogfmt(key, val []byte) error {
 	case bytes.Equal(key, keyDyno):
 		dm.Dyno = string(val)
 	case bytes.HasSuffix(key, keyMemoryTotal):
-		dm.MemoryTotal, _ = strconv.ParseFloat(strings.TrimSuffix(string(val), "MB"), 64)
+		dm.MemoryTotal, _ = parseMemory(val)
 	case bytes.HasSuffix(key, keyMemoryRSS):
-		dm.MemoryRSS, _ = strconv.ParseFloat(strings.TrimSuffix(string(val), "MB"), 64)
+		dm.MemoryRSS, _ = parseMemory(val)
 	case bytes.HasSuffix(key, keyMemoryCache):
-		dm.MemoryCache, _ = strconv.ParseFloat(strings.TrimSuffix(string(val), "MB"), 64)
+		dm.MemoryCache, _ = parseMemory(val)
 	case bytes.HasSuffix(key, keyMemorySwap):
-		dm.MemorySwap, _ = strconv.ParseFloat(strings.TrimSuffix(string(val), "MB"), 64)
+		dm.MemorySwap, _ = parseMemory(val)
 	case bytes.HasSuffix(key, keyMemoryPgpgin):
 		dm.MemoryPgpgin, _ = strconv.Atoi(strings.TrimSuffix(string(val), "pages"))
 	case bytes.HasSuffix(key, keyMemoryPgpgout):
 		dm.MemoryPgpgout, _ = strconv.Atoi(strings.TrimSuffix(string(val), "pages"))
+	case bytes.HasSuffix(key, keyMemoryQuota):
+		dm.MemoryQuota, _ = parseMemory(val)
 	}
 	return nil
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// How a destination with several endpoints posts to them
+type EndpointMode string
+
+const (
+	// Each write goes to the next endpoint in turn, and on to the others
+	// when it fails
+	EndpointsRoundRobin EndpointMode = "round_robin"
+	// Each write goes to the next endpoint, and also to another when the
+	// first hasn't answered within InfluxDBRaceDelay. The first answer wins,
+	// so a write may land twice.
+	EndpointsRace EndpointMode = "race"
+)
+
+// Endpoint mode of a destination, EndpointsRoundRobin unless it's
+// configured
+func endpointMode(mode, name string) EndpointMode {
+	switch EndpointMode(mode) {
+	case EndpointsRoundRobin, EndpointsRace:
+		return EndpointMode(mode)
+	case "":
+	default:
+		log.Printf("Unknown endpoint mode (%q) for %s, using %s\n", mode, name, EndpointsRoundRobin)
+	}
+	return EndpointsRoundRobin
+}
+
+// Adds endpoints of the destination beside its host, given as
+// "<host>|<host>...", e.g. relays in front of the same cluster
+func (w *seriesWriter) setEndpoints(name, hosts string, mode EndpointMode) {
+	w.endpoints = []string{w.config.Host}
+	for _, host := range strings.Split(hosts, "|") {
+		if host = strings.Trim(host, "\t "); host != "" && host != w.config.Host {
+			w.endpoints = append(w.endpoints, host)
+		}
+	}
+	w.endpointMode = mode
+	w.failovers = metrics.GetOrRegisterCounter("lumbermill.poster.endpoints.failovers."+name, metrics.DefaultRegistry)
+	w.races = metrics.GetOrRegisterCounter("lumbermill.poster.endpoints.races."+name, metrics.DefaultRegistry)
+}
+
+// The endpoints, starting with the next one in turn
+func (w *seriesWriter) rotation() []string {
+	n := int(atomic.AddUint32(&w.nextEndpoint, 1)-1) % len(w.endpoints)
+	hosts := make([]string, 0, len(w.endpoints))
+	hosts = append(hosts, w.endpoints[n:]...)
+	return append(hosts, w.endpoints[:n]...)
+}
+
+// Whether a write that failed with err may succeed at another endpoint
+func failover(err error) bool {
+	werr, ok := err.(*backendError)
+	return !ok || !werr.permanent()
+}
+
+// Posts series to the endpoints in turn, until one of them takes them
+func (w *seriesWriter) postRoundRobin(ctx context.Context, series []*influx.Series, encoding string) (status int, err error) {
+	for i, host := range w.rotation() {
+		if i > 0 {
+			w.failovers.Inc(1)
+		}
+		status, err = w.post(ctx, host, series, encoding)
+		if err == nil || !failover(err) || ctx.Err() != nil {
+			return status, err
+		}
+	}
+	return status, err
+}
+
+// Posts series to the next endpoint, racing it with another whenever the
+// last one started is slow to answer or fails
+func (w *seriesWriter) postRace(ctx context.Context, series []*influx.Series, encoding string) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Abandons the losers
+
+	type result struct {
+		status int
+		err    error
+	}
+	hosts := w.rotation()
+	results := make(chan result, len(hosts))
+	started, pending := 0, 0
+	start := func() {
+		host := hosts[started]
+		started++
+		pending++
+		go func() {
+			status, err := w.post(ctx, host, series, encoding)
+			results <- result{status, err}
+		}()
+	}
+
+	start()
+	delay := time.NewTimer(InfluxDBRaceDelay)
+	defer delay.Stop()
+	var last result
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil || !failover(r.err) {
+				return r.status, r.err
+			}
+			last = r
+			if started < len(hosts) {
+				w.failovers.Inc(1)
+				start()
+			}
+		case <-delay.C:
+			if started < len(hosts) {
+				w.races.Inc(1)
+				start()
+				delay.Reset(InfluxDBRaceDelay)
+			}
+		}
+	}
+	return last.status, last.err
+}
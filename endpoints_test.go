@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestEndpointsRoundRobinFailsOver(t *testing.T) {
+	first, second := lumbermilltest.NewFakeInfluxDB(), lumbermilltest.NewFakeInfluxDB()
+	defer first.Close()
+	defer second.Close()
+	writer := newTestWriter(first, "")
+	writer.setEndpoints("endpoints-test", second.Host(), EndpointsRoundRobin)
+
+	for i := 0; i < 4; i++ {
+		if err := writer.Write(testSeries()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	if len(first.Writes()) != 2 || len(second.Writes()) != 2 {
+		t.Errorf("Expected writes to take turns, got %d and %d", len(first.Writes()), len(second.Writes()))
+	}
+
+	failovers := writer.failovers.Count()
+	first.Respond(lumbermilltest.Response{Status: 503})
+	if err := writer.Write(testSeries()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(second.Writes()) != 3 || writer.failovers.Count() != failovers+1 {
+		t.Errorf("Expected the write to fail over to the second endpoint")
+	}
+
+	first.Respond(lumbermilltest.Response{Status: 400, Body: "unable to parse"})
+	writer.Write(testSeries())
+	if err := writer.Write(testSeries()); err == nil {
+		t.Errorf("Expected a refused write not to fail over")
+	}
+}
+
+func TestEndpointsRace(t *testing.T) {
+	slow, fast := lumbermilltest.NewFakeInfluxDB(), lumbermilltest.NewFakeInfluxDB()
+	defer slow.Close()
+	defer fast.Close()
+	slow.SetDelay(200 * time.Millisecond)
+	writer := newTestWriter(slow, "")
+	writer.setEndpoints("endpoints-test", fast.Host(), EndpointsRace)
+
+	defer func(delay time.Duration) { InfluxDBRaceDelay = delay }(InfluxDBRaceDelay)
+	InfluxDBRaceDelay = 10 * time.Millisecond
+	races := writer.races.Count()
+
+	start := time.Now()
+	if err := writer.Write(testSeries()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if took := time.Since(start); took > 150*time.Millisecond {
+		t.Errorf("Expected the fast endpoint to win, took %s", took)
+	}
+	if len(fast.Writes()) != 1 || writer.races.Count() != races+1 {
+		t.Errorf("Expected the write to be raced to the fast endpoint, got %d writes", len(fast.Writes()))
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Machine readable error codes returned in error response bodies
+const (
+	errAuthFailed       = "auth_failed"
+	errBadRequest       = "bad_request"
+	errBodyRead         = "body_read_failed"
+	errCanceled         = "canceled"
+	errInternal         = "internal_error"
+	errMaintenance      = "maintenance"
+	errInsufficientRole = "insufficient_role"
+	errMethodNotAllowed = "method_not_allowed"
+	errNotFound         = "not_found"
+	errOverCapacity     = "over_capacity"
+	errPaused           = "paused"
+	errShuttingDown     = "shutting_down"
+	errTokenNotAllowed  = "token_not_allowed"
+	errTooLarge         = "too_large"
+	errUndelivered      = "undelivered"
+)
+
+const requestIdHeader = "X-Request-Id"
+
+// Body of every error response
+type errorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message,omitempty"`
+	RequestId string `json:"request_id"`
+
+	// Seconds to wait before retrying, as in the Retry-After header
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// Longest request id accepted from upstream
+const maxRequestIdLength = 200
+
+// Returns the request id set by the Heroku router (or any upstream proxy),
+// generating one if none was given, or it isn't one we'd log as is.
+func requestId(r *http.Request) string {
+	if id := r.Header.Get(requestIdHeader); validRequestId(id) {
+		return id
+	}
+	id := newRequestId()
+	r.Header.Set(requestIdHeader, id)
+	return id
+}
+
+// Whether id is short and made of characters safe in logs and keys, such
+// as the router's UUIDs
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '@', c == '+', c == '/', c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// Writes a small JSON body describing the error and logs it along with the
+// request id, so drain errors can be correlated with our logs.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code string, message string) {
+	writeRetryableError(w, r, status, code, message, 0)
+}
+
+// Like writeError, telling the client when to retry with a Retry-After
+// header, unless after is 0.
+func writeRetryableError(w http.ResponseWriter, r *http.Request, status int, code string, message string, after time.Duration) {
+	id := requestId(r)
+	seconds := int(math.Ceil(after.Seconds()))
+	response, _ := json.Marshal(errorResponse{Code: code, Message: message, RequestId: id, RetryAfter: seconds})
+
+	log.Printf("request_id=%s at=error status=%d code=%s message=%q\n", id, status, code, message)
+
+	headers := w.Header()
+	if seconds > 0 {
+		headers.Set("Retry-After", strconv.Itoa(seconds))
+	}
+	headers.Set(requestIdHeader, id)
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Content-Length", fmt.Sprintf("%d", len(response)))
+	w.WriteHeader(status)
+	w.Write(response)
+}
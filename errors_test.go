@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestId(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		kept   bool
+	}{
+		{"f4ac7ea4-c6d3-4ff5-a1c5-1d5a7e1d9b27", true},
+		{"abc/def=", true},
+		{"", false},
+		{"bad id", false},
+		{"bad\nid=1 at=forged", false},
+		{strings.Repeat("a", maxRequestIdLength+1), false},
+	} {
+		r, _ := http.NewRequest("POST", "/drain", nil)
+		r.Header.Set(requestIdHeader, tc.header)
+		id := requestId(r)
+		if kept := id == tc.header; kept != tc.kept {
+			t.Errorf("%q: expected kept=%t, got %q", tc.header, tc.kept, id)
+		}
+		if !validRequestId(id) || r.Header.Get(requestIdHeader) != id {
+			t.Errorf("%q: expected a valid id on the request, got %q", tc.header, id)
+		}
+	}
+}
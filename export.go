@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// An exported file: a series' points of a token and day
+type exportPartition struct {
+	series SeriesType
+	date   string
+	token  string
+}
+
+func (p exportPartition) path(dir, name string) string {
+	return filepath.Join(dir, p.series.Name(), "date="+p.date, "token="+strings.Replace(p.token, "/", "_", -1), name+".parquet")
+}
+
+// Exports the points of archive files (see ARCHIVE_PATH) to Parquet files
+// partitioned by series, date and token, for Athena or BigQuery
+func runExport(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(out)
+	dir := flags.String("out", "export", "Directory to write <series>/date=<date>/token=<token>/<file>.parquet files to")
+	flags.Usage = func() {
+		fmt.Fprintln(out, "usage: lumbermill export [options] FILE...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	for _, path := range flags.Args() {
+		points, files, nulled, err := exportFile(path, *dir)
+		if err != nil {
+			fmt.Fprintf(out, "Error exporting %s: %s\n", path, err)
+			return 1
+		}
+		fmt.Fprintf(out, "%s: exported %d points to %d files", path, points, files)
+		if nulled > 0 {
+			fmt.Fprintf(out, ", nulling %d values of the wrong type", nulled)
+		}
+		fmt.Fprintln(out)
+	}
+	return 0
+}
+
+// The name of the files exported from the archive file at path, its hour
+// (<date>-<hour>), so the files of a partition don't collide
+func exportName(path string) string {
+	hour := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return filepath.Base(filepath.Dir(path)) + "-" + hour
+}
+
+// Exports the points of the archive file at path, returning how many there
+// were, how many files they went to and how many values were nulled
+func exportFile(path, dir string) (int, int, int, error) {
+	partitions := make(map[exportPartition][][]interface{})
+	points, err := readArchive(path, func(point Point) error {
+		date := time.Unix(0, point.Points[0].(int64)*int64(time.Microsecond)).UTC().Format("2006-01-02")
+		partition := exportPartition{point.Type, date, point.Token}
+		partitions[partition] = append(partitions[partition], point.Points)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	name := exportName(path)
+	nulled := 0
+	for partition, records := range partitions {
+		n, err := writeExportPartition(partition.path(dir, name), partition.series, records)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		nulled += n
+	}
+	return points, len(partitions), nulled, nil
+}
+
+// The type a series' column is exported as. Router times are doubles, as
+// they're floats when ROUTER_DURATION_UNIT is "s", so a series' schema is
+// the same whatever it was written with.
+func exportColumnType(st SeriesType, i int) ColumnType {
+	if st == Router && (i == 2 || i == 3) {
+		return ColumnFloat
+	}
+	return seriesColumnTypes[st][i]
+}
+
+// The Parquet schema of a series: its time as a timestamp, and its other
+// columns as optional fields of their exportColumnType
+func exportSchema(st SeriesType) *parquet.Schema {
+	fields := make(parquet.Group, len(st.Columns()))
+	for i, column := range st.Columns() {
+		var node parquet.Node
+		switch t := exportColumnType(st, i); {
+		case i == 0:
+			fields[column] = parquet.Timestamp(parquet.Microsecond)
+			continue
+		case t == ColumnFloat:
+			node = parquet.Leaf(parquet.DoubleType)
+		case t == ColumnBool:
+			node = parquet.Leaf(parquet.BooleanType)
+		case t == ColumnString:
+			node = parquet.String()
+		default:
+			node = parquet.Int(64)
+		}
+		fields[column] = parquet.Optional(node)
+	}
+	return parquet.NewSchema(st.Name(), fields)
+}
+
+// Writes the records of a partition to path, sorted by time, returning how
+// many values were nulled as they couldn't be their column's type
+func writeExportPartition(path string, st SeriesType, records [][]interface{}) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	schema := exportSchema(st)
+	columns := make([]int, len(st.Columns())) // Leaf column of each column
+	for i, column := range st.Columns() {
+		leaf, _ := schema.Lookup(column)
+		columns[i] = leaf.ColumnIndex
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i][0].(int64) < records[j][0].(int64) })
+	builder := parquet.NewRowBuilder(schema)
+	rows := make([]parquet.Row, len(records))
+	nulled := 0
+	for r, values := range records {
+		builder.Reset()
+		for i, v := range values {
+			if v == nil || i >= len(columns) {
+				continue
+			}
+			if i > 0 {
+				var ok bool
+				if v, ok = coerceValue(v, exportColumnType(st, i)); !ok {
+					nulled++
+					continue
+				}
+			}
+			builder.Add(columns[i], parquet.ValueOf(v))
+		}
+		rows[r] = builder.Row()
+	}
+
+	w := parquet.NewWriter(f, schema, parquet.Compression(&parquet.Snappy))
+	if _, err := w.WriteRows(rows); err != nil {
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return nulled, f.Close()
+}
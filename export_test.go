@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Reads the schema and rows of a Parquet file
+func readParquetFile(t *testing.T, path string) (*parquet.Schema, []parquet.Row) {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := parquet.NewReader(f)
+	defer r.Close()
+	rows := make([]parquet.Row, r.NumRows())
+	if n, _ := r.ReadRows(rows); n != len(rows) {
+		t.Fatalf("Read %d of %d rows of %s", n, len(rows), path)
+	}
+	return r.Schema(), rows
+}
+
+// The value of column in row
+func parquetValue(t *testing.T, schema *parquet.Schema, row parquet.Row, column string) parquet.Value {
+	leaf, ok := schema.Lookup(column)
+	if !ok {
+		t.Fatalf("No %s column in %s", column, schema)
+	}
+	for _, v := range row {
+		if v.Column() == leaf.ColumnIndex {
+			return v
+		}
+	}
+	t.Fatalf("No %s value in %v", column, row)
+	return parquet.Value{}
+}
+
+func TestExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := NewDestination("export-test-archive", 10)
+	poster := NewArchivePoster(filepath.Join(dir, "archive"), 100, archive, new(sync.WaitGroup))
+	day := int64(1425579721000000)
+	for _, point := range []Point{
+		{Token: "t.a", Type: Router, Points: []interface{}{day + 2, 200, 10, 1.5, "web", nil, "https", nil, nil, nil}},
+		{Token: "t.a", Type: Router, Points: []interface{}{day + 1, 503, 30000, 1, "web", nil, "https", "req-1", nil, nil}},
+		{Token: "t.b", Type: Router, Points: []interface{}{day, "oops", 10, 1, "web", nil, "http", nil, nil, nil}},
+		{Token: "t.a", Type: EventsRouterStatus, Points: []interface{}{day, 503, "web.1", "/", "web", nil}},
+		{Token: "t.a", Type: EventsRouterStatus, Points: []interface{}{day + 86400000000, 503, "web.1", "/", "web", nil}},
+	} {
+		archive.PostPoint(point)
+	}
+	archive.Close()
+	poster.Run()
+	archived, _ := filepath.Glob(filepath.Join(dir, "archive", "*", "*.points"))
+	if len(archived) != 1 {
+		t.Fatalf("Expected an archive file, got %v", archived)
+	}
+
+	var out bytes.Buffer
+	if code := runExport([]string{"-out", filepath.Join(dir, "out"), archived[0]}, &out); code != 0 {
+		t.Fatalf("Export failed (%d): %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "exported 5 points to 4 files, nulling 1 values") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+
+	name := exportName(archived[0])
+	schema, rows := readParquetFile(t, filepath.Join(dir, "out", "router", "date=2015-03-05", "token=t.a", name+".parquet"))
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 router rows of t.a, got %v", rows)
+	}
+	if v := parquetValue(t, schema, rows[0], "time"); v.Int64() != day+1 {
+		t.Errorf("Expected the rows sorted by time, got %v", v)
+	}
+	if v := parquetValue(t, schema, rows[0], "status"); v.Int64() != 503 {
+		t.Errorf("Expected a status of 503, got %v", v)
+	}
+	if v := parquetValue(t, schema, rows[0], "connect"); v.Double() != 1.0 {
+		t.Errorf("Expected the connect time as a double, got %v", v)
+	}
+	if v := parquetValue(t, schema, rows[1], "connect"); v.Double() != 1.5 {
+		t.Errorf("Expected the connect time as a double, got %v", v)
+	}
+	if v := parquetValue(t, schema, rows[0], "request_id"); v.String() != "req-1" {
+		t.Errorf("Expected the request id, got %v", v)
+	}
+	if v := parquetValue(t, schema, rows[1], "request_id"); !v.IsNull() {
+		t.Errorf("Expected a null request id, got %v", v)
+	}
+
+	// Each partition of a series has its schema, whatever its values were
+	other, rows := readParquetFile(t, filepath.Join(dir, "out", "router", "date=2015-03-05", "token=t.b", name+".parquet"))
+	if len(rows) != 1 || !parquetValue(t, other, rows[0], "status").IsNull() {
+		t.Errorf("Expected t.b's status to be nulled, got %v", rows)
+	}
+	if expected := exportSchema(Router).String(); schema.String() != expected || other.String() != expected {
+		t.Errorf("Expected the router schema\n%s\ngot\n%s\nand\n%s", expected, schema, other)
+	}
+	for _, path := range []string{
+		"events.router.status/date=2015-03-05/token=t.a/" + name + ".parquet",
+		"events.router.status/date=2015-03-06/token=t.a/" + name + ".parquet",
+	} {
+		if _, rows := readParquetFile(t, filepath.Join(dir, "out", path)); len(rows) != 1 {
+			t.Errorf("Expected a row in %s, got %v", path, rows)
+		}
+	}
+}
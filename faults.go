@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Faults injected to rehearse backpressure and failover, e.g. in
+	// staging: the fraction of deliveries failed, the latency added to
+	// parsing every batch and the fraction of points dropped. Also settable
+	// from /admin/faults.
+	faults = NewFaults(FaultSettings{
+		DeliveryErrorRate: parseFloatSetting("FAULT_DELIVERY_ERROR_RATE", os.Getenv("FAULT_DELIVERY_ERROR_RATE"), 0),
+		ParseLatency:      parseDurationSetting("FAULT_PARSE_LATENCY", os.Getenv("FAULT_PARSE_LATENCY"), 0),
+		DropRate:          parseFloatSetting("FAULT_DROP_RATE", os.Getenv("FAULT_DROP_RATE"), 0),
+	})
+
+	errInjectedFault = errors.New("Injected delivery fault")
+
+	faultsActiveGauge         = metrics.GetOrRegisterGauge("lumbermill.faults.active", metrics.DefaultRegistry)
+	faultDeliveryErrorCounter = metrics.GetOrRegisterCounter("lumbermill.faults.injected.delivery", metrics.DefaultRegistry)
+	faultLatencyCounter       = metrics.GetOrRegisterCounter("lumbermill.faults.injected.latency", metrics.DefaultRegistry)
+	faultDroppedCounter       = metrics.GetOrRegisterCounter("lumbermill.faults.injected.dropped", metrics.DefaultRegistry)
+)
+
+// What faults to inject
+type FaultSettings struct {
+	DeliveryErrorRate float64       `json:"delivery_error_rate"`
+	ParseLatency      time.Duration `json:"parse_latency"`
+	DropRate          float64       `json:"drop_rate"`
+}
+
+func (s FaultSettings) Active() bool {
+	return s.DeliveryErrorRate > 0 || s.ParseLatency > 0 || s.DropRate > 0
+}
+
+// Faults being injected. lumbermill.faults.active is 1 while any is, so
+// nobody mistakes a rehearsal for an incident.
+type Faults struct {
+	sync.Mutex
+	settings FaultSettings
+}
+
+func NewFaults(settings FaultSettings) *Faults {
+	f := &Faults{}
+	f.Set(settings)
+	return f
+}
+
+func (f *Faults) Get() FaultSettings {
+	f.Lock()
+	defer f.Unlock()
+	return f.settings
+}
+
+func (f *Faults) Set(settings FaultSettings) {
+	f.Lock()
+	defer f.Unlock()
+	f.settings = settings
+	if settings.Active() {
+		faultsActiveGauge.Update(1)
+	} else {
+		faultsActiveGauge.Update(0)
+	}
+}
+
+// Returns errInjectedFault for the configured fraction of deliveries
+func (f *Faults) DeliveryError() error {
+	if rate := f.Get().DeliveryErrorRate; rate > 0 && rand.Float64() < rate {
+		faultDeliveryErrorCounter.Inc(1)
+		return errInjectedFault
+	}
+	return nil
+}
+
+// Sleeps for the configured parse latency
+func (f *Faults) Delay() {
+	if latency := f.Get().ParseLatency; latency > 0 {
+		faultLatencyCounter.Inc(1)
+		time.Sleep(latency)
+	}
+}
+
+// Drops the configured fraction of the batch's points
+func (f *Faults) Drop(b *pointBatch) {
+	rate := f.Get().DropRate
+	if rate <= 0 {
+		return
+	}
+	kept := b.points[:0]
+	for _, point := range b.points {
+		if rand.Float64() < rate {
+			faultDroppedCounter.Inc(1)
+		} else {
+			kept = append(kept, point)
+		}
+	}
+	b.points = kept
+}
+
+// GET /admin/faults, or POST /admin/faults with any of
+// ?delivery_error_rate=<0-1>&parse_latency=<duration>&drop_rate=<0-1> to
+// change the faults injected. Zeros stop them.
+func (s *LumbermillServer) serveAdminFaults(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.adminActor(w, r, roleFor(r, Admin))
+	if !ok {
+		return
+	}
+
+	if r.Method == "POST" {
+		query := r.URL.Query()
+		before := faults.Get()
+		after := before
+		var err error
+		if v := query.Get("delivery_error_rate"); v != "" {
+			after.DeliveryErrorRate, err = parseFaultRate(v)
+		}
+		if v := query.Get("drop_rate"); v != "" && err == nil {
+			after.DropRate, err = parseFaultRate(v)
+		}
+		if v := query.Get("parse_latency"); v != "" && err == nil {
+			after.ParseLatency, err = time.ParseDuration(v)
+		}
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "Rates must be between 0 and 1, and parse_latency a duration")
+			return
+		}
+		faults.Set(after)
+		auditLog.Record(actor, "faults.set", before, after)
+	}
+
+	writeJSON(w, faults.Get())
+}
+
+func parseFaultRate(v string) (float64, error) {
+	rate, err := strconv.ParseFloat(v, 64)
+	if err == nil && (rate < 0 || rate > 1) {
+		err = errors.New("out of range")
+	}
+	return rate, err
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestFaultInjection(t *testing.T) {
+	defer func(f FaultSettings) { faults.Set(f) }(faults.Get())
+
+	faults.Set(FaultSettings{DropRate: 1})
+	batch := new(pointBatch)
+	batch.points = []Point{{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10}}}
+	faults.Drop(batch)
+	if len(batch.points) != 0 || faultsActiveGauge.Value() != 1 {
+		t.Errorf("Expected every point to be dropped, and the faults marked active")
+	}
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	poster := NewPoster(newTestWriter(db, ""), "faults-test", NewDestination("faults-test", 10), new(sync.WaitGroup))
+	faults.Set(FaultSettings{DeliveryErrorRate: 1})
+	d := newDelivery()
+	d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10}})
+	failures := poster.pointsFailureCounter.Count()
+	poster.deliver(d)
+	if len(db.Writes()) != 0 || poster.pointsFailureCounter.Count()-failures != 1 {
+		t.Errorf("Expected the delivery to fail without reaching InfluxDB")
+	}
+
+	faults.Set(FaultSettings{})
+	if faultsActiveGauge.Value() != 0 {
+		t.Errorf("Expected the faults to be marked inactive")
+	}
+}
+
+func TestAdminFaults(t *testing.T) {
+	defer func(f FaultSettings) { faults.Set(f) }(faults.Get())
+	User, Password = "foo", "foo"
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	admin := func(query string) int {
+		req, _ := http.NewRequest("POST", "/admin/faults?"+query, nil)
+		req.SetBasicAuth("foo", "foo")
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if code := admin("delivery_error_rate=0.5&parse_latency=10ms"); code != http.StatusOK {
+		t.Fatalf("Setting faults failed: %d", code)
+	}
+	if f := faults.Get(); f.DeliveryErrorRate != 0.5 || f.ParseLatency.String() != "10ms" || f.DropRate != 0 {
+		t.Errorf("Unexpected faults %+v", f)
+	}
+	if code := admin("drop_rate=2"); code != http.StatusBadRequest {
+		t.Errorf("Expected an out of range rate to be refused, got %d", code)
+	}
+}
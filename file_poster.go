@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// Writes points as newline delimited JSON to a local file, rotating it once
+// it grows past maxBytes. Meant for local development without InfluxDB.
+type FilePoster struct {
+	destination   *Destination
+	path          string
+	maxBytes      int64
+	backups       int
+	file          *os.File
+	writer        *bufio.Writer
+	written       int64
+	pointsCounter metrics.Counter
+	rotateErrors  metrics.Counter
+	waitGroup     *sync.WaitGroup
+}
+
+func NewFilePoster(path string, maxBytes int64, backups int, destination *Destination, waitGroup *sync.WaitGroup) (*FilePoster, error) {
+	p := &FilePoster{
+		destination:   destination,
+		path:          path,
+		maxBytes:      maxBytes,
+		backups:       backups,
+		pointsCounter: metrics.GetOrRegisterCounter("lumbermill.poster.file.points", metrics.DefaultRegistry),
+		rotateErrors:  metrics.GetOrRegisterCounter("lumbermill.poster.file.rotate.errors", metrics.DefaultRegistry),
+		waitGroup:     waitGroup,
+	}
+	if err := p.open(); err != nil {
+		return nil, err
+	}
+	waitGroup.Add(1)
+	return p, nil
+}
+
+func (p *FilePoster) open() error {
+	file, err := os.OpenFile(p.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if p.file != nil {
+		p.file.Close()
+	}
+	p.file = file
+	if p.writer == nil {
+		p.writer = bufio.NewWriter(file)
+	} else {
+		p.writer.Reset(file)
+	}
+	p.written = info.Size()
+	return nil
+}
+
+// Shifts path to path.1, path.1 to path.2, ... and starts a new file. When
+// that fails, points keep going to the current file, which is rotated again
+// once it's grown by maxBytes.
+func (p *FilePoster) rotate() error {
+	if err := p.writer.Flush(); err != nil {
+		p.written = 0
+		return err
+	}
+
+	for i := p.backups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", p.path, i), fmt.Sprintf("%s.%d", p.path, i+1))
+	}
+	var err error
+	if p.backups > 0 {
+		err = os.Rename(p.path, p.path+".1")
+	} else {
+		err = os.Remove(p.path)
+	}
+	if err == nil {
+		err = p.open()
+	}
+	if err != nil {
+		p.written = 0
+	}
+	return err
+}
+
+// A point as a JSON object of its columns
+func pointJSON(point Point) ([]byte, error) {
+	obj := make(map[string]interface{}, len(point.Points)+4)
+	obj["series"] = point.SeriesName()
+	obj["series_type"] = point.Type.Name()
+	obj["token"] = point.Token
+	if point.RequestId != "" {
+		obj["request_id"] = point.RequestId
+	}
+	for i, column := range point.Type.Columns() {
+		if i < len(point.Points) {
+			obj[column] = point.Points[i]
+		}
+	}
+	return json.Marshal(obj)
+}
+
+func (p *FilePoster) write(point Point) {
+	line, err := pointJSON(point)
+	if err != nil {
+		log.Printf("Error encoding point for %s: %s\n", p.path, err)
+		p.destination.fail(point.RequestId)
+		return
+	}
+
+	if p.maxBytes > 0 && p.written+int64(len(line))+1 > p.maxBytes && p.written > 0 {
+		if err := p.rotate(); err != nil {
+			p.rotateErrors.Inc(1)
+			log.Printf("Unable to rotate %s, still writing to it: %s\n", p.path, err)
+		}
+	}
+
+	p.writer.Write(line)
+	p.writer.WriteByte('\n')
+	p.written += int64(len(line)) + 1
+	p.pointsCounter.Inc(1)
+	p.destination.ack(point.RequestId, 1)
+}
+
+func (p *FilePoster) Run() {
+	defer p.waitGroup.Done()
+
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+	defer func() {
+		p.writer.Flush()
+		p.file.Close()
+	}()
+
+	for {
+		select {
+		case point, open := <-p.destination.points:
+			if !open {
+				return
+			}
+			p.write(point)
+		case <-flush.C:
+			p.writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func readJSONLines(t *testing.T, path string) []map[string]interface{} {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	lines := make([]map[string]interface{}, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		obj := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
+			t.Fatalf("Invalid JSON line %q: %s", scanner.Text(), err)
+		}
+		lines = append(lines, obj)
+	}
+	return lines
+}
+
+func TestFilePoster(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumbermill-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "points.json")
+	destination := NewDestination("file-test", 10)
+	waitGroup := new(sync.WaitGroup)
+
+	// Small enough to rotate after every couple of points
+	poster, err := NewFilePoster(path, 150, 1, destination, waitGroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int64(1); i <= 5; i++ {
+		destination.PostPoint(pointAt(i))
+	}
+	destination.Close()
+	poster.Run()
+
+	current := readJSONLines(t, path)
+	backup := readJSONLines(t, path+".1")
+	if len(current) == 0 || len(backup) == 0 || len(current)+len(backup) >= 5 {
+		t.Fatalf("Expected rotation to keep a single backup, got %d and %d points", len(current), len(backup))
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("Expected only one backup")
+	}
+
+	last := current[len(current)-1]
+	if last["series"] != "router.t.test" || last["status"] != float64(200) || last["time"] != float64(5) {
+		t.Errorf("Unexpected point: %v", last)
+	}
+}
+
+func TestFilePosterRotationFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumbermill-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The backup can't be renamed over a directory that isn't empty
+	path := filepath.Join(dir, "points.json")
+	if err := os.MkdirAll(filepath.Join(path+".1", "taken"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	destination := NewDestination("file-rotate-test", 10)
+	poster, err := NewFilePoster(path, 150, 1, destination, new(sync.WaitGroup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errors := poster.rotateErrors.Count()
+	for i := int64(1); i <= 5; i++ {
+		destination.PostPoint(pointAt(i))
+	}
+	destination.Close()
+	poster.Run()
+
+	if poster.rotateErrors.Count() == errors {
+		t.Errorf("Expected the failed rotation to be counted")
+	}
+	if points := readJSONLines(t, path); len(points) != 5 {
+		t.Errorf("Expected every point to be written to the current file, got %d", len(points))
+	}
+}
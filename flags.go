@@ -0,0 +1,147 @@
+package main
+
+import (
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// Percentage of tokens each feature flag is on for, as "<flag>=<percent>,...",
+// e.g. "parser.v2=1" to try a new parser on 1% of apps. Changed at runtime
+// through the admin API.
+var Flags = newFeatureFlags(parseKeyValueList(os.Getenv("FEATURE_FLAGS")))
+
+// Feature flags rolled out to a percentage of tokens. A token's flags stay
+// put while the percentage grows: the tokens on at 1% are on at 10% too.
+type FeatureFlags struct {
+	sync.RWMutex
+	percents map[string]int
+	counters sync.Map // metrics.Counter by metric name
+	timers   sync.Map // metrics.Timer by metric name
+}
+
+func newFeatureFlags(percents map[string]string) *FeatureFlags {
+	f := &FeatureFlags{percents: make(map[string]int)}
+	for flag, percent := range percents {
+		f.Set(flag, parseIntSetting("percent of "+flag, percent, 0))
+	}
+	return f
+}
+
+// Rolls flag out to percent of tokens, clamped to 0-100
+func (f *FeatureFlags) Set(flag string, percent int) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	f.Lock()
+	defer f.Unlock()
+	if percent == 0 {
+		delete(f.percents, flag)
+		return
+	}
+	f.percents[flag] = percent
+}
+
+// The percentage of every flag rolled out
+func (f *FeatureFlags) All() map[string]int {
+	f.RLock()
+	defer f.RUnlock()
+	all := make(map[string]int, len(f.percents))
+	for flag, percent := range f.percents {
+		all[flag] = percent
+	}
+	return all
+}
+
+// Whether flag is on for token, counted as lumbermill.flags.<flag>.{on,off}
+func (f *FeatureFlags) Enabled(flag, token string) bool {
+	f.RLock()
+	on := flagOn(flag, token, f.percents[flag])
+	f.RUnlock()
+
+	f.counter(flagMetric("lumbermill.flags", flag, on)).Inc(1)
+	return on
+}
+
+// Whether flag, rolled out to percent of tokens, is on for token
+func flagOn(flag, token string, percent int) bool {
+	return percent >= 100 || percent > 0 && flagBucket(flag, token) < percent
+}
+
+// The counter named name, registered once rather than on every request
+func (f *FeatureFlags) counter(name string) metrics.Counter {
+	if counter, found := f.counters.Load(name); found {
+		return counter.(metrics.Counter)
+	}
+	counter, _ := f.counters.LoadOrStore(name, metrics.GetOrRegisterCounter(name, metrics.DefaultRegistry))
+	return counter.(metrics.Counter)
+}
+
+// The timer named name, registered once rather than on every request
+func (f *FeatureFlags) timer(name string) metrics.Timer {
+	if timer, found := f.timers.Load(name); found {
+		return timer.(metrics.Timer)
+	}
+	timer, _ := f.timers.LoadOrStore(name, metrics.GetOrRegisterTimer(name, metrics.DefaultRegistry))
+	return timer.(metrics.Timer)
+}
+
+// Where token falls, 0-99, in flag's rollout
+func flagBucket(flag, token string) int {
+	h := fnv.New32a()
+	h.Write([]byte(flag))
+	h.Write([]byte{0})
+	h.Write([]byte(token))
+	return int(h.Sum32() % 100)
+}
+
+// name tagged with flag's state, "<name>.<flag>.<on|off>", so metrics of
+// work done either way can be compared
+func flagMetric(name, flag string, on bool) string {
+	if on {
+		return name + "." + flag + ".on"
+	}
+	return name + "." + flag + ".off"
+}
+
+// Times work for token since start as name tagged with the state of each
+// flag rolled out, so a flag's cost shows up before it's on everywhere.
+// Flags aren't counted as on or off again, Enabled did that already.
+func (f *FeatureFlags) TimeSince(name, token string, start time.Time) {
+	f.RLock()
+	defer f.RUnlock()
+	for flag, percent := range f.percents {
+		f.timer(flagMetric(name, flag, flagOn(flag, token, percent))).UpdateSince(start)
+	}
+}
+
+// GET /admin/flags, or POST /admin/flags?flag=<name>&percent=<0-100> to
+// roll a flag out to a percentage of tokens (0 turns it off)
+func (s *LumbermillServer) serveAdminFlags(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.adminActor(w, r, roleFor(r, Operator))
+	if !ok {
+		return
+	}
+
+	if r.Method == "POST" {
+		query := r.URL.Query()
+		flag := query.Get("flag")
+		percent, err := strconv.Atoi(query.Get("percent"))
+		if flag == "" || err != nil || percent < 0 || percent > 100 {
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "flag and a percent of 0-100 are required")
+			return
+		}
+		before := Flags.All()[flag]
+		Flags.Set(flag, percent)
+		auditLog.Record(actor, "flags.set."+flag, before, percent)
+	}
+
+	writeJSON(w, Flags.All())
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+func TestFeatureFlagsRollout(t *testing.T) {
+	flags := newFeatureFlags(map[string]string{"parser.v2": "10"})
+
+	on := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		token := fmt.Sprintf("t.flags%d", i)
+		if flags.Enabled("parser.v2", token) {
+			on[token] = true
+		}
+	}
+	if len(on) < 50 || len(on) > 150 {
+		t.Errorf("Expected about 10%% of tokens on, got %d of 1000", len(on))
+	}
+
+	// Tokens on stay on as the rollout grows
+	flags.Set("parser.v2", 50)
+	for token := range on {
+		if !flags.Enabled("parser.v2", token) {
+			t.Fatalf("Expected %s to stay on", token)
+		}
+	}
+
+	flags.Set("parser.v2", 0)
+	if flags.Enabled("parser.v2", "t.flags0") || len(flags.All()) != 0 {
+		t.Errorf("Expected the flag to be off")
+	}
+	flags.Set("encoder.v2", 200)
+	if !flags.Enabled("encoder.v2", "t.flags0") || flags.All()["encoder.v2"] != 100 {
+		t.Errorf("Expected the flag to be on for everyone")
+	}
+}
+
+func TestAdminFlags(t *testing.T) {
+	defer func(flags *FeatureFlags) { Flags = flags }(Flags)
+	Flags = newFeatureFlags(nil)
+	User, Password = "foo", "foo"
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+
+	admin := func(method, query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/admin/flags?"+query, nil)
+		req.SetBasicAuth("foo", "foo")
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if recorder := admin("POST", "flag=parser.v2&percent=1"); recorder.Code != http.StatusOK || Flags.All()["parser.v2"] != 1 {
+		t.Errorf("Rolling out a flag failed (%d): %s", recorder.Code, recorder.Body)
+	}
+	if recorder := admin("POST", "flag=parser.v2&percent=101"); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected a percent over 100 to be refused, got %d", recorder.Code)
+	}
+	if recorder := admin("GET", ""); recorder.Body.String() != `{"parser.v2":1}` {
+		t.Errorf("Unexpected flags: %s", recorder.Body)
+	}
+}
+
+func TestFeatureFlagsTimeSinceCountsOnce(t *testing.T) {
+	flags := newFeatureFlags(map[string]string{"timing.test": "100"})
+	counter := metrics.GetOrRegisterCounter("lumbermill.flags.timing.test.on", metrics.DefaultRegistry)
+	timer := metrics.GetOrRegisterTimer("lumbermill.test.time.timing.test.on", metrics.DefaultRegistry)
+	counted, timed := counter.Count(), timer.Count()
+
+	flags.Enabled("timing.test", "t.flags")
+	flags.TimeSince("lumbermill.test.time", "t.flags", time.Now())
+	flags.TimeSince("lumbermill.test.time", "t.flags", time.Now())
+	if counter.Count() != counted+1 {
+		t.Errorf("Expected the flag to be counted once, got %d", counter.Count()-counted)
+	}
+	if timer.Count() != timed+2 {
+		t.Errorf("Expected both timings, got %d", timer.Count()-timed)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Look up the formation (dyno sizes) of each app this often with the
+	// Heroku API, to tag dyno.mem and dyno.load points with dyno_size. Off
+	// unless set. Apps are those of add-on resources, and TOKEN_APPS as
+	// "<token>=<app>,...".
+	FormationInterval = parseDurationSetting("FORMATION_INTERVAL", os.Getenv("FORMATION_INTERVAL"), 0)
+	TokenApps         = parseKeyValueList(os.Getenv("TOKEN_APPS"))
+	HerokuAPIKey      = os.Getenv("HEROKU_API_KEY")
+	HerokuAPIURL      = stringSetting(os.Getenv("HEROKU_API_URL"), "https://api.heroku.com")
+
+	formationRefreshCounter = metrics.GetOrRegisterCounter("lumbermill.formations.refreshed", metrics.DefaultRegistry)
+	formationErrorsCounter  = metrics.GetOrRegisterCounter("lumbermill.formations.errors", metrics.DefaultRegistry)
+)
+
+// The dyno size of each process type of each app (token), as the Heroku API
+// last reported it
+type Formations struct {
+	sync.RWMutex
+	sizes  map[string]map[string]string // token -> process type -> size
+	client *http.Client
+}
+
+func NewFormations() *Formations {
+	return &Formations{sizes: make(map[string]map[string]string), client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// The size of token's dynos of dynoType, nil when it isn't known. A nil
+// Formations knows none.
+func (f *Formations) Size(token, dynoType string) interface{} {
+	if f == nil {
+		return nil
+	}
+	f.RLock()
+	defer f.RUnlock()
+	if size, ok := f.sizes[token][dynoType]; ok {
+		return size
+	}
+	return nil
+}
+
+// A process type of an app's formation, as the Heroku API describes it
+type formationProcess struct {
+	Type     string `json:"type"`
+	Size     string `json:"size"`
+	Quantity int    `json:"quantity"`
+}
+
+// Fetches app's formation, by process type
+func (f *Formations) fetch(app string) (map[string]string, error) {
+	req, err := http.NewRequest("GET", HerokuAPIURL+"/apps/"+app+"/formation", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.heroku+json; version=3")
+	req.Header.Set("Authorization", "Bearer "+secrets.Get("HEROKU_API_KEY", HerokuAPIKey))
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Heroku API returned %d for %s", resp.StatusCode, app)
+	}
+	var processes []formationProcess
+	if err := json.NewDecoder(resp.Body).Decode(&processes); err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]string, len(processes))
+	for _, p := range processes {
+		sizes[p.Type] = p.Size
+	}
+	return sizes, nil
+}
+
+// Fetches the formation of each token's app, keeping the last one known of
+// apps that can't be fetched
+func (f *Formations) Refresh(apps map[string]string) {
+	for token, app := range apps {
+		sizes, err := f.fetch(app)
+		if err != nil {
+			formationErrorsCounter.Inc(1)
+			log.Printf("at=formation_error app=%s err=%q\n", app, err)
+			continue
+		}
+		f.Lock()
+		f.sizes[token] = sizes
+		f.Unlock()
+		formationRefreshCounter.Inc(1)
+	}
+}
+
+// The app of each token: TOKEN_APPS, and the apps of add-on resources
+func (s *LumbermillServer) tokenApps() map[string]string {
+	apps := s.addons.Apps()
+	for token, app := range TokenApps {
+		apps[token] = app
+	}
+	return apps
+}
+
+// Refreshes the formations every so often
+func (s *LumbermillServer) runFormations(every time.Duration) {
+	for {
+		s.formations.Refresh(s.tokenApps())
+		time.Sleep(every)
+	}
+}
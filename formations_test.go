@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestFormations(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer api-key" || r.URL.Path != "/apps/shop/formation" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[{"type":"web","size":"Standard-2X","quantity":3},{"type":"worker","size":"Performance-M","quantity":1}]`))
+	}))
+	defer api.Close()
+	defer func(url, key string) { HerokuAPIURL, HerokuAPIKey = url, key }(HerokuAPIURL, HerokuAPIKey)
+	HerokuAPIURL, HerokuAPIKey = api.URL, "api-key"
+
+	formations := NewFormations()
+	errors := formationErrorsCounter.Count()
+	formations.Refresh(map[string]string{"t.shop": "shop", "t.gone": "gone"})
+	if formations.Size("t.shop", "web") != "Standard-2X" || formations.Size("t.shop", "worker") != "Performance-M" {
+		t.Errorf("Expected the app's dyno sizes")
+	}
+	if formations.Size("t.shop", "clock") != nil || formations.Size("t.gone", "web") != nil {
+		t.Errorf("Expected unknown sizes to be nil")
+	}
+	if formationErrorsCounter.Count() != errors+1 {
+		t.Errorf("Expected the missing app to be counted")
+	}
+
+	destination := NewDestination("formations-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.formations = formations
+	req := lumbermilltest.NewDrainRequest("/drain", "t.shop",
+		lumbermilltest.SyslogLine("heroku", "web.1", "source=web.1 dyno=heroku.1.abc sample#load_avg_1m=0.5 sample#load_avg_5m=0.4 sample#load_avg_15m=0.3"))
+	server.serveDrain(httptest.NewRecorder(), req)
+	if len(destination.points) != 1 {
+		t.Fatalf("Expected a dyno.load point")
+	}
+	point := <-destination.points
+	if size := point.Points[len(point.Points)-1]; point.Type != DynoLoad || size != "Standard-2X" {
+		t.Errorf("Expected the point to be tagged with its dyno size, got %v", point.Points)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+// Number of space delimited fields lpx reads per frame: the frame length
+// followed by six syslog header fields. The message is read with Read.
+const lpxFieldsPerFrame = 7
+
+var errFrameTooLarge = errors.New("logplex frame too large")
+
+// Bounds the length of logplex frames before lpx allocates a buffer for
+// them, so a bogus frame length can't exhaust memory.
+type frameLimitReader struct {
+	*bufio.Reader
+	max   int64
+	field int // Field lpx reads next, 0 being the frame length
+}
+
+func newFrameLimitReader(r *bufio.Reader, max int64) *frameLimitReader {
+	return &frameLimitReader{Reader: r, max: max}
+}
+
+func (f *frameLimitReader) ReadBytes(delim byte) ([]byte, error) {
+	line, err := f.Reader.ReadBytes(delim)
+	if err != nil {
+		return line, err
+	}
+
+	if f.field == 0 {
+		n, err := strconv.ParseInt(string(bytes.TrimRight(line, " ")), 10, 64)
+		if err == nil && n > f.max {
+			return nil, errFrameTooLarge
+		}
+	}
+	f.field = (f.field + 1) % lpxFieldsPerFrame
+
+	return line, nil
+}
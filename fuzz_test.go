@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lumbermilltest"
+	"github.com/kr/logfmt"
+)
+
+// Seeds a fuzzer with each corpus file as a drain body
+func addCorpusBodies(f *testing.F) {
+	corpus, err := lumbermilltest.LoadCorpus(corpusDir)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, lines := range corpus {
+		f.Add([]byte(lumbermilltest.Body(lines...)))
+	}
+}
+
+// Seeds a fuzzer with the messages of the corpus lines
+func addCorpusMessages(f *testing.F) {
+	corpus, err := lumbermilltest.LoadCorpus(corpusDir)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, lines := range corpus {
+		lp := lpx.NewReader(bufio.NewReader(bytes.NewBufferString(lumbermilltest.Body(lines...))))
+		for lp.Next() {
+			f.Add(append([]byte(nil), lp.Bytes()...))
+		}
+	}
+}
+
+// Malformed drain bodies must not panic the parse tree
+func FuzzParseLines(f *testing.F) {
+	addCorpusBodies(f)
+	// Crashed before frame lengths were bounded
+	f.Add([]byte("99999999999999 <45>1 2015-03-05T18:22:01+00:00 host heroku web.1 - x"))
+	f.Fuzz(func(t *testing.T, body []byte) {
+		lp := lpx.NewReader(newFrameLimitReader(bufio.NewReader(bytes.NewReader(body)), MaxFrameBytes))
+		parseLines(lp, drainContext{token: corpusToken, requestId: "fuzz", allowOverrides: true}, new(pointBatch), &lineCounts{})
+	})
+}
+
+// Nor may any of the logfmt branches
+func FuzzLogfmtMessages(f *testing.F) {
+	addCorpusMessages(f)
+	// Crashed parseBytesToDynoError before its length was checked
+	f.Add([]byte("Error R"))
+	f.Add([]byte("Error R1"))
+	f.Fuzz(func(t *testing.T, msg []byte) {
+		logfmt.Unmarshal(msg, &routerMsg{})
+		logfmt.Unmarshal(msg, &routerError{})
+		logfmt.Unmarshal(msg, &dynoMemMsg{})
+		logfmt.Unmarshal(msg, &dynoLoadMsg{})
+		if bytes.HasPrefix(msg, dynoErrorSentinel) {
+			parseBytesToDynoError(msg)
+		}
+	})
+}
+
+func FuzzParseTimestamp(f *testing.F) {
+	f.Add([]byte("2015-03-05T18:21:34.118254+00:00"))
+	f.Add([]byte("2014-07-02T20:09:15+00:00"))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		parseTimestamp(b)
+	})
+}
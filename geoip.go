@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// A directory of MaxMind's GeoLite2 (or GeoIP2) Country database in CSV
+	// form. When set, router points get the country and continent of the
+	// client in their fwd field, for coarse geographic dashboards. The IP
+	// itself is never stored. Off unless set.
+	GeoIPDatabase = os.Getenv("GEOIP_DATABASE")
+
+	// How precisely clients are located: "country" (the default) or
+	// "continent", leaving country null
+	GeoIPPrecision = os.Getenv("GEOIP_PRECISION")
+
+	// Tokens whose clients are located, as "<token>,...". Every token's are
+	// when unset.
+	GeoIPTokens = parseSet(os.Getenv("GEOIP_TOKENS"))
+
+	geoIPMissesCounter = metrics.GetOrRegisterCounter("lumbermill.geoip.misses", metrics.DefaultRegistry)
+)
+
+// A network of the database and where it is
+type geoNetwork struct {
+	first, last []byte // 16 byte addresses
+	country     string
+	continent   string
+}
+
+// Locates IPs with a MaxMind country database
+type GeoIP struct {
+	networks      []geoNetwork // Sorted by first address
+	continentOnly bool
+	tokens        map[string]bool // Tokens located, all when empty
+}
+
+// Loads the GeoLite2-Country CSV files in dir
+func LoadGeoIP(dir string) (*GeoIP, error) {
+	locations, err := loadGeoLocations(filepath.Join(dir, "GeoLite2-Country-Locations-en.csv"))
+	if err != nil {
+		return nil, err
+	}
+	g := &GeoIP{}
+	for _, name := range []string{"GeoLite2-Country-Blocks-IPv4.csv", "GeoLite2-Country-Blocks-IPv6.csv"} {
+		if err := g.loadBlocks(filepath.Join(dir, name), locations); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if len(g.networks) == 0 {
+		return nil, fmt.Errorf("no networks in %s", dir)
+	}
+	sort.Slice(g.networks, func(i, j int) bool { return bytes.Compare(g.networks[i].first, g.networks[j].first) < 0 })
+	return g, nil
+}
+
+// Reads a CSV file with a header, calling row with each row by column name
+func readCSV(path string, row func(map[string]string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		return err
+	}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				values[column] = record[i]
+			}
+		}
+		row(values)
+	}
+}
+
+// Country and continent codes by geoname id
+func loadGeoLocations(path string) (map[string][2]string, error) {
+	locations := make(map[string][2]string)
+	err := readCSV(path, func(row map[string]string) {
+		locations[row["geoname_id"]] = [2]string{row["country_iso_code"], row["continent_code"]}
+	})
+	return locations, err
+}
+
+func (g *GeoIP) loadBlocks(path string, locations map[string][2]string) error {
+	return readCSV(path, func(row map[string]string) {
+		_, network, err := net.ParseCIDR(row["network"])
+		if err != nil {
+			return
+		}
+		id := row["geoname_id"]
+		if id == "" {
+			id = row["registered_country_geoname_id"]
+		}
+		location, ok := locations[id]
+		if !ok {
+			return
+		}
+		first := network.IP.To16()
+		last := make([]byte, 16)
+		mask := network.Mask
+		if len(mask) == net.IPv4len {
+			mask = append(net.CIDRMask(96, 128)[:12:12], mask...)
+		}
+		for i := range last {
+			last[i] = first[i] | ^mask[i]
+		}
+		g.networks = append(g.networks, geoNetwork{first: first, last: last, country: location[0], continent: location[1]})
+	})
+}
+
+// The country and continent codes of the client in token's fwd, the first
+// address when there are several, or nils. A nil GeoIP locates nothing.
+func (g *GeoIP) Locate(token, fwd string) (country, continent interface{}) {
+	if g == nil || fwd == "" || len(g.tokens) > 0 && !g.tokens[token] {
+		return nil, nil
+	}
+	if i := strings.IndexByte(fwd, ','); i >= 0 {
+		fwd = fwd[:i]
+	}
+	ip := net.ParseIP(strings.TrimSpace(fwd)).To16()
+	if ip == nil {
+		geoIPMissesCounter.Inc(1)
+		return nil, nil
+	}
+	i := sort.Search(len(g.networks), func(i int) bool { return bytes.Compare(g.networks[i].first, ip) > 0 }) - 1
+	if i < 0 || bytes.Compare(ip, g.networks[i].last) > 0 {
+		geoIPMissesCounter.Inc(1)
+		return nil, nil
+	}
+	network := g.networks[i]
+	if network.continent != "" {
+		continent = network.continent
+	}
+	if network.country != "" && !g.continentOnly {
+		country = network.country
+	}
+	return country, continent
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGeoIPDatabase(t *testing.T) string {
+	return writeGeoIPBlocks(t, "46.20.44.0/22,2921044,2921044,,0,0\n"+
+		"8.8.8.0/24,,6252001,,0,0\n")
+}
+
+// Writes a database of Germany and the United States, with blocks as the
+// IPv4 networks in them
+func writeGeoIPBlocks(t *testing.T, blocks string) string {
+	dir, err := ioutil.TempDir("", "lumbermill-geoip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"GeoLite2-Country-Locations-en.csv": "geoname_id,locale_code,continent_code,continent_name,country_iso_code,country_name,is_in_european_union\n" +
+			"2921044,en,EU,Europe,DE,Germany,1\n" +
+			"6252001,en,NA,\"North America\",US,\"United States\",0\n",
+		"GeoLite2-Country-Blocks-IPv4.csv": "network,geoname_id,registered_country_geoname_id,represented_country_geoname_id,is_anonymous_proxy,is_satellite_provider\n" +
+			blocks,
+		"GeoLite2-Country-Blocks-IPv6.csv": "network,geoname_id,registered_country_geoname_id,represented_country_geoname_id,is_anonymous_proxy,is_satellite_provider\n" +
+			"2a00:1450::/32,2921044,2921044,,0,0\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGeoIPLocate(t *testing.T) {
+	dir := writeGeoIPDatabase(t)
+	defer os.RemoveAll(dir)
+	geoip, err := LoadGeoIP(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for fwd, expected := range map[string][2]interface{}{
+		"46.20.45.18":            {"DE", "EU"},
+		"46.20.47.255, 10.0.0.1": {"DE", "EU"},
+		"8.8.8.8":                {"US", "NA"}, // Located by its registered country
+		"2a00:1450:4001::1":      {"DE", "EU"},
+		"46.20.48.1":             {nil, nil},
+		"1.1.1.1":                {nil, nil},
+		"not an ip":              {nil, nil},
+		"":                       {nil, nil},
+	} {
+		country, continent := geoip.Locate("t.a", fwd)
+		if country != expected[0] || continent != expected[1] {
+			t.Errorf("Expected %q to be in %v, got %v %v", fwd, expected, country, continent)
+		}
+	}
+
+	geoip.continentOnly = true
+	if country, continent := geoip.Locate("t.a", "46.20.45.18"); country != nil || continent != "EU" {
+		t.Errorf("Expected only the continent, got %v %v", country, continent)
+	}
+
+	geoip.tokens = map[string]bool{"t.b": true}
+	if _, continent := geoip.Locate("t.a", "46.20.45.18"); continent != nil {
+		t.Errorf("Expected tokens that aren't listed not to be located")
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Just enough of Grafana's (rows based) dashboard model for our dashboards
+type grafanaDashboard struct {
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	Time          map[string]string `json:"time"`
+	Rows          []grafanaRow      `json:"rows"`
+	SchemaVersion int               `json:"schemaVersion"`
+}
+
+type grafanaRow struct {
+	Title  string         `json:"title"`
+	Height string         `json:"height"`
+	Panels []grafanaPanel `json:"panels"`
+}
+
+type grafanaPanel struct {
+	Id         int             `json:"id"`
+	Title      string          `json:"title"`
+	Type       string          `json:"type"`
+	Datasource string          `json:"datasource"`
+	Span       int             `json:"span"`
+	Targets    []grafanaTarget `json:"targets"`
+}
+
+type grafanaTarget struct {
+	Query    string `json:"query"`
+	RawQuery bool   `json:"rawQuery"`
+}
+
+// Metrics lumbermill reports about itself, graphed when a data source for
+// them is given
+var selfMetricPanels = []struct {
+	title   string
+	metrics []string
+}{
+	{"Lines", []string{"lumbermill.lines", "lumbermill.lines.router", "lumbermill.lines.unknown.user"}},
+	{"Errors", []string{"lumbermill.errors.dropped", "lumbermill.errors.logfmt.parse", "lumbermill.errors.body.read"}},
+	{"Deliveries", []string{"lumbermill.poster.deliver.sizes", "lumbermill.batches.parse.time"}},
+}
+
+// name as a double quoted InfluxQL identifier, so tokens can't end the
+// identifier and change the query
+func influxQLIdentifier(name string) string {
+	return `"` + influxQLEscaper.Replace(name) + `"`
+}
+
+var influxQLEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Builds a dashboard of token's series, querying the InfluxDB data source
+// named datasource. Lumbermill's own metrics are added when selfDatasource
+// is given.
+func newGrafanaDashboard(datasource, token, selfDatasource string) grafanaDashboard {
+	series := func(st SeriesType) string {
+		return influxQLIdentifier(Point{Token: token, Type: st}.SeriesName())
+	}
+	id := 0
+	panel := func(title, query string, args ...interface{}) grafanaPanel {
+		id++
+		return grafanaPanel{
+			Id:         id,
+			Title:      title,
+			Type:       "graph",
+			Datasource: datasource,
+			Span:       6,
+			Targets:    []grafanaTarget{{Query: fmt.Sprintf(query, args...), RawQuery: true}},
+		}
+	}
+
+	dashboard := grafanaDashboard{
+		Title:         "lumbermill " + token,
+		Tags:          []string{"lumbermill"},
+		Time:          map[string]string{"from": "now-6h", "to": "now"},
+		SchemaVersion: 6,
+		Rows: []grafanaRow{
+			{Title: "Router", Height: "250px", Panels: []grafanaPanel{
+				panel("Service time p95 ("+RouterDurationUnit+")", `select percentile(service, 95) from %s where $timeFilter group by time($interval), dynoType`, series(Router)),
+				panel("Connect time p95 ("+RouterDurationUnit+")", `select percentile(connect, 95) from %s where $timeFilter group by time($interval), dynoType`, series(Router)),
+				panel("Requests by status", `select count(status) from %s where $timeFilter group by time($interval), status`, series(Router)),
+			}},
+			{Title: "Errors", Height: "250px", Panels: []grafanaPanel{
+				panel("Router errors", `select count(code) from %s where $timeFilter group by time($interval), code`, series(EventsRouter)),
+				panel("5xx responses", `select count(status) from %s where $timeFilter group by time($interval), status`, series(EventsRouterStatus)),
+				panel("Dyno errors", `select count(code) from %s where $timeFilter group by time($interval), code`, series(EventsDyno)),
+				panel("Lines dropped by logplex", `select sum(dropped) from %s where $timeFilter group by time($interval), code`, series(LogplexHealth)),
+			}},
+			{Title: "Dynos", Height: "250px", Panels: []grafanaPanel{
+				panel("Memory ("+MemoryUnit+")", `select mean(memory_total) from %s where $timeFilter group by time($interval), source`, series(DynoMem)),
+				panel("Load average (1m)", `select mean(load_avg_1m) from %s where $timeFilter group by time($interval), source`, series(DynoLoad)),
+			}},
+		},
+	}
+
+	if selfDatasource != "" {
+		row := grafanaRow{Title: "Lumbermill", Height: "250px"}
+		for _, p := range selfMetricPanels {
+			id++
+			targets := make([]grafanaTarget, len(p.metrics))
+			for i, metric := range p.metrics {
+				targets[i] = grafanaTarget{Query: metric}
+			}
+			row.Panels = append(row.Panels, grafanaPanel{Id: id, Title: p.title, Type: "graph", Datasource: selfDatasource, Span: 4, Targets: targets})
+		}
+		dashboard.Rows = append(dashboard.Rows, row)
+	}
+
+	return dashboard
+}
+
+// GET /dashboards/grafana?datasource=<name>&token=<token>[&self_datasource=<name>]
+func (s *LumbermillServer) serveGrafanaDashboard(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkAuth(r); err != nil {
+		writeError(w, r, http.StatusForbidden, errAuthFailed, err.Error())
+		authFailureCounter.Inc(1)
+		return
+	}
+
+	query := r.URL.Query()
+	datasource, token := query.Get("datasource"), query.Get("token")
+	if datasource == "" || token == "" {
+		writeError(w, r, http.StatusBadRequest, errBadRequest, "datasource and token are required")
+		badRequestCounter.Inc(1)
+		return
+	}
+
+	body, _ := json.MarshalIndent(newGrafanaDashboard(datasource, token, query.Get("self_datasource")), "", "  ")
+	headers := w.Header()
+	headers.Set("Content-Length", fmt.Sprintf("%d", len(body)))
+	headers.Set("Content-Type", "application/json")
+	w.Write(body)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGrafanaDashboard(t *testing.T) {
+	dashboard := newGrafanaDashboard("influx", "t.abc", "")
+	if dashboard.Title != "lumbermill t.abc" {
+		t.Errorf("Unexpected title: %q", dashboard.Title)
+	}
+
+	ids := make(map[int]bool)
+	for _, row := range dashboard.Rows {
+		for _, panel := range row.Panels {
+			if ids[panel.Id] {
+				t.Errorf("Duplicate panel id %d", panel.Id)
+			}
+			ids[panel.Id] = true
+			if panel.Datasource != "influx" {
+				t.Errorf("Panel %q uses data source %q", panel.Title, panel.Datasource)
+			}
+			if !strings.Contains(panel.Targets[0].Query, `.t.abc"`) {
+				t.Errorf("Panel %q doesn't query the token's series: %s", panel.Title, panel.Targets[0].Query)
+			}
+		}
+	}
+
+	withSelf := newGrafanaDashboard("influx", "t.abc", "librato")
+	if len(withSelf.Rows) != len(dashboard.Rows)+1 {
+		t.Errorf("Expected a row of lumbermill metrics")
+	}
+}
+
+func TestGrafanaDashboardEscapesToken(t *testing.T) {
+	dashboard := newGrafanaDashboard("influx", `t.a" where 1=1; drop measurement "x`, "")
+	query := dashboard.Rows[0].Panels[0].Targets[0].Query
+	if !strings.Contains(query, `from "router.t.a\" where 1=1; drop measurement \"x" where`) {
+		t.Errorf("Token isn't escaped: %s", query)
+	}
+}
+
+func TestServeGrafanaDashboard(t *testing.T) {
+	User = "foo"
+	Password = "foo"
+	server := NewLumbermillServer(&http.Server{}, nil)
+
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/dashboards/grafana?datasource=influx&token=t.abc", nil)
+	req.SetBasicAuth("foo", "foo")
+	server.serveGrafanaDashboard(recorder, req)
+
+	var dashboard grafanaDashboard
+	if err := json.Unmarshal(recorder.Body.Bytes(), &dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if len(dashboard.Rows) == 0 {
+		t.Errorf("Dashboard has no rows")
+	}
+
+	recorder = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/dashboards/grafana?token=t.abc", nil)
+	req.SetBasicAuth("foo", "foo")
+	server.serveGrafanaDashboard(recorder, req)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatal("Wrong Response Code: ", recorder.Code)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/heroku/lumbermill/proto/ingestv1"
+	metrics "github.com/rcrowley/go-metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// Port the gRPC ingestion service (proto/ingest.proto) is served on,
+	// alongside HTTP. Not served when unset.
+	GRPCPort = os.Getenv("GRPC_PORT")
+
+	ingestBatchesCounter = metrics.GetOrRegisterCounter("lumbermill.ingest.grpc.batches", metrics.DefaultRegistry)
+	ingestRefusedCounter = metrics.GetOrRegisterCounter("lumbermill.ingest.grpc.refused", metrics.DefaultRegistry)
+)
+
+// Serves proto/ingest.proto. Each batch is handed to serveDrain as the
+// drain request it stands for, so it goes through the same authentication,
+// limits and parsing, and its refusals are the gRPC codes of the drain's.
+type ingestServer struct {
+	ingestv1.UnimplementedIngestServer
+	server *LumbermillServer
+}
+
+// Starts serving the ingestion service on addr, until shutting down stops
+// it once the batches in flight are done
+func (s *LumbermillServer) StartIngest(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.serveIngest(listener)
+	return nil
+}
+
+func (s *LumbermillServer) serveIngest(listener net.Listener) {
+	s.ingest = grpc.NewServer()
+	ingestv1.RegisterIngestServer(s.ingest, &ingestServer{server: s})
+	go s.ingest.Serve(listener)
+}
+
+func (i *ingestServer) PushBatch(ctx context.Context, batch *ingestv1.Batch) (*ingestv1.BatchResult, error) {
+	return i.push(ctx, batch)
+}
+
+func (i *ingestServer) PushStream(stream ingestv1.Ingest_PushStreamServer) error {
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result, err := i.push(stream.Context(), batch)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
+
+func (i *ingestServer) push(ctx context.Context, batch *ingestv1.Batch) (*ingestv1.BatchResult, error) {
+	ingestBatchesCounter.Inc(1)
+	req, err := ingestDrainRequest(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ingestResponse{header: make(http.Header), status: http.StatusOK}
+	i.server.serveDrain(resp, req)
+	if resp.status >= 300 {
+		ingestRefusedCounter.Inc(1)
+		return nil, resp.err()
+	}
+	return &ingestv1.BatchResult{BatchId: batch.BatchId, Points: int64(resp.points), Rejected: int64(resp.rejected)}, nil
+}
+
+// The drain request a batch stands for: its frames and records as an lpx
+// body for its token, with the caller's credentials and request id
+func ingestDrainRequest(ctx context.Context, batch *ingestv1.Batch) (*http.Request, error) {
+	var body bytes.Buffer
+	for _, frame := range batch.Frames {
+		body.WriteString(strconv.Itoa(len(frame)) + " ")
+		body.Write(frame)
+	}
+	for n, record := range batch.Records {
+		source, err := ingestHeaderField(record.Source)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "record %d: source %s", n, err)
+		}
+		process, err := ingestHeaderField(record.Process)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "record %d: process %s", n, err)
+		}
+		frame := fmt.Sprintf("<134>1 %s host %s %s - %s",
+			time.Unix(0, record.TimeUnixMicros*int64(time.Microsecond)).UTC().Format(timestampLayouts[0]),
+			source, process, record.Message)
+		body.WriteString(strconv.Itoa(len(frame)) + " " + frame)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", DrainPath, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	req.Header.Set("Content-Type", "application/logplex-1")
+	req.Header.Set("Logplex-Msg-Count", strconv.Itoa(len(batch.Frames)+len(batch.Records)))
+	req.Header.Set("Logplex-Frame-Id", batch.BatchId)
+	if batch.Token != "" {
+		req.Header.Set("Logplex-Drain-Token", batch.Token)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			req.Header.Set("Authorization", values[0])
+		}
+		if values := md.Get("x-request-id"); len(values) > 0 {
+			req.Header.Set(requestIdHeader, values[0])
+		}
+	}
+	return req, nil
+}
+
+// A syslog header field, "-" when empty as it can't be left out. Fields are
+// printable ASCII without spaces, as anything else would shift the header's
+// fields and throw off the frames after it.
+func ingestHeaderField(s string) (string, error) {
+	if s == "" {
+		return "-", nil
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] > '~' {
+			return "", fmt.Errorf("%q has whitespace or non-printable characters", s)
+		}
+	}
+	return s, nil
+}
+
+// Records what serveDrain answers a batch with
+type ingestResponse struct {
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	points   int
+	rejected int
+}
+
+func (r *ingestResponse) Header() http.Header         { return r.header }
+func (r *ingestResponse) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *ingestResponse) WriteHeader(status int)      { r.status = status }
+
+func (r *ingestResponse) drainResult(points, rejected int) {
+	r.points, r.rejected = points, rejected
+}
+
+// The gRPC codes of the drain's error codes
+var ingestErrorCodes = map[string]codes.Code{
+	errAuthFailed:       codes.Unauthenticated,
+	errBadRequest:       codes.InvalidArgument,
+	errBodyRead:         codes.InvalidArgument,
+	errCanceled:         codes.Canceled,
+	errInsufficientRole: codes.PermissionDenied,
+	errMaintenance:      codes.Unavailable,
+	errOverCapacity:     codes.ResourceExhausted,
+	errPaused:           codes.Unavailable,
+	errShuttingDown:     codes.Unavailable,
+	errTokenNotAllowed:  codes.PermissionDenied,
+	errTooLarge:         codes.ResourceExhausted,
+	errUndelivered:      codes.Unavailable,
+}
+
+// The refusal as a gRPC status
+func (r *ingestResponse) err() error {
+	var response errorResponse
+	if err := json.Unmarshal(r.body.Bytes(), &response); err != nil {
+		return status.Errorf(codes.Unknown, "drain answered %d", r.status)
+	}
+	code, ok := ingestErrorCodes[response.Code]
+	if !ok {
+		code = codes.Internal
+	}
+	return status.Error(code, response.Message)
+}
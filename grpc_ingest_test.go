@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+	"github.com/heroku/lumbermill/proto/ingestv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func startIngest(t *testing.T, server *LumbermillServer) ingestv1.IngestClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %s", err)
+	}
+	server.serveIngest(listener)
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unable to connect: %s", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.ingest.Stop()
+	})
+	return ingestv1.NewIngestClient(conn)
+}
+
+func TestIngestPushBatch(t *testing.T) {
+	destination := NewDestination("ingest-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	client := startIngest(t, server)
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	result, err := client.PushBatch(context.Background(), &ingestv1.Batch{
+		Token:   "t.ingest",
+		BatchId: "batch-1",
+		Frames:  [][]byte{[]byte(lumbermilltest.SyslogLine("heroku", "router", router))},
+		Records: []*ingestv1.Record{
+			{TimeUnixMicros: time.Now().UnixNano() / 1000, Source: "heroku", Process: "router", Message: router},
+			{TimeUnixMicros: time.Now().UnixNano() / 1000, Source: "heroku", Process: "router", Message: "at=info status=bad"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if result.BatchId != "batch-1" || result.Points != 2 || result.Rejected != 1 {
+		t.Errorf("Expected 2 points and a rejected record, got %v", result)
+	}
+	if len(destination.points) != 2 {
+		t.Fatalf("Expected the points to be queued, got %d", len(destination.points))
+	}
+	if point := <-destination.points; point.Token != "t.ingest" || point.Type != Router {
+		t.Errorf("Expected a router point for the batch's token, got %v", point)
+	}
+}
+
+func TestIngestRejectsHeaderWhitespace(t *testing.T) {
+	destination := NewDestination("ingest-whitespace-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	client := startIngest(t, server)
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	_, err := client.PushBatch(context.Background(), &ingestv1.Batch{
+		Token: "t.ingest",
+		Records: []*ingestv1.Record{
+			{TimeUnixMicros: time.Now().UnixNano() / 1000, Source: "my app", Process: "router", Message: router},
+			{TimeUnixMicros: time.Now().UnixNano() / 1000, Source: "heroku", Process: "router", Message: router},
+		},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected a source with a space to be an invalid argument, got %v", err)
+	}
+	if len(destination.points) != 0 {
+		t.Errorf("Expected none of the batch to be queued, got %d points", len(destination.points))
+	}
+}
+
+func TestIngestPushStreamRefusals(t *testing.T) {
+	destination := NewDestination("ingest-stream-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	client := startIngest(t, server)
+
+	stream, err := client.PushStream(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	batch := &ingestv1.Batch{Token: "t.ingest", Frames: [][]byte{[]byte(lumbermilltest.SyslogLine("heroku", "router", router))}}
+	for i := 0; i < 2; i++ {
+		if err := stream.Send(batch); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if result, err := stream.Recv(); err != nil || result.Points != 1 {
+			t.Fatalf("Expected each batch to be answered in turn, got %v, %v", result, err)
+		}
+	}
+
+	// Batches without a token or credentials are refused like drain requests
+	_, err = client.PushBatch(context.Background(), &ingestv1.Batch{Frames: batch.Frames})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected a batch without a token to be unauthenticated, got %v", err)
+	}
+
+	server.Close()
+	if err := stream.Send(batch); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected batches to be refused while shutting down, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/heroku/lumbermill/proto/pointsv1"
+	"github.com/heroku/lumbermill/proto/sinkv1"
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// Whether gRPC sinks are called over TLS
+	GRPCSinkTLS = os.Getenv("GRPC_SINK_TLS") == "true"
+)
+
+// Writes series to a gRPC sink (proto/sink.proto). Calls are answered once
+// the series are stored, and their errors are taken as the HTTP statuses
+// InfluxDB would answer with, so posters quarantine and retry alike.
+type grpcWriter struct {
+	conn   *grpc.ClientConn
+	client sinkv1.SinkClient
+	sent   metrics.Counter
+}
+
+func newGRPCWriter(addr, name string) (*grpcWriter, error) {
+	creds := insecure.NewCredentials()
+	if GRPCSinkTLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	return &grpcWriter{
+		conn:   conn,
+		client: sinkv1.NewSinkClient(conn),
+		sent:   metrics.GetOrRegisterCounter("lumbermill.poster.grpc.points."+name, metrics.DefaultRegistry),
+	}, nil
+}
+
+func (w *grpcWriter) Write(series []*influx.Series) error {
+	return w.WriteContext(context.Background(), series)
+}
+
+func (w *grpcWriter) WriteContext(ctx context.Context, series []*influx.Series) error {
+	req := &sinkv1.WriteRequest{Series: make([]*sinkv1.Series, 0, len(series))}
+	points := 0
+	for _, s := range series {
+		req.Series = append(req.Series, sinkSeries(s))
+		points += len(s.Points)
+	}
+	if _, err := w.client.Write(ctx, req); err != nil {
+		return grpcBackendError(err)
+	}
+	w.sent.Inc(int64(points))
+	return nil
+}
+
+// Errors the sink answered with as the backendError of the HTTP status
+// with the same meaning, or as they are when the call didn't get that far
+func grpcBackendError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	var code int
+	switch s.Code() {
+	case codes.InvalidArgument:
+		code = http.StatusBadRequest
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.Canceled, codes.DeadlineExceeded:
+		return err
+	default:
+		code = http.StatusServiceUnavailable
+	}
+	return newBackendError(code, []byte(s.Message()))
+}
+
+func sinkSeries(s *influx.Series) *sinkv1.Series {
+	rows := make([]*sinkv1.Row, len(s.Points))
+	for i, point := range s.Points {
+		values := make([]*pointsv1.Value, len(point))
+		for j, v := range point {
+			values[j] = pointValue(v)
+		}
+		rows[i] = &sinkv1.Row{Values: values}
+	}
+	return &sinkv1.Series{Name: s.Name, Columns: s.Columns, Rows: rows}
+}
+
+// A column's value as a pointsv1.Value, nil having no kind
+func pointValue(v interface{}) *pointsv1.Value {
+	switch v := v.(type) {
+	case nil:
+		return &pointsv1.Value{}
+	case int:
+		return &pointsv1.Value{Kind: &pointsv1.Value_Int{Int: int64(v)}}
+	case int64:
+		return &pointsv1.Value{Kind: &pointsv1.Value_Int{Int: v}}
+	case float64:
+		return &pointsv1.Value{Kind: &pointsv1.Value_Float{Float: v}}
+	case string:
+		return &pointsv1.Value{Kind: &pointsv1.Value_String_{String_: v}}
+	case bool:
+		return &pointsv1.Value{Kind: &pointsv1.Value_Bool{Bool: v}}
+	}
+	return &pointsv1.Value{Kind: &pointsv1.Value_String_{String_: fmt.Sprint(v)}}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/heroku/lumbermill/proto/sinkv1"
+	influx "github.com/influxdb/influxdb-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A sink keeping what it's written, or failing with err
+type fakeSink struct {
+	sinkv1.UnimplementedSinkServer
+	sync.Mutex
+	series []*sinkv1.Series
+	err    error
+}
+
+func (s *fakeSink) Write(ctx context.Context, req *sinkv1.WriteRequest) (*sinkv1.WriteResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.series = append(s.series, req.Series...)
+	return &sinkv1.WriteResponse{}, nil
+}
+
+func startFakeSink(t *testing.T) (*fakeSink, string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %s", err)
+	}
+	sink := new(fakeSink)
+	server := grpc.NewServer()
+	sinkv1.RegisterSinkServer(server, sink)
+	go server.Serve(listener)
+	return sink, listener.Addr().String(), server.Stop
+}
+
+func TestGRPCWriter(t *testing.T) {
+	sink, addr, stop := startFakeSink(t)
+	defer stop()
+	writer, err := transports[TransportGRPC](addr, "grpc-test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	series := []*influx.Series{{
+		Name:    "router.t.a",
+		Columns: []string{"time", "status", "service", "tls", "canary"},
+		Points:  [][]interface{}{{int64(1), 200, 1.5, nil, true}, {int64(2), 404, 2.5, "TLSv1.3", false}},
+	}}
+	if err := writer.Write(series); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(sink.series) != 1 || sink.series[0].Name != "router.t.a" || len(sink.series[0].Rows) != 2 {
+		t.Fatalf("Expected the series to be written, got %v", sink.series)
+	}
+	values := sink.series[0].Rows[1].Values
+	if values[0].GetInt() != 2 || values[1].GetInt() != 404 || values[2].GetFloat() != 2.5 || values[3].GetString_() != "TLSv1.3" || values[4].GetKind() == nil {
+		t.Errorf("Expected the values to keep their types, got %v", values)
+	}
+	if sink.series[0].Rows[0].Values[3].GetKind() != nil {
+		t.Errorf("Expected a null column to have no kind, got %v", sink.series[0].Rows[0].Values[3])
+	}
+
+	sink.err = status.Error(codes.InvalidArgument, "bad series router.t.a")
+	if err, ok := writer.Write(series).(*backendError); !ok || !err.permanent() {
+		t.Errorf("Expected an invalid argument to be a permanent error, got %v", err)
+	}
+	sink.err = status.Error(codes.Unavailable, "down")
+	if err, ok := writer.Write(series).(*backendError); !ok || err.permanent() {
+		t.Errorf("Expected an unavailable sink to be tried again, got %v", err)
+	}
+	sink.err = status.Error(codes.PermissionDenied, "no")
+	if err, ok := writer.Write(series).(*backendError); !ok || !err.auth() {
+		t.Errorf("Expected a permission error to be an auth error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Write a heartbeat point to every destination this often, and with
+	// HEARTBEAT_VERIFY query each one back an interval later, to catch a
+	// destination accepting writes but losing them (e.g. a misconfigured
+	// proxy). Off unless set.
+	HeartbeatInterval = parseDurationSetting("HEARTBEAT_INTERVAL", os.Getenv("HEARTBEAT_INTERVAL"), 0)
+	HeartbeatVerify   = os.Getenv("HEARTBEAT_VERIFY") == "true"
+)
+
+const heartbeatSeries = "lumbermill.heartbeat"
+
+var heartbeatColumns = []string{"time", "seq", "host"}
+
+// Heartbeats of a destination
+type heartbeatState struct {
+	seq     int
+	pending int // Written, but not verified yet
+
+	failures metrics.Counter
+	lost     metrics.Counter
+	verified metrics.Counter
+}
+
+// Heartbeat points written to every destination
+type Heartbeats struct {
+	sync.Mutex
+	host         string
+	verify       bool
+	destinations map[string]*heartbeatState
+}
+
+func NewHeartbeats(verify bool) *Heartbeats {
+	host, _ := os.Hostname()
+	return &Heartbeats{host: host, verify: verify, destinations: make(map[string]*heartbeatState)}
+}
+
+// Verifies the previous heartbeat of each destination, and writes the next
+func (h *Heartbeats) Beat() {
+	h.Lock()
+	defer h.Unlock()
+	for name, w := range allSeriesWriters() {
+		state := h.destinations[name]
+		if state == nil {
+			state = &heartbeatState{
+				failures: metrics.GetOrRegisterCounter("lumbermill.heartbeat.errors."+name, metrics.DefaultRegistry),
+				lost:     metrics.GetOrRegisterCounter("lumbermill.heartbeat.lost."+name, metrics.DefaultRegistry),
+				verified: metrics.GetOrRegisterCounter("lumbermill.heartbeat.verified."+name, metrics.DefaultRegistry),
+			}
+			h.destinations[name] = state
+		}
+		h.beat(name, w, state)
+	}
+}
+
+func (h *Heartbeats) beat(name string, w SeriesWriter, state *heartbeatState) {
+	// Only destinations written over HTTP can be queried back
+	httpWriter, queryable := w.(*seriesWriter)
+	if state.pending != 0 && queryable {
+		found, err := h.landed(httpWriter, state.pending)
+		switch {
+		case err != nil:
+			log.Printf("Error querying heartbeat %d on %s: %s\n", state.pending, name, err)
+		case found:
+			state.verified.Inc(1)
+		default:
+			state.lost.Inc(1)
+			log.Printf("at=heartbeat_lost destination=%s seq=%d\n", name, state.pending)
+		}
+		state.pending = 0
+	}
+
+	state.seq++
+	series := &influx.Series{
+		Name:    heartbeatSeries,
+		Columns: heartbeatColumns,
+		Points:  [][]interface{}{{time.Now().UnixNano() / int64(time.Microsecond), state.seq, h.host}},
+	}
+	if err := w.Write([]*influx.Series{series}); err != nil {
+		state.failures.Inc(1)
+		log.Printf("Error writing heartbeat to %s: %s\n", name, err)
+		return
+	}
+	if h.verify && queryable {
+		state.pending = state.seq
+	}
+}
+
+// Whether heartbeat seq was stored
+func (h *Heartbeats) landed(w *seriesWriter, seq int) (bool, error) {
+	query := fmt.Sprintf(`select seq from "%s" where seq = %d and host = '%s'`, heartbeatSeries, seq, h.host)
+	var found []struct{ Points [][]interface{} }
+	if err := w.api("GET", "/db/"+w.config.Database+"/series", url.Values{"q": {query}}, nil, &found); err != nil {
+		return false, err
+	}
+	for _, series := range found {
+		if len(series.Points) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Beats every so often
+func (h *Heartbeats) Run(every time.Duration) {
+	for {
+		time.Sleep(every)
+		h.Beat()
+	}
+}
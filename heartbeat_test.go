@@ -0,0 +1,28 @@
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestHeartbeatsCatchLostWrites(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	NewPoster(newTestWriter(db, ""), "heartbeat-test", NewDestination("heartbeat-test", 10), new(sync.WaitGroup))
+
+	heartbeats := NewHeartbeats(true)
+	db.Respond(lumbermilltest.Response{Status: 200, Lose: true})
+	heartbeats.Beat()
+	heartbeats.Beat()
+	heartbeats.Beat()
+
+	state := heartbeats.destinations["heartbeat-test"]
+	if state.lost.Count() != 1 || state.verified.Count() != 1 || state.failures.Count() != 0 {
+		t.Errorf("Expected the first heartbeat to be lost and the second verified, got lost=%d verified=%d", state.lost.Count(), state.verified.Count())
+	}
+	if points := db.Points(heartbeatSeries); len(points) != 2 {
+		t.Errorf("Expected 2 heartbeats to land, got %v", points)
+	}
+}
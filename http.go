@@ -2,55 +2,104 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"log"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
+
+	"google.golang.org/grpc"
 )
 
 type LumbermillServer struct {
 	sync.WaitGroup
 	connectionCloser chan struct{}
 	hashRing         *HashRing
+	memoryBudget     *MemoryBudget
+	throughput       *Throughput  // Autoscale signals, nil when disabled
+	cardinality      *Cardinality // nil unless cardinality is monitored
+	addons           *AddonStore  // Add-on resources and self-service drains, nil when disabled
+	destinations     []*Destination
+	paused           *PausedTokens
+	dedup            *DynoErrorDedup // nil unless dyno errors are coalesced
+	memorySamples    *MemorySamples
+	restarts         *DynoRestarts
+	formations       *Formations   // nil unless dyno sizes are looked up
+	geoip            *GeoIP        // nil unless router clients are located
+	patterns         *SeenPatterns // nil unless user lines are fingerprinted
+	slos             *SLOs         // nil without SLO definitions
+	reports          *Reports      // nil without daily reports
+	backpressure     *Backpressure // nil unless connect times are watched
+	processTypes     *ProcessTypes // nil unless dyno types are aggregated
 	http             *http.Server
-	shutdownChan     ShutdownChan
-	isShuttingDown   bool
+	mux              *http.ServeMux
+	drainRings       map[string]*HashRing // Rings of the drain routes besides DrainPath, by path
+	derived          *derivedRoutes       // Where derived points of each token are posted
+	vhosts           map[string]*vhostTenant
+	shutdown         *Shutdown
+	ingest           *grpc.Server // nil unless the gRPC ingestion service is served
 }
 
 func NewLumbermillServer(server *http.Server, hashRing *HashRing) *LumbermillServer {
 
 	s := &LumbermillServer{
 		connectionCloser: make(chan struct{}),
-		shutdownChan:     make(chan struct{}),
+		shutdown:         NewShutdown(),
 		http:             server,
 		hashRing:         hashRing,
+		paused:           NewPausedTokens(),
+		memorySamples:    NewMemorySamples(),
+		restarts:         NewDynoRestarts(),
+		mux:              http.NewServeMux(),
+		drainRings:       make(map[string]*HashRing),
+		derived:          newDerivedRoutes(hashRing),
 	}
 
-	mux := http.NewServeMux()
-
-	mux.HandleFunc("/drain", func(w http.ResponseWriter, r *http.Request) {
-		s.serveDrain(w, r)
-		s.recycleConnection(w)
-	})
+	mux := s.mux
+	mux.HandleFunc(DrainPath, s.serveDrainRoute)
 
 	mux.HandleFunc("/health", s.serveHealth)
 	mux.HandleFunc("/target/", s.serveTarget)
+	mux.HandleFunc("/autoscale/", s.serveAutoscale)
+	mux.HandleFunc("/aggregates/", s.serveAggregates)
+	mux.HandleFunc("/dashboards/grafana", s.serveGrafanaDashboard)
+	mux.HandleFunc("/admin/audit", s.serveAdminAudit)
+	mux.HandleFunc("/admin/debug", s.serveAdminDebug)
+	mux.HandleFunc("/admin/ring", s.serveAdminRing)
+	mux.HandleFunc("/admin/paused", s.serveAdminPaused)
+	mux.HandleFunc("/admin/maintenance", s.serveAdminMaintenance)
+	mux.HandleFunc("/admin/status", s.serveAdminStatus)
+	mux.HandleFunc("/admin/top", s.serveAdminTop)
+	mux.HandleFunc("/admin/cardinality", s.serveAdminCardinality)
+	mux.HandleFunc("/admin/quarantine", s.serveAdminQuarantine)
+	mux.HandleFunc("/admin/faults", s.serveAdminFaults)
+	mux.HandleFunc("/admin/flags", s.serveAdminFlags)
+	mux.HandleFunc("/heroku/resources", s.serveAddonResources)
+	mux.HandleFunc("/heroku/resources/", s.serveAddonResources)
+	mux.HandleFunc("/heroku/sso", s.serveAddonSSO)
+	mux.HandleFunc("/drains", s.serveDrainURLs)
+	mux.HandleFunc("/drains/", s.serveDrainURLs)
 
 	s.http.Handler = mux
+	// Requests are canceled when shutting down runs out of time
+	s.http.BaseContext = func(net.Listener) context.Context { return s.shutdown.Context() }
 
 	return s
 }
 
+// Starts shutting down
 func (s *LumbermillServer) Close() error {
-	s.shutdownChan <- struct{}{}
+	s.shutdown.Begin()
 	return nil
 }
 
 func (s *LumbermillServer) scheduleConnectionRecycling(after time.Duration) {
-	for !s.isShuttingDown {
+	for s.shutdown.Phase() == phaseRunning {
 		time.Sleep(after)
 		s.connectionCloser <- struct{}{}
 	}
@@ -61,14 +110,15 @@ func (s *LumbermillServer) recycleConnection(w http.ResponseWriter) {
 	case <-s.connectionCloser:
 		w.Header().Set("Connection", "close")
 	default:
-		if s.isShuttingDown {
+		if s.shutdown.Phase() != phaseRunning {
 			w.Header().Set("Connection", "close")
 		}
 	}
 }
 
 func (s *LumbermillServer) Run(connRecycle time.Duration) {
-	go s.awaitShutdown()
+	go s.memorySamples.Run(time.Minute)
+	go s.restarts.Run(time.Minute)
 	go s.scheduleConnectionRecycling(connRecycle)
 
 	if err := s.http.ListenAndServe(); err != nil {
@@ -76,57 +126,94 @@ func (s *LumbermillServer) Run(connRecycle time.Duration) {
 	}
 }
 
-// Health Checks, so just say 200 - OK
+// Health Checks, so just say 200 - OK, with the shutdown phase
 // TODO: Actual healthcheck
 func (s *LumbermillServer) serveHealth(w http.ResponseWriter, r *http.Request) {
-	if s.isShuttingDown {
-		http.Error(w, "Shutting Down", 503)
+	if phase := s.shutdown.Phase(); phase != phaseRunning {
+		writeError(w, r, http.StatusServiceUnavailable, errShuttingDown, "Shutting Down ("+phase.String()+")")
+		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	writeJSON(w, map[string]string{"state": phaseRunning.String()})
 }
 
-func (s *LumbermillServer) awaitShutdown() {
-	<-s.shutdownChan
-	log.Printf("Shutting down.")
-	s.isShuttingDown = true
+// Authenticates a request for fleet-wide data (targets, aggregates,
+// dashboards) as USER. Drain users only see their own tokens, so aren't
+// good for these.
+func (s *LumbermillServer) checkAuth(r *http.Request) error {
+	user, err := s.authenticate(r)
+	if err == nil && user != secrets.Get("USER", User) {
+		return errors.New("Drain users can't read other tokens' data")
+	}
+	return err
 }
 
-func (s *LumbermillServer) checkAuth(r *http.Request) error {
+// Returns the user the request authenticated as, USER or one of
+// DRAIN_USERS
+func (s *LumbermillServer) authenticate(r *http.Request) (string, error) {
 	header := r.Header.Get("Authorization")
 	if header == "" {
-		return errors.New("Authorization required")
+		return "", errors.New("Authorization required")
 	}
 	headerParts := strings.SplitN(header, " ", 2)
 	if len(headerParts) != 2 {
-		return errors.New("Authorization header is malformed")
+		return "", errors.New("Authorization header is malformed")
 	}
 
 	method := headerParts[0]
 	if method != "Basic" {
-		return errors.New("Only Basic Authorization is accepted")
+		return "", errors.New("Only Basic Authorization is accepted")
 	}
 
 	encodedUserPass := headerParts[1]
 	decodedUserPass, err := base64.StdEncoding.DecodeString(encodedUserPass)
 	if err != nil {
-		return errors.New("Authorization header is malformed")
+		return "", errors.New("Authorization header is malformed")
 	}
 
 	userPassParts := bytes.SplitN(decodedUserPass, []byte{':'}, 2)
 	if len(userPassParts) != 2 {
-		return errors.New("Authorization header is malformed")
+		return "", errors.New("Authorization header is malformed")
 	}
 
 	user := userPassParts[0]
 	pass := userPassParts[1]
 
-	if string(user) != User {
-		return errors.New("Unknown user")
+	if string(user) == secrets.Get("USER", User) {
+		if string(pass) != secrets.Get("PASSWORD", Password) {
+			return "", errors.New("Incorrect token")
+		}
+		return string(user), nil
+	}
+
+	password, found := parseKeyValueList(secrets.Get("DRAIN_USERS", DrainUsers))[string(user)]
+	if !found {
+		return "", errUnknownUser
 	}
-	if string(pass) != Password {
-		return errors.New("Incorrect token")
+	if string(pass) != password {
+		return "", errors.New("Incorrect token")
 	}
 
-	return nil
+	return string(user), nil
+}
+
+var errUnknownUser = errors.New("Unknown user")
+
+// Returns the user a drain request authenticated as, and the add-on
+// resource when it's one of theirs. Add-on credentials are part of drain
+// URLs, so they're only good for draining the resource's own token.
+func (s *LumbermillServer) authenticateDrain(r *http.Request) (string, *AddonResource, error) {
+	user, err := s.authenticate(r)
+	if err != errUnknownUser {
+		return user, nil, err
+	}
+	id, pass, _ := r.BasicAuth()
+	resource := s.addons.Get(id)
+	if resource == nil {
+		return "", nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(pass), []byte(resource.Password)) != 1 {
+		return "", nil, errors.New("Incorrect token")
+	}
+	return id, resource, nil
 }
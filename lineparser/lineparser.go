@@ -0,0 +1,39 @@
+// Package lineparser is what lumbermill's parsing plugins implement, to
+// parse bespoke log formats into lumbermill's series without forking it.
+//
+// A plugin is a Go plugin (go build -buildmode=plugin) exporting
+//
+//	func New(config string) (lineparser.LineParser, error)
+//
+// lumbermill hands it the lines of the tokens configured to use it, before
+// parsing them itself.
+package lineparser
+
+// A line of a drain request
+type Line struct {
+	Token     string
+	Hostname  string
+	Name      string // The syslog app name, e.g. "heroku" or "app"
+	Procid    string // e.g. "router" or "web.1"
+	Timestamp int64  // Microseconds since the epoch
+	Msg       []byte // Only valid during the call
+}
+
+// A point for one of lumbermill's series, e.g. "router" or "events.dyno".
+// Values are its columns after time, in order. Trailing columns may be left
+// out, and are null.
+//
+// Values should be of the types lumbermill writes the columns as: int,
+// float64, string or bool. Others that convert without loss (e.g. the
+// float64 2 for an int column, or "200") are converted, and points with a
+// value that can't be are dropped.
+type Point struct {
+	Series string
+	Values []interface{}
+}
+
+type LineParser interface {
+	// Parses line into points, returning whether it handled the line.
+	// Lines it doesn't handle are parsed by lumbermill as usual.
+	ParseLine(line Line) ([]Point, bool)
+}
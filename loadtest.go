@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apg/lpxgen"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// Kinds of lines a load test can send
+var loadtestKinds = []string{"router", "mem", "load", "errors"}
+
+// Formats a framed syslog line of the given kind, using lpxgen's Heroku
+// messages. Lines are always for the drain's own token.
+func loadtestLine(kind string) string {
+	procid, prival := "router", "<158>1"
+	var msg string
+	switch kind {
+	case "router":
+		msg = lpxgen.Router.Msg()
+	case "mem", "load":
+		procid, prival = "web."+strconv.Itoa(1+rand.Intn(5)), "<45>1"
+		if kind == "mem" {
+			msg = lpxgen.DynoMem.Msg()
+		} else {
+			msg = lpxgen.DynoLoad.Msg()
+		}
+	case "errors":
+		if rand.Intn(2) == 0 {
+			msg = fmt.Sprintf(`at=error code=H12 desc="Request timeout" method=GET path="/" host=loadtest.herokuapp.com request_id=%s dyno=web.1 connect=1ms service=30000ms status=503 bytes=0`, lpxgen.UUID4())
+		} else {
+			procid, prival = "web."+strconv.Itoa(1+rand.Intn(5)), "<45>1"
+			msg = "Error R14 (Memory quota exceeded)"
+		}
+	}
+	line := fmt.Sprintf("%s %s host heroku %s - %s", prival, time.Now().UTC().Format(lpxgen.TimeFormat), procid, msg)
+	return strconv.Itoa(len(line)) + " " + line
+}
+
+// Parses a line mix as "<kind>:<weight>,...", e.g. "router:0.8,errors:0.2"
+func parseLoadtestMix(mix string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(mix, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		known := false
+		for _, kind := range loadtestKinds {
+			known = known || kind == kv[0]
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown line kind %q, expected one of %s", kv[0], strings.Join(loadtestKinds, ", "))
+		}
+		weight := 1.0
+		if len(kv) == 2 {
+			var err error
+			if weight, err = strconv.ParseFloat(kv[1], 64); err != nil || weight < 0 {
+				return nil, fmt.Errorf("invalid weight %q for %s", kv[1], kv[0])
+			}
+		}
+		weights[kv[0]] += weight
+	}
+	return weights, nil
+}
+
+// Picks a kind of line according to the weights
+func pickLoadtestKind(weights map[string]float64) string {
+	sum := 0.0
+	for _, kind := range loadtestKinds {
+		sum += weights[kind]
+	}
+	n := rand.Float64() * sum
+	for _, kind := range loadtestKinds {
+		if n -= weights[kind]; n < 0 {
+			return kind
+		}
+	}
+	return "router"
+}
+
+// What a load test achieved
+type loadtestResult struct {
+	sync.Mutex
+	requests int
+	lines    int
+	failures int // Requests that didn't get a response
+	statuses map[int]int
+	latency  metrics.Timer
+}
+
+func (r *loadtestResult) record(lines, status int, took time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	r.requests++
+	r.lines += lines
+	if status == 0 {
+		r.failures++
+	} else {
+		r.statuses[status]++
+	}
+	r.latency.Update(took)
+}
+
+func (r *loadtestResult) errors() int {
+	errors := r.failures
+	for status, n := range r.statuses {
+		if status >= 300 {
+			errors += n
+		}
+	}
+	return errors
+}
+
+func (r *loadtestResult) report(out io.Writer, elapsed time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	seconds := elapsed.Seconds()
+	errorRate := 0.0
+	if r.requests > 0 {
+		errorRate = float64(r.errors()) / float64(r.requests)
+	}
+	fmt.Fprintf(out, "at=loadtest requests=%d lines=%d elapsed=%s rps=%.1f lines_per_sec=%.1f errors=%d error_rate=%.4f p50=%s p99=%s\n",
+		r.requests, r.lines, elapsed.Round(time.Millisecond), float64(r.requests)/seconds, float64(r.lines)/seconds, r.errors(), errorRate,
+		time.Duration(r.latency.Percentile(0.5)).Round(time.Microsecond), time.Duration(r.latency.Percentile(0.99)).Round(time.Microsecond))
+
+	statuses := make([]int, 0, len(r.statuses))
+	for status := range r.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(out, "at=loadtest status=%d count=%d\n", status, r.statuses[status])
+	}
+	if r.failures > 0 {
+		fmt.Fprintf(out, "at=loadtest status=none count=%d\n", r.failures)
+	}
+}
+
+// Runs `lumbermill loadtest [options] URL`, driving the drain at URL with
+// generated batches and reporting the throughput achieved. Returns the exit
+// status.
+func runLoadtest(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	flags.SetOutput(out)
+	duration := flags.Duration("duration", time.Minute, "How long to run for")
+	batches := flags.Int("batches", 0, "Number of batches to send, instead of running for -duration")
+	concurrency := flags.Int("concurrency", 10, "Concurrent requests")
+	minBatch := flags.Int("min", 1, "Minimum number of lines in a batch")
+	maxBatch := flags.Int("max", 100, "Maximum number of lines in a batch")
+	tokens := flags.Int("tokens", 100, "Number of distinct tokens")
+	mix := flags.String("mix", "router:0.7,mem:0.1,load:0.1,errors:0.1", "Mix of lines as <router|mem|load|errors>:<weight>,...")
+	user := flags.String("user", os.Getenv("USER"), "User to authenticate as")
+	password := flags.String("password", os.Getenv("PASSWORD"), "Password to authenticate with")
+	seed := flags.Int64("seed", 1, "Random seed, so runs are reproducible")
+	flags.Usage = func() {
+		fmt.Fprintln(out, "usage: lumbermill loadtest [options] URL")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *minBatch < 1 || *maxBatch < *minBatch || *tokens < 1 || *concurrency < 1 {
+		flags.Usage()
+		return 2
+	}
+	weights, err := parseLoadtestMix(*mix)
+	if err != nil {
+		fmt.Fprintln(out, "Invalid -mix:", err)
+		return 2
+	}
+	url := flags.Arg(0)
+
+	rand.Seed(*seed)
+	tokenNames := make([]string, *tokens)
+	for i := range tokenNames {
+		tokenNames[i] = fmt.Sprintf("t.loadtest-%d", i)
+	}
+
+	// Batches are generated up front, so generating them isn't measured
+	type batch struct {
+		token string
+		lines int
+		body  []byte
+	}
+	generate := func() batch {
+		n := *minBatch + rand.Intn(*maxBatch-*minBatch+1)
+		var body bytes.Buffer
+		for i := 0; i < n; i++ {
+			body.WriteString(loadtestLine(pickLoadtestKind(weights)))
+		}
+		return batch{tokenNames[rand.Intn(len(tokenNames))], n, body.Bytes()}
+	}
+	pool := make([]batch, 1000)
+	if *batches > 0 && *batches < len(pool) {
+		pool = pool[:*batches]
+	}
+	for i := range pool {
+		pool[i] = generate()
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	result := &loadtestResult{statuses: make(map[int]int), latency: metrics.NewTimer()}
+	work := make(chan batch)
+	var wg sync.WaitGroup
+	for w := 0; w < *concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range work {
+				req, _ := http.NewRequest("POST", url, bytes.NewReader(b.body))
+				req.Header.Set("Content-Type", "application/logplex-1")
+				req.Header.Set("Logplex-Msg-Count", strconv.Itoa(b.lines))
+				req.Header.Set("Logplex-Frame-Id", lpxgen.RandomFrameId())
+				req.Header.Set("Logplex-Drain-Token", b.token)
+				if *user != "" {
+					req.SetBasicAuth(*user, *password)
+				}
+				start := time.Now()
+				status := 0
+				if resp, err := client.Do(req); err == nil {
+					io.Copy(ioutil.Discard, resp.Body)
+					resp.Body.Close()
+					status = resp.StatusCode
+				}
+				result.record(b.lines, status, time.Since(start))
+			}
+		}()
+	}
+
+	start := time.Now()
+	deadline := start.Add(*duration)
+	for i := 0; *batches > 0 && i < *batches || *batches == 0 && time.Now().Before(deadline); i++ {
+		work <- pool[i%len(pool)]
+	}
+	close(work)
+	wg.Wait()
+
+	result.report(out, time.Since(start))
+	return 0
+}
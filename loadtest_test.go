@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseLoadtestMix(t *testing.T) {
+	weights, err := parseLoadtestMix("router:0.8,errors")
+	if err != nil || weights["router"] != 0.8 || weights["errors"] != 1 || weights["mem"] != 0 {
+		t.Errorf("Unexpected weights %v (%v)", weights, err)
+	}
+	if _, err := parseLoadtestMix("router:0.8,scheduler:0.2"); err == nil {
+		t.Errorf("Expected an unknown kind to be refused")
+	}
+	if kind := pickLoadtestKind(map[string]float64{"load": 1}); kind != "load" {
+		t.Errorf("Expected load lines only, got %s", kind)
+	}
+}
+
+func TestLoadtest(t *testing.T) {
+	destination := NewDestination("loadtest-test", 100000)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	lumbermill := NewLumbermillServer(&http.Server{}, hashRing)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		lumbermill.serveDrain(w, r)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	if code := runLoadtest([]string{"-batches", "20", "-concurrency", "2", "-max", "10", "-tokens", "3", "-user", "", server.URL + "/drain"}, &out); code != 0 {
+		t.Fatalf("Load test failed (%d): %s", code, out.String())
+	}
+	report := out.String()
+	for _, expected := range []string{"requests=20 ", "errors=1 ", "status=204 count=19", "status=503 count=1"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected %q in the report, got %s", expected, report)
+		}
+	}
+	if len(destination.points) == 0 {
+		t.Errorf("Expected the batches to be parsed into points")
+	}
+
+	if code := runLoadtest([]string{"-mix", "cron", server.URL}, &out); code != 2 {
+		t.Errorf("Expected an invalid mix to be refused, got %d", code)
+	}
+}
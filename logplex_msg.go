@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+var (
+	logplexErrorSentinel = []byte("Error L")
+	logplexDroppedMarker = []byte("): ")
+)
+
+var errLogplexErrorTooShort = errors.New("logplex error too short for a code")
+
+// Logplex's errors about a drain, e.g.
+// Error L10 (output buffer overflow): 7 messages dropped since 2015-03-05T18:20:00+00:00.
+// L10 and L11 mean the drain isn't keeping up, L12 that logplex itself
+// isn't.
+type logplexError struct {
+	Code    string
+	Dropped int // 0 when the line doesn't say
+}
+
+func parseLogplexError(msg []byte) (logplexError, error) {
+	le := logplexError{}
+	start := len(logplexErrorSentinel) - 1
+	if len(msg) < start+3 {
+		return le, errLogplexErrorTooShort
+	}
+	if _, err := strconv.Atoi(string(msg[start+1 : start+3])); err != nil {
+		return le, err
+	}
+	le.Code = string(msg[start : start+3])
+
+	if i := bytes.Index(msg, logplexDroppedMarker); i != -1 {
+		rest := msg[i+len(logplexDroppedMarker):]
+		end := 0
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		le.Dropped, _ = strconv.Atoi(string(rest[:end]))
+	}
+	return le, nil
+}
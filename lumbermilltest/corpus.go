@@ -0,0 +1,48 @@
+package lumbermilltest
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Loads a corpus of syslog lines, one per line, from the *.log files in dir
+// (e.g. lumbermill's testdata/corpus). Blank lines and lines starting with
+// # are skipped. The result maps file names, without extension, to their
+// lines.
+func LoadCorpus(dir string) (map[string][]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	corpus := make(map[string][]string)
+	for _, path := range files {
+		lines, err := loadCorpusFile(path)
+		if err != nil {
+			return nil, err
+		}
+		corpus[strings.TrimSuffix(filepath.Base(path), ".log")] = lines
+	}
+	return corpus, nil
+}
+
+func loadCorpusFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
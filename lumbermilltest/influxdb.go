@@ -0,0 +1,239 @@
+// Package lumbermilltest provides fakes for testing lumbermill pipelines: a
+// fake InfluxDB that records the points written to it and helpers to build
+// logplex drain requests.
+package lumbermilltest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// A series as written to the InfluxDB 0.8 HTTP API
+type Series struct {
+	Name    string          `json:"name"`
+	Columns []string        `json:"columns"`
+	Points  [][]interface{} `json:"points"`
+}
+
+// A single write received by a FakeInfluxDB
+type Write struct {
+	Database string
+	Encoding string // Content-Encoding of the payload
+	Query    url.Values
+	Series   []Series
+}
+
+// A scripted response to a write
+type Response struct {
+	Status int
+	Body   string
+	Lose   bool // Accept the write without recording it, like a broken proxy
+}
+
+// A fake InfluxDB 0.8 HTTP API that records the series written to it. Its
+// responses can be scripted to rehearse failures.
+type FakeInfluxDB struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	writes   []Write
+	script   []Response
+	delay    time.Duration
+	rejected map[string]bool
+	notify   chan struct{}
+}
+
+func newFakeInfluxDB() *FakeInfluxDB {
+	return &FakeInfluxDB{rejected: make(map[string]bool), notify: make(chan struct{}, 1)}
+}
+
+// Starts a fake InfluxDB serving plain HTTP
+func NewFakeInfluxDB() *FakeInfluxDB {
+	db := newFakeInfluxDB()
+	db.Server = httptest.NewServer(db)
+	return db
+}
+
+// Starts a fake InfluxDB serving HTTPS with a self signed certificate
+func NewTLSFakeInfluxDB() *FakeInfluxDB {
+	db := newFakeInfluxDB()
+	db.Server = httptest.NewTLSServer(db)
+	return db
+}
+
+// The host:port the fake is listening on
+func (db *FakeInfluxDB) Host() string {
+	return strings.SplitN(db.URL, "://", 2)[1]
+}
+
+// Uses the given responses, in order, for the next writes. Writes answered
+// with a non 2xx status aren't recorded.
+func (db *FakeInfluxDB) Respond(responses ...Response) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.script = append(db.script, responses...)
+}
+
+// Waits this long before answering each write
+func (db *FakeInfluxDB) SetDelay(delay time.Duration) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.delay = delay
+}
+
+// Answers payloads with this Content-Encoding with a 415
+func (db *FakeInfluxDB) RejectEncoding(encoding string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.rejected[encoding] = true
+}
+
+// The writes recorded so far
+func (db *FakeInfluxDB) Writes() []Write {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return append([]Write(nil), db.writes...)
+}
+
+// The points written to the named series so far
+func (db *FakeInfluxDB) Points(series string) [][]interface{} {
+	points := make([][]interface{}, 0)
+	for _, w := range db.Writes() {
+		for _, s := range w.Series {
+			if s.Name == series {
+				points = append(points, s.Points...)
+			}
+		}
+	}
+	return points
+}
+
+// The number of points written to all series so far
+func (db *FakeInfluxDB) PointCount() int {
+	n := 0
+	for _, w := range db.Writes() {
+		for _, s := range w.Series {
+			n += len(s.Points)
+		}
+	}
+	return n
+}
+
+// Waits until at least n points were written, returning false on timeout
+func (db *FakeInfluxDB) WaitForPoints(n int, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for db.PointCount() < n {
+		select {
+		case <-db.notify:
+		case <-deadline:
+			return false
+		}
+	}
+	return true
+}
+
+func (db *FakeInfluxDB) nextResponse() (Response, time.Duration) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	response := Response{Status: http.StatusOK}
+	if len(db.script) > 0 {
+		response = db.script[0]
+		db.script = db.script[1:]
+	}
+	return response, db.delay
+}
+
+// Answers a query with the points of every series it names in double
+// quotes. Conditions are ignored.
+func (db *FakeInfluxDB) serveQuery(w http.ResponseWriter, q string) {
+	found := make([]Series, 0)
+	for _, write := range db.Writes() {
+		for _, s := range write.Series {
+			if strings.Contains(q, `"`+s.Name+`"`) {
+				found = append(found, s)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(found)
+}
+
+func (db *FakeInfluxDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "db" || parts[2] != "series" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method == "GET" {
+		db.serveQuery(w, r.URL.Query().Get("q"))
+		return
+	}
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	encoding := r.Header.Get("Content-Encoding")
+	db.mu.Lock()
+	rejected := db.rejected[encoding]
+	db.mu.Unlock()
+	if rejected {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	response, delay := db.nextResponse()
+	time.Sleep(delay)
+
+	var body io.Reader = r.Body
+	switch encoding {
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = gz
+	case "snappy":
+		body = snappy.NewReader(r.Body)
+	case "zstd":
+		zr, err := zstd.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer zr.Close()
+		body = zr
+	}
+
+	write := Write{Database: parts[1], Encoding: encoding, Query: r.URL.Query()}
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&write.Series); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if response.Status >= 200 && response.Status < 300 && !response.Lose {
+		db.mu.Lock()
+		db.writes = append(db.writes, write)
+		db.mu.Unlock()
+		select {
+		case db.notify <- struct{}{}:
+		default:
+		}
+	}
+
+	w.WriteHeader(response.Status)
+	io.WriteString(w, response.Body)
+}
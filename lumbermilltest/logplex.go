@@ -0,0 +1,78 @@
+package lumbermilltest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	lpxgen "github.com/apg/lpxgen"
+	"github.com/bmizerany/lpx"
+)
+
+// Timestamp format used by logplex
+const TimeFormat = "2006-01-02T15:04:05.000000+00:00"
+
+// Formats a syslog line the way logplex does, e.g.
+// SyslogLine("heroku", "router", "at=info ...")
+func SyslogLine(name, procid, msg string) string {
+	return SyslogLineAt(time.Now(), name, procid, msg)
+}
+
+// Like SyslogLine, at the given time
+func SyslogLineAt(t time.Time, name, procid, msg string) string {
+	return fmt.Sprintf("<158>1 %s host %s %s - %s", t.UTC().Format(TimeFormat), name, procid, msg)
+}
+
+// Frames a syslog line with its octet count
+func Frame(line string) string {
+	return strconv.Itoa(len(line)) + " " + line
+}
+
+// Frames the lines into a drain request body
+func Body(lines ...string) string {
+	frames := make([]string, len(lines))
+	for i, line := range lines {
+		frames[i] = Frame(line)
+	}
+	return strings.Join(frames, "")
+}
+
+// Builds a drain request for token carrying the lines, with the headers
+// logplex sends
+func NewDrainRequest(url, token string, lines ...string) *http.Request {
+	body := Body(lines...)
+	req, _ := http.NewRequest("POST", url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/logplex-1")
+	req.Header.Set("Logplex-Msg-Count", strconv.Itoa(len(lines)))
+	req.Header.Set("Logplex-Frame-Id", lpxgen.RandomFrameId())
+	if token != "" {
+		req.Header.Set("Logplex-Drain-Token", token)
+	}
+	return req
+}
+
+// Generates a random drain request with gen, fixing up the count headers
+// lpxgen formats incorrectly.
+func Generate(gen *lpxgen.LPXGenerator, url string) *http.Request {
+	req := gen.Generate(url)
+
+	var body bytes.Buffer
+	body.ReadFrom(req.Body)
+
+	count := 0
+	lp := lpx.NewReader(bufio.NewReader(bytes.NewReader(body.Bytes())))
+	for lp.Next() {
+		count++
+	}
+
+	fixed, _ := http.NewRequest("POST", url, bytes.NewReader(body.Bytes()))
+	for _, header := range []string{"Content-Type", "Logplex-Frame-Id", "Logplex-Drain-Token"} {
+		fixed.Header.Set(header, req.Header.Get(header))
+	}
+	fixed.Header.Set("Logplex-Msg-Count", strconv.Itoa(count))
+	return fixed
+}
@@ -0,0 +1,76 @@
+package lumbermilltest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	lpxgen "github.com/apg/lpxgen"
+	"github.com/bmizerany/lpx"
+)
+
+func TestNewDrainRequest(t *testing.T) {
+	lines := []string{
+		SyslogLine("heroku", "router", "at=info status=200"),
+		SyslogLine("app", "web.1", "hello"),
+	}
+	req := NewDrainRequest("http://localhost/drain", "t.test", lines...)
+
+	if req.Header.Get("Logplex-Msg-Count") != "2" || req.Header.Get("Logplex-Drain-Token") != "t.test" {
+		t.Errorf("Wrong headers: %v", req.Header)
+	}
+
+	var body bytes.Buffer
+	body.ReadFrom(req.Body)
+	lp := lpx.NewReader(bufio.NewReader(&body))
+	msgs := make([]string, 0)
+	for lp.Next() {
+		msgs = append(msgs, string(lp.Bytes()))
+	}
+	if len(msgs) != 2 || msgs[0] != "at=info status=200" || msgs[1] != "hello" {
+		t.Errorf("Wrong messages: %q", msgs)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	req := Generate(lpxgen.NewGenerator(5, 6, lpxgen.Router), "http://localhost/drain")
+	if req.Header.Get("Logplex-Msg-Count") != "5" {
+		t.Errorf("Expected a message count of 5, got %q", req.Header.Get("Logplex-Msg-Count"))
+	}
+}
+
+func TestFakeInfluxDB(t *testing.T) {
+	db := NewFakeInfluxDB()
+	defer db.Close()
+	db.Respond(Response{Status: http.StatusInternalServerError, Body: "oops"})
+
+	post := func() int {
+		payload := `[{"name": "router.t.test", "columns": ["time", "status"], "points": [[1, 200]]}]`
+		resp, err := http.Post(db.URL+"/db/test/series?u=u&p=p", "application/json", bytes.NewBufferString(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := post(); status != http.StatusInternalServerError {
+		t.Errorf("Expected scripted 500, got %d", status)
+	}
+	if status := post(); status != http.StatusOK {
+		t.Errorf("Expected 200, got %d", status)
+	}
+
+	if !db.WaitForPoints(1, time.Second) {
+		t.Fatal("Timed out waiting for points")
+	}
+	if n := db.PointCount(); n != 1 {
+		t.Errorf("Expected only the successful write to be recorded, got %d points", n)
+	}
+	if points := db.Points("router.t.test"); len(points) != 1 || points[0][1] != json.Number("200") {
+		t.Errorf("Wrong points: %v", points)
+	}
+}
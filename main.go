@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"io"
 	"log"
@@ -18,8 +19,6 @@ import (
 	librato "github.com/rcrowley/go-metrics/librato"
 )
 
-type ShutdownChan chan struct{}
-
 const (
 	PointChannelCapacity = 500000
 	HashRingReplication  = 46
@@ -28,18 +27,133 @@ const (
 
 var (
 	connectionCloser = make(chan struct{})
-	Debug            = os.Getenv("DEBUG") == "true"
+	Debug            = newToggle(os.Getenv("DEBUG") == "true")
+
+	// Runtime changes, and who made them
+	auditLog, _ = NewAuditLog("")
+
+	// Parse and route points as usual, but discard them instead of
+	// delivering them, to validate changes against production traffic.
+	DryRun = os.Getenv("DRY_RUN") == "true"
 
 	User     = os.Getenv("USER")
 	Password = os.Getenv("PASSWORD")
-)
 
-func (s ShutdownChan) Close() error {
-	s <- struct{}{}
-	return nil
-}
+	// More users, one per tenant, as "<user>=<password>,..."
+	DrainUsers = os.Getenv("DRAIN_USERS")
+
+	// Tokens each user may send lines for, as "<user>=<token>|<token>,...".
+	// Users without an entry may send any. Lines for other tokens are
+	// dropped, or the whole batch rejected when ALLOWED_TOKENS_POLICY is
+	// "reject".
+	UserAllowedTokens   = parseAllowedTokens(os.Getenv("USER_ALLOWED_TOKENS"))
+	AllowedTokensPolicy = os.Getenv("ALLOWED_TOKENS_POLICY")
+
+	// Credentials from a secrets provider, which take precedence over the
+	// environment and can be rotated at runtime
+	secrets = NewSecrets(nil)
+
+	// Destination channel sizes and drop policies, globally and per
+	// destination as "<host>=<value>,..."
+	DestinationChannelCapacity   = os.Getenv("DESTINATION_CHANNEL_CAPACITY")
+	DestinationChannelCapacities = parseKeyValueList(os.Getenv("DESTINATION_CHANNEL_CAPACITIES"))
+	DestinationDropPolicy        = os.Getenv("DESTINATION_DROP_POLICY")
+	DestinationDropPolicies      = parseKeyValueList(os.Getenv("DESTINATION_DROP_POLICIES"))
+
+	// What counts as a successful write to InfluxDB, globally and per host:
+	// "status" (a 2xx, the default), "body" (a 2xx without an error in the
+	// body) or "none" (any response)
+	InfluxDBAckLevel  = os.Getenv("INFLUXDB_ACK_LEVEL")
+	InfluxDBAckLevels = parseKeyValueList(os.Getenv("INFLUXDB_ACK_LEVELS"))
+
+	// How points are sent to each host: http (the default), udp (an
+	// InfluxDB UDP listener) or statsd, globally and per host
+	InfluxDBTransport  = os.Getenv("INFLUXDB_TRANSPORT")
+	InfluxDBTransports = parseKeyValueList(os.Getenv("INFLUXDB_TRANSPORTS"))
+
+	// Compression of InfluxDB payloads, globally and per host
+	InfluxDBCompression  = os.Getenv("INFLUXDB_COMPRESSION")
+	InfluxDBCompressions = parseKeyValueList(os.Getenv("INFLUXDB_COMPRESSIONS"))
+
+	// Capacity of the priority lane error events take to InfluxDB, ahead of
+	// bulk metrics. Off when 0.
+	PriorityLaneCapacity = parseIntSetting("PRIORITY_LANE_CAPACITY", os.Getenv("PRIORITY_LANE_CAPACITY"), 0)
+
+	// Further endpoints of each host, as "<host>=<host>|<host>,...", and how
+	// they're posted to: round_robin (the default) or race, globally and per
+	// host. Raced writes start on another endpoint after InfluxDBRaceDelay.
+	InfluxDBEndpoints     = parseKeyValueList(os.Getenv("INFLUXDB_ENDPOINTS"))
+	InfluxDBEndpointMode  = os.Getenv("INFLUXDB_ENDPOINT_MODE")
+	InfluxDBEndpointModes = parseKeyValueList(os.Getenv("INFLUXDB_ENDPOINT_MODES"))
+	InfluxDBRaceDelay     = parseDurationSetting("INFLUXDB_RACE_DELAY", os.Getenv("INFLUXDB_RACE_DELAY"), 250*time.Millisecond)
+
+	// Points and requests written to each host a second, globally and per
+	// host, so a backlog doesn't overload a recovering InfluxDB. Unlimited
+	// when unset.
+	InfluxDBMaxPointRate    = os.Getenv("INFLUXDB_MAX_POINT_RATE")
+	InfluxDBMaxPointRates   = parseKeyValueList(os.Getenv("INFLUXDB_MAX_POINT_RATES"))
+	InfluxDBMaxRequestRate  = os.Getenv("INFLUXDB_MAX_REQUEST_RATE")
+	InfluxDBMaxRequestRates = parseKeyValueList(os.Getenv("INFLUXDB_MAX_REQUEST_RATES"))
+
+	// Client certificates for mutual TLS with InfluxDB, globally and per
+	// host as "<host>=<path>,..."
+	InfluxDBClientCert  = os.Getenv("INFLUXDB_CLIENT_CERT")
+	InfluxDBClientCerts = parseKeyValueList(os.Getenv("INFLUXDB_CLIENT_CERTS"))
+	InfluxDBClientKey   = os.Getenv("INFLUXDB_CLIENT_KEY")
+	InfluxDBClientKeys  = parseKeyValueList(os.Getenv("INFLUXDB_CLIENT_KEYS"))
+
+	// Whether invalid UTF-8 and control characters are cleaned out of point
+	// values ("true", the default, or "false"), globally and per destination
+	SanitizeUTF8  = os.Getenv("SANITIZE_UTF8")
+	SanitizeUTF8s = parseKeyValueList(os.Getenv("SANITIZE_UTF8S"))
+
+	// Whether PII is scrubbed from raw messages and paths ("true" or
+	// "false", the default), globally and per destination
+	ScrubPII  = os.Getenv("SCRUB_PII")
+	ScrubPIIs = parseKeyValueList(os.Getenv("SCRUB_PIIS"))
+
+	// Write points as JSON to this file instead of delivering them
+	FileOutputPath = os.Getenv("FILE_OUTPUT_PATH")
+
+	// Pretty print points to stdout instead of delivering them
+	ConsoleOutput = os.Getenv("CONSOLE_OUTPUT") == "true"
+
+	// Candidate InfluxDB host that gets a copy of SHADOW_PERCENT% of the
+	// tokens' points, to compare delivery with before migrating.
+	ShadowInfluxDBHost = os.Getenv("SHADOW_INFLUXDB_HOST")
+	ShadowPercent      = os.Getenv("SHADOW_PERCENT")
+
+	// Memory, in MB, that queued points and request bodies may use before
+	// drain requests are shed. Unlimited when unset.
+	MemoryBudgetMB = os.Getenv("MEMORY_BUDGET_MB")
+)
 
 func createInfluxDBClient(host string, skipVerify bool) influx.ClientConfig {
+	dialTimeout := parseDurationSetting("INFLUXDB_DIAL_TIMEOUT", os.Getenv("INFLUXDB_DIAL_TIMEOUT"), 5*time.Second)
+
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: skipVerify,
+			ClientSessionCache: tls.NewLRUClientSessionCache(0),
+		},
+		ResponseHeaderTimeout: parseDurationSetting("INFLUXDB_RESPONSE_HEADER_TIMEOUT", os.Getenv("INFLUXDB_RESPONSE_HEADER_TIMEOUT"), 5*time.Second),
+		MaxIdleConnsPerHost:   parseIntSetting("INFLUXDB_MAX_IDLE_CONNS_PER_HOST", os.Getenv("INFLUXDB_MAX_IDLE_CONNS_PER_HOST"), PostersPerHost),
+		ForceAttemptHTTP2:     os.Getenv("INFLUXDB_HTTP2") == "true",
+		Dial: func(network, address string) (net.Conn, error) {
+			return net.DialTimeout(network, address, dialTimeout)
+		},
+	}
+
+	certFile := settingFor(InfluxDBClientCerts, host, InfluxDBClientCert)
+	if certFile != "" {
+		certs, err := newCertReloader(certFile, settingFor(InfluxDBClientKeys, host, InfluxDBClientKey))
+		if err != nil {
+			log.Fatalf("Unable to load client certificate for %s: %s\n", host, err)
+		}
+		go certs.Watch(time.Minute)
+		transport.TLSClientConfig.GetClientCertificate = certs.GetClientCertificate
+	}
+
 	return influx.ClientConfig{
 		Host:     host,                       //"influxor.ssl.edward.herokudev.com:8086",
 		Username: os.Getenv("INFLUXDB_USER"), //"test",
@@ -47,13 +161,8 @@ func createInfluxDBClient(host string, skipVerify bool) influx.ClientConfig {
 		Database: os.Getenv("INFLUXDB_NAME"), //"ingress",
 		IsSecure: true,
 		HttpClient: &http.Client{
-			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: skipVerify},
-				ResponseHeaderTimeout: 5 * time.Second,
-				Dial: func(network, address string) (net.Conn, error) {
-					return net.DialTimeout(network, address, 5*time.Second)
-				},
-			},
-			Timeout: 10 * time.Second,
+			Transport: transport,
+			Timeout:   parseDurationSetting("INFLUXDB_REQUEST_TIMEOUT", os.Getenv("INFLUXDB_REQUEST_TIMEOUT"), 10*time.Second),
 		},
 	}
 }
@@ -70,16 +179,78 @@ func createClients(hostlist string, skipVerify bool) []influx.ClientConfig {
 	return clients
 }
 
-// Creates destinations and attaches them to posters, which deliver to InfluxDB
-func createMessageRoutes(hostlist string, skipVerify bool) (*HashRing, []*Destination, *sync.WaitGroup) {
+// Creates a destination, sized and with the drop policy configured for it
+func createDestination(name string) *Destination {
+	chanCap := parseIntSetting("channel capacity for "+name,
+		settingFor(DestinationChannelCapacities, name, DestinationChannelCapacity),
+		PointChannelCapacity)
+	if chanCap < 0 {
+		log.Printf("Invalid channel capacity (%d) for %s, using %d\n", chanCap, name, PointChannelCapacity)
+		chanCap = PointChannelCapacity
+	}
+	destination := NewDestination(name, chanCap)
+
+	switch policy := DropPolicy(settingFor(DestinationDropPolicies, name, DestinationDropPolicy)); policy {
+	case DropNewest, DropOldest, Block:
+		destination.DropPolicy = policy
+	case "":
+	default:
+		log.Printf("Unknown drop policy (%q) for %s, using %s\n", policy, name, destination.DropPolicy)
+	}
+
+	destination.SanitizeUTF8 = settingFor(SanitizeUTF8s, name, SanitizeUTF8) != "false"
+	destination.ScrubPII = settingFor(ScrubPIIs, name, ScrubPII) == "true"
+	destination.Maintenance.Set(MaintenanceDestinations[name])
+
+	return destination
+}
+
+// Creates destinations and attaches them to posters, which deliver to
+// InfluxDB until ctx is done
+func createMessageRoutes(ctx context.Context, hostlist string, skipVerify bool) (*HashRing, []*Destination, *sync.WaitGroup) {
 	posterGroup := new(sync.WaitGroup)
 	hashRing := NewHashRing(HashRingReplication, nil)
+	hashRing.LoadFactor = parseFloatSetting("HASH_RING_LOAD_FACTOR", os.Getenv("HASH_RING_LOAD_FACTOR"), 0)
+	if ttl := parseDurationSetting("ROUTE_CACHE_TTL", os.Getenv("ROUTE_CACHE_TTL"), time.Minute); ttl > 0 {
+		hashRing.EnableCache(ttl)
+	}
 	destinations := make([]*Destination, 0)
 
 	influxClients := createClients(hostlist, skipVerify)
-	if len(influxClients) == 0 {
+	if FileOutputPath != "" {
+		// Local development, so write points to a file
+		destination := createDestination("file")
+		hashRing.Add(destination)
+		destinations = append(destinations, destination)
+		poster, err := NewFilePoster(
+			FileOutputPath,
+			int64(parseIntSetting("FILE_OUTPUT_MAX_MB", os.Getenv("FILE_OUTPUT_MAX_MB"), 100))<<20,
+			parseIntSetting("FILE_OUTPUT_BACKUPS", os.Getenv("FILE_OUTPUT_BACKUPS"), 3),
+			destination,
+			posterGroup,
+		)
+		if err != nil {
+			log.Fatalln("Unable to open file output: ", err)
+		}
+		go poster.Run()
+	} else if ConsoleOutput {
+		// Demos and debugging, so print points
+		destination := createDestination("console")
+		hashRing.Add(destination)
+		destinations = append(destinations, destination)
+		poster := NewConsolePoster(os.Stdout, os.Getenv("CONSOLE_OUTPUT_SERIES"), os.Getenv("NO_COLOR") == "", destination, posterGroup)
+		go poster.Run()
+	} else if BigQueryProject != "" && !DryRun {
+		// Analytics on GCP, so stream points into BigQuery
+		destination := createDestination("bigquery")
+		hashRing.Add(destination)
+		destinations = append(destinations, destination)
+		poster := NewBigQueryPoster(BigQueryProject, BigQueryDataset, destination, posterGroup)
+		poster.ctx = ctx
+		go poster.Run()
+	} else if len(influxClients) == 0 {
 		//No backends, so blackhole things
-		destination := NewDestination("null", PointChannelCapacity)
+		destination := createDestination("null")
 		hashRing.Add(destination)
 		destinations = append(destinations, destination)
 		poster := NewNullPoster(destination)
@@ -87,21 +258,108 @@ func createMessageRoutes(hostlist string, skipVerify bool) (*HashRing, []*Destin
 	} else {
 		for _, client := range influxClients {
 			name := client.Host
-			destination := NewDestination(name, PointChannelCapacity)
+			destination := createDestination(name)
 			hashRing.Add(destination)
 			destinations = append(destinations, destination)
+			if DryRun {
+				poster := NewNullPoster(destination)
+				go poster.Run()
+				continue
+			}
+			destination.EnablePriorityLane(PriorityLaneCapacity)
+			writer := createSeriesWriter(client, name, name)
 			for p := 0; p < PostersPerHost; p++ {
-				poster := NewPoster(client, name, destination, posterGroup)
+				poster := NewPoster(writer, name, destination, posterGroup)
+				poster.ctx = ctx
 				go poster.Run()
 			}
 		}
 	}
 
+	if ArchivePath != "" {
+		archive := createArchiveDestination(ArchivePath, posterGroup)
+		for _, destination := range destinations {
+			destination.Archive = archive
+		}
+		destinations = append(destinations, archive)
+	}
+
+	if ShadowInfluxDBHost != "" && !DryRun {
+		shadow := createShadowDestination(ctx, ShadowInfluxDBHost, skipVerify, posterGroup)
+		percent := parseIntSetting("SHADOW_PERCENT", ShadowPercent, 0)
+		for _, destination := range destinations {
+			if destination.candidate {
+				// The archive gets the ring destinations' points, which
+				// they shadow already
+				continue
+			}
+			destination.Shadow = shadow
+			destination.ShadowPercent = percent
+		}
+		destinations = append(destinations, shadow)
+	}
+
 	return hashRing, destinations, posterGroup
 }
 
+// Creates a destination for a candidate backend. It isn't part of the ring,
+// and its posters' metrics are named "shadow.<host>" for comparison.
+func createShadowDestination(ctx context.Context, host string, skipVerify bool, posterGroup *sync.WaitGroup) *Destination {
+	name := "shadow." + host
+	destination := createDestination(name)
+	destination.candidate = true
+	if destination.DropPolicy == Block {
+		// Trouble with the candidate mustn't hold up the drain
+		destination.DropPolicy = DropNewest
+	}
+	destination.EnablePriorityLane(PriorityLaneCapacity)
+	writer := createSeriesWriter(createInfluxDBClient(host, skipVerify), host, name)
+	for p := 0; p < PostersPerHost; p++ {
+		poster := NewPoster(writer, name, destination, posterGroup)
+		poster.ctx = ctx
+		go poster.Run()
+	}
+	return destination
+}
+
+// Creates the destination archiving the points of the others to dir. It
+// isn't part of the ring, and doesn't ack drain requests.
+func createArchiveDestination(dir string, posterGroup *sync.WaitGroup) *Destination {
+	destination := createDestination("archive")
+	destination.candidate = true
+	if destination.DropPolicy == Block {
+		// A slow disk mustn't hold up the drain
+		destination.DropPolicy = DropNewest
+	}
+	go NewArchivePoster(dir, ArchiveBatchSize, destination, posterGroup).Run()
+	return destination
+}
+
+// Creates the writer of destination name, for client's host, over the
+// transport configured for the host. Only HTTP writers have endpoints, rate
+// limits and ack levels, and are bootstrapped.
+func createSeriesWriter(client influx.ClientConfig, host, name string) SeriesWriter {
+	if transport := transportFor(settingFor(InfluxDBTransports, host, InfluxDBTransport), name); transport != TransportHTTP {
+		writer, err := transports[transport](client.Host, name)
+		if err != nil {
+			log.Fatalf("Unable to create %s writer for %s: %s\n", transport, name, err)
+		}
+		return writer
+	}
+	writer := newSeriesWriter(client, name, settingFor(InfluxDBCompressions, host, InfluxDBCompression))
+	writer.ack = ackLevel(settingFor(InfluxDBAckLevels, host, InfluxDBAckLevel), name)
+	writer.setEndpoints(name, InfluxDBEndpoints[host], endpointMode(settingFor(InfluxDBEndpointModes, host, InfluxDBEndpointMode), name))
+	writer.setRateLimits(name,
+		parseFloatSetting("point rate for "+name, settingFor(InfluxDBMaxPointRates, host, InfluxDBMaxPointRate), 0),
+		parseFloatSetting("request rate for "+name, settingFor(InfluxDBMaxRequestRates, host, InfluxDBMaxRequestRate), 0))
+	if InfluxDBBootstrap {
+		go bootstrapHost(writer)
+	}
+	return writer
+}
+
 func awaitSignals(ss ...io.Closer) {
-	sigCh := make(chan os.Signal)
+	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
 	sig := <-sigCh
 	log.Printf("Got signal: %q", sig)
@@ -110,16 +368,56 @@ func awaitSignals(ss ...io.Closer) {
 	}
 }
 
-func awaitShutdown(shutdownChan ShutdownChan, server *LumbermillServer, posterGroup *sync.WaitGroup) {
-	<-shutdownChan
-	log.Printf("waiting for inflight requests to finish.")
-	server.Wait()
-	posterGroup.Wait()
-	log.Printf("Shutdown complete.")
-}
-
 func main() {
-	hashRing, destinations, posterGroup := createMessageRoutes(os.Getenv("INFLUXDB_HOSTS"), os.Getenv("INFLUXDB_SKIP_VERIFY") == "true")
+	if len(os.Args) > 1 && os.Args[1] == "loadtest" {
+		os.Exit(runLoadtest(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		os.Exit(runExport(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:], os.Stdout))
+	}
+
+	if path := os.Getenv("AUDIT_LOG_PATH"); path != "" {
+		var err error
+		if auditLog, err = NewAuditLog(path); err != nil {
+			log.Fatalln("Unable to open audit log: ", err)
+		}
+	}
+
+	secrets = NewSecrets(secretsProviderFromEnv())
+	if err := secrets.Refresh(); err != nil {
+		log.Fatalln("Unable to load secrets: ", err)
+	}
+	go secrets.Watch(parseDurationSetting("SECRETS_REFRESH_INTERVAL", os.Getenv("SECRETS_REFRESH_INTERVAL"), time.Minute))
+
+	if len(TokenLineParsers) > 0 {
+		var err error
+		if tokenLineParsers, err = loadLineParsers(LineParserPlugins, LineParserPluginConfigs, TokenLineParsers); err != nil {
+			log.Fatalln("Unable to load parsing plugins: ", err)
+		}
+	}
+
+	if ScriptsPath != "" {
+		var err error
+		if seriesScripts, err = loadScripts(ScriptsPath); err != nil {
+			log.Fatalln("Unable to load scripts: ", err)
+		}
+	}
+
+	if ScrubPatternsPath != "" {
+		if err := loadScrubPatterns(ScrubPatternsPath); err != nil {
+			log.Fatalln("Unable to load scrub patterns: ", err)
+		}
+	}
+
+	if QuarantineEnabled || QuarantinePath != "" {
+		var err error
+		if quarantine, err = NewQuarantine(QuarantinePath, QuarantineMaxPoints); err != nil {
+			log.Fatalln("Unable to open quarantine: ", err)
+		}
+	}
 
 	if os.Getenv("LIBRATO_TOKEN") != "" {
 		go librato.Librato(
@@ -130,24 +428,31 @@ func main() {
 			os.Getenv("LIBRATO_SOURCE"),
 			[]float64{0.50, 0.95, 0.99},
 			time.Millisecond,
-			)
-	} else if os.Getenv("DEBUG") == "true" {
+		)
+	} else if Debug.On() {
 		go metrics.Log(metrics.DefaultRegistry, 20e9, log.New(os.Stderr, "metrics: ", log.Lmicroseconds))
 	}
 
-	shutdownChan := make(ShutdownChan)
-	server := NewLumbermillServer(&http.Server{Addr: ":" + os.Getenv("PORT")}, hashRing)
+	server, posterGroup, err := SetupLumbermill(&http.Server{Addr: ":" + os.Getenv("PORT")}, os.Getenv("INFLUXDB_HOSTS"), os.Getenv("INFLUXDB_SKIP_VERIFY") == "true")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if CanaryToken != "" {
+		canary = NewCanary(CanaryToken, server)
+		go canary.Run(CanaryInterval, CanaryRate)
+	}
+
+	go topTokens.Run(TopTokensInterval)
 
 	log.Printf("Starting up")
 	go server.Run(5 * time.Minute)
-
-	closers := make([]io.Closer, 0)
-	closers = append(closers, server)
-	closers = append(closers, shutdownChan)
-	for _, cls := range destinations {
-		closers = append(closers, cls)
+	if GRPCPort != "" {
+		if err := server.StartIngest(":" + GRPCPort); err != nil {
+			log.Fatalln("Unable to start gRPC server: ", err)
+		}
 	}
 
-	go awaitSignals(closers...)
-	awaitShutdown(shutdownChan, server, posterGroup)
+	go awaitSignals(server)
+	server.awaitShutdown(posterGroup)
+	log.Printf("Shutdown complete.")
 }
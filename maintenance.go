@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Answer every drain request with a 503, so logplex buffers the batches
+	Maintenance = newToggle(os.Getenv("MAINTENANCE") == "true")
+
+	// Destinations whose tokens' batches get a 503, as "<host>,..."
+	MaintenanceDestinations = parseSet(os.Getenv("MAINTENANCE_DESTINATIONS"))
+
+	maintenanceRejectedCounter = metrics.GetOrRegisterCounter("lumbermill.batches.maintenance.rejected", metrics.DefaultRegistry)
+)
+
+// Maintenance state, as served by the admin API
+type maintenanceState struct {
+	Enabled      bool     `json:"enabled"`
+	Destinations []string `json:"destinations"`
+}
+
+// GET /admin/maintenance, or POST /admin/maintenance?enabled=<bool> to put
+// everything into maintenance (add &destination=<name> for one destination)
+// or take it out.
+func (s *LumbermillServer) serveAdminMaintenance(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.adminActor(w, r, roleFor(r, Operator))
+	if !ok {
+		return
+	}
+
+	if r.Method == "POST" {
+		query := r.URL.Query()
+		enabled, err := strconv.ParseBool(query.Get("enabled"))
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "enabled must be true or false")
+			return
+		}
+
+		flag, action := Maintenance, "maintenance.set"
+		if name := query.Get("destination"); name != "" {
+			flag = nil
+			for _, d := range s.destinations {
+				if d.Name == name {
+					flag, action = d.Maintenance, "maintenance.set."+name
+				}
+			}
+			if flag == nil {
+				writeError(w, r, http.StatusNotFound, errNotFound, "Unknown destination")
+				return
+			}
+		}
+		before := flag.On()
+		flag.Set(enabled)
+		auditLog.Record(actor, action, before, enabled)
+	}
+
+	state := maintenanceState{Enabled: Maintenance.On(), Destinations: make([]string, 0)}
+	for _, d := range s.destinations {
+		if d.Maintenance.On() {
+			state.Destinations = append(state.Destinations, d.Name)
+		}
+	}
+	writeJSON(w, state)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestMaintenance(t *testing.T) {
+	defer Maintenance.Set(false)
+	User, Password = "foo", "foo"
+	a, b := NewDestination("maintenance-a", 10), NewDestination("maintenance-b", 10)
+	hashRing := NewHashRing(HashRingReplication, nil)
+	hashRing.Add(a, b)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.destinations = []*Destination{a, b}
+
+	// A token routed to each destination
+	tokens := make(map[*Destination]string)
+	for i := 0; len(tokens) < 2; i++ {
+		token := fmt.Sprintf("t.maintenance%d", i)
+		if _, found := tokens[hashRing.Get(token)]; !found {
+			tokens[hashRing.Get(token)] = token
+		}
+	}
+
+	admin := func(query string) int {
+		req, _ := http.NewRequest("POST", "/admin/maintenance?"+query, nil)
+		req.SetBasicAuth("foo", "foo")
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	drain := func(token string) int {
+		req := lumbermilltest.NewDrainRequest("/drain", token, lumbermilltest.SyslogLine("heroku", "router", router))
+		recorder := httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+		return recorder.Code
+	}
+
+	if code := admin("enabled=true&destination=maintenance-a"); code != http.StatusOK {
+		t.Fatalf("Entering maintenance failed: %d", code)
+	}
+	if code := drain(tokens[a]); code != http.StatusServiceUnavailable || len(a.points) != 0 {
+		t.Errorf("Expected a batch for a destination in maintenance to get a 503, got %d", code)
+	}
+	if code := drain(tokens[b]); code != http.StatusNoContent || len(b.points) != 1 {
+		t.Errorf("Expected other destinations to flow, got %d", code)
+	}
+
+	admin("enabled=false&destination=maintenance-a")
+	admin("enabled=true")
+	if code := drain(tokens[a]); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected every batch to get a 503 in global maintenance, got %d", code)
+	}
+	admin("enabled=false")
+	if code := drain(tokens[a]); code != http.StatusNoContent || len(a.points) != 1 {
+		t.Errorf("Expected batches to flow after maintenance, got %d", code)
+	}
+
+	if code := admin("enabled=true&destination=unknown"); code != http.StatusNotFound {
+		t.Errorf("Expected an unknown destination to be refused, got %d", code)
+	}
+}
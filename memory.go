@@ -0,0 +1,97 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+const (
+	// Rough in memory size of a queued Point, including its values
+	estimatedPointSize = 256
+
+	// Assumed size of a drain request without a Content-Length
+	unknownBodySize = 64 * 1024
+)
+
+var (
+	memoryShedCounter = metrics.GetOrRegisterCounter("lumbermill.errors.memory.shed", metrics.DefaultRegistry)
+	memoryUsedGauge   = metrics.GetOrRegisterGauge("lumbermill.memory.budget.used", metrics.DefaultRegistry)
+)
+
+// Bounds the memory used by request bodies being parsed and points queued
+// for delivery, so we shed load instead of getting OOM killed.
+type MemoryBudget struct {
+	bodies       int64 // Bytes of request bodies being parsed
+	limit        int64
+	destinations []*Destination
+}
+
+func NewMemoryBudget(limit int64, destinations []*Destination) *MemoryBudget {
+	budget := &MemoryBudget{limit: limit, destinations: destinations}
+	go budget.Sample(10 * time.Second)
+	return budget
+}
+
+// Update the used gauge every so often
+func (m *MemoryBudget) Sample(every time.Duration) {
+	for {
+		time.Sleep(every)
+		memoryUsedGauge.Update(m.Used())
+	}
+}
+
+// Estimated bytes in use by bodies and queued points
+func (m *MemoryBudget) Used() int64 {
+	return atomic.LoadInt64(&m.bodies) + m.queued()
+}
+
+// Estimated bytes of points queued for delivery
+func (m *MemoryBudget) queued() int64 {
+	queued := 0
+	for _, d := range m.destinations {
+		queued += d.depth()
+	}
+	return int64(queued) * estimatedPointSize
+}
+
+// Whether a body of n bytes is larger than the whole budget, so it could
+// never be reserved. A nil budget is unlimited.
+func (m *MemoryBudget) TooLarge(n int64) bool {
+	return m != nil && n > m.limit
+}
+
+// Reserves n bytes for a request body, returning false if that would exceed
+// the budget. A nil budget is unlimited.
+func (m *MemoryBudget) Reserve(n int64) bool {
+	if m == nil {
+		return true
+	}
+	if n < 0 {
+		n = unknownBodySize
+	}
+	queued := m.queued()
+	for {
+		bodies := atomic.LoadInt64(&m.bodies)
+		if bodies+queued+n > m.limit {
+			memoryShedCounter.Inc(1)
+			return false
+		}
+		// Concurrent reservations can't both take the last of the budget
+		if atomic.CompareAndSwapInt64(&m.bodies, bodies, bodies+n) {
+			return true
+		}
+	}
+}
+
+// Releases a reservation made with Reserve
+func (m *MemoryBudget) Release(n int64) {
+	if m == nil {
+		return
+	}
+	if n < 0 {
+		n = unknownBodySize
+	}
+	atomic.AddInt64(&m.bodies, -n)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Samples older than this aren't attached to dyno errors
+const memorySampleMaxAge = 5 * time.Minute
+
+// A dyno's latest log-runtime-metrics memory sample
+type memorySample struct {
+	timestamp int64 // In microseconds
+	total     float64
+	quota     float64 // 0 when the sample didn't report it
+}
+
+// The latest memory sample of each dyno, so dyno errors (e.g. R14s) can be
+// recorded along with the memory use that led to them.
+type MemorySamples struct {
+	sync.Mutex
+	samples map[string]memorySample
+}
+
+func NewMemorySamples() *MemorySamples {
+	return &MemorySamples{samples: make(map[string]memorySample)}
+}
+
+// Records a sample of token's dyno. A nil MemorySamples records nothing.
+func (m *MemorySamples) Record(token, dyno string, timestamp int64, total, quota float64) {
+	if m == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	key := token + "\x00" + dyno
+	if timestamp >= m.samples[key].timestamp {
+		m.samples[key] = memorySample{timestamp, total, quota}
+	}
+}
+
+// The memory total of token's dyno at timestamp, and the percentage of its
+// quota it was, as point values. They're nil when there's no sample from
+// before timestamp, or it's too old.
+func (m *MemorySamples) At(token, dyno string, timestamp int64) (interface{}, interface{}) {
+	if m == nil {
+		return nil, nil
+	}
+	m.Lock()
+	sample, found := m.samples[token+"\x00"+dyno]
+	m.Unlock()
+	if !found || sample.timestamp > timestamp || timestamp-sample.timestamp > int64(memorySampleMaxAge/time.Microsecond) {
+		return nil, nil
+	}
+	if sample.quota == 0 {
+		return sample.total, nil
+	}
+	return sample.total, 100 * sample.total / sample.quota
+}
+
+// Forgets the samples too old to be attached, e.g. of dynos that are gone
+func (m *MemorySamples) Expire(now time.Time) {
+	cutoff := now.Add(-memorySampleMaxAge).UnixNano() / int64(time.Microsecond)
+	m.Lock()
+	defer m.Unlock()
+	for key, sample := range m.samples {
+		if sample.timestamp < cutoff {
+			delete(m.samples, key)
+		}
+	}
+}
+
+// Expires samples every so often
+func (m *MemorySamples) Run(every time.Duration) {
+	for {
+		time.Sleep(every)
+		m.Expire(time.Now())
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	destination := NewDestination("memory-test", 10)
+	budget := &MemoryBudget{limit: 4 * estimatedPointSize, destinations: []*Destination{destination}}
+
+	if !budget.Reserve(2 * estimatedPointSize) {
+		t.Fatal("Expected reservation within budget to succeed")
+	}
+
+	destination.PostPoint(pointAt(1))
+	destination.PostPoint(pointAt(2))
+
+	if used := budget.Used(); used != 4*estimatedPointSize {
+		t.Errorf("Expected bodies and queued points to be accounted for, got %d", used)
+	}
+
+	shedBefore := memoryShedCounter.Count()
+	if budget.Reserve(1) {
+		t.Error("Expected reservation over budget to fail")
+	}
+	if memoryShedCounter.Count()-shedBefore != 1 {
+		t.Error("Expected shed request to be counted")
+	}
+
+	budget.Release(2 * estimatedPointSize)
+	<-destination.points
+
+	if !budget.Reserve(3 * estimatedPointSize) {
+		t.Error("Expected reservation to succeed once memory was released")
+	}
+
+	var unlimited *MemoryBudget
+	if !unlimited.Reserve(1<<40) || unlimited.TooLarge(1<<40) {
+		t.Error("Expected nil budget to be unlimited")
+	}
+}
+
+func TestMemoryBudgetConcurrentReservations(t *testing.T) {
+	budget := &MemoryBudget{limit: 100}
+	var reserved int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if budget.Reserve(10) {
+				atomic.AddInt64(&reserved, 10)
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 100 || budget.Used() != 100 {
+		t.Errorf("Expected reservations to fill the budget exactly, got %d and %d used", reserved, budget.Used())
+	}
+}
+
+func TestMemoryBudgetTooLarge(t *testing.T) {
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	server.memoryBudget = &MemoryBudget{limit: 10}
+
+	req := lumbermilltest.NewDrainRequest("/drain", "t.test", lumbermilltest.SyslogLine("app", "web.1", "hi"))
+	recorder := httptest.NewRecorder()
+	server.serveDrain(recorder, req)
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected a body larger than the budget to get a 413, got %d", recorder.Code)
+	}
+	assertErrorCode(t, recorder, errTooLarge)
+	if server.memoryBudget.TooLarge(-1) {
+		t.Errorf("Expected bodies of unknown length not to be too large")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		pending  int64
+		rate     float64
+		expected time.Duration
+	}{
+		{0, 0, RetryAfterMin},
+		{1000, 100, 10 * time.Second},
+		{10, 100, RetryAfterMin},
+		{1000000, 100, RetryAfterMax},
+		{1000, 0, RetryAfterMax}, // Nothing is getting delivered
+	} {
+		if after := retryAfter(tc.pending, tc.rate); after != tc.expected {
+			t.Errorf("%d pending at %.0f/s: Expected %s, got %s", tc.pending, tc.rate, tc.expected, after)
+		}
+	}
+}
+
+func TestDestinationDrainRate(t *testing.T) {
+	destination := NewDestination("drain-rate-test", 10)
+	for i := 0; i < 4; i++ {
+		destination.PostPoint(pointAt(int64(i)))
+	}
+	<-destination.points
+
+	destination.sample(10 * time.Millisecond)
+	if rate := destination.DrainRate(); rate != 100 {
+		t.Errorf("Expected 1 point per 10ms, got %.1f/s", rate)
+	}
+}
+
+func TestMemoryShedRetryAfter(t *testing.T) {
+	destination := NewDestination("retry-after-test", 10)
+	destination.PostPoint(pointAt(1))
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	server.destinations = []*Destination{destination}
+	// Room for the body, but not with the point queued
+	server.memoryBudget = &MemoryBudget{limit: estimatedPointSize, destinations: server.destinations}
+
+	recorder := httptest.NewRecorder()
+	server.serveDrain(recorder, lumbermilltest.NewDrainRequest("/drain", "t.test", lumbermilltest.SyslogLine("app", "web.1", "hi")))
+	if recorder.Code != http.StatusServiceUnavailable || recorder.Header().Get("Retry-After") != strconv.Itoa(int(RetryAfterMax.Seconds())) {
+		t.Errorf("Expected a 503 with Retry-After, got %d and %q", recorder.Code, recorder.Header().Get("Retry-After"))
+	}
+	assertErrorCode(t, recorder, errOverCapacity)
+}
+
+func TestMemorySamples(t *testing.T) {
+	samples := NewMemorySamples()
+	start := time.Unix(1500000000, 0)
+	at := func(d time.Duration) int64 { return start.Add(d).UnixNano() / int64(time.Microsecond) }
+
+	samples.Record("t.a", "web.1", at(0), 600, 512)
+	samples.Record("t.a", "web.1", at(-time.Minute), 100, 512) // Late, so ignored
+	samples.Record("t.a", "web.2", at(0), 256, 0)
+
+	if total, pct := samples.At("t.a", "web.1", at(5*time.Second)); total != 600.0 || pct != 100*600.0/512 {
+		t.Errorf("Expected the latest sample of web.1, got %v and %v", total, pct)
+	}
+	if total, pct := samples.At("t.a", "web.2", at(5*time.Second)); total != 256.0 || pct != nil {
+		t.Errorf("Expected no percentage without a quota, got %v and %v", total, pct)
+	}
+	for _, tc := range []struct {
+		token, dyno string
+		at          time.Duration
+	}{
+		{"t.b", "web.1", 5 * time.Second},    // Another app
+		{"t.a", "web.1", -5 * time.Second},   // Before the sample
+		{"t.a", "web.1", 10 * time.Minute},   // Long after it
+		{"t.a", "worker.1", 5 * time.Second}, // Another dyno
+	} {
+		if total, pct := samples.At(tc.token, tc.dyno, at(tc.at)); total != nil || pct != nil {
+			t.Errorf("%s %s at %s: Expected no sample, got %v", tc.token, tc.dyno, tc.at, total)
+		}
+	}
+
+	samples.Expire(start.Add(10 * time.Minute))
+	if len(samples.samples) != 0 {
+		t.Errorf("Expected old samples to be forgotten")
+	}
+}
@@ -1,18 +1,27 @@
 package main
 
+import (
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// Discards points, counting them
 type NullPoster struct {
-	destination *Destination
-	name        string
+	destination   *Destination
+	name          string
+	pointsCounter metrics.Counter
 }
 
 func NewNullPoster(destination *Destination) *NullPoster {
 	return &NullPoster{
-		destination: destination,
-		name:        "null",
+		destination:   destination,
+		name:          destination.Name,
+		pointsCounter: metrics.GetOrRegisterCounter("lumbermill.poster.null.points."+destination.Name, metrics.DefaultRegistry),
 	}
 }
 
 func (p *NullPoster) Run() {
-	for _ = range p.destination.points {
+	for point := range p.destination.points {
+		p.pointsCounter.Inc(1)
+		p.destination.ack(point.RequestId, 1)
 	}
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	// Leave one-off (heroku run) and scheduler dynos out of the autoscale
+	// signals and aggregates, so cron jobs don't skew dyno counts
+	OneOffExcludeAggregates = os.Getenv("ONEOFF_EXCLUDE_AGGREGATES") == "true"
+
+	oneOffStartingSentinel = []byte("Starting process with command")
+	oneOffStateSentinel    = []byte("State changed from ")
+	oneOffExitSentinel     = []byte("Process exited with status ")
+)
+
+// Whether a procid is a one-off dyno's, e.g. "run.1234" or "scheduler.5678"
+func isOneOffDyno(what string) bool {
+	return strings.HasPrefix(what, "run.") || strings.HasPrefix(what, "scheduler.")
+}
+
+// The state a one-off dyno's lifecycle or error line reports, with its exit
+// status or error code, if any. Commands aren't kept, as they may hold
+// secrets. Reports false for other lines, e.g. runtime metrics.
+func parseOneOffLine(msg []byte) (state string, exitStatus, errorCode interface{}, ok bool) {
+	switch {
+	case bytes.HasPrefix(msg, oneOffStartingSentinel):
+		return "starting", nil, nil, true
+	case bytes.HasPrefix(msg, oneOffStateSentinel):
+		rest := msg[len(oneOffStateSentinel):]
+		if i := bytes.Index(rest, []byte(" to ")); i != -1 {
+			return string(bytes.TrimSpace(rest[i+len(" to "):])), nil, nil, true
+		}
+	case bytes.HasPrefix(msg, oneOffExitSentinel):
+		status, err := strconv.Atoi(string(bytes.TrimSpace(msg[len(oneOffExitSentinel):])))
+		if err == nil {
+			return "exited", status, nil, true
+		}
+	case bytes.HasPrefix(msg, dynoKillSentinel):
+		return "killed", nil, killedDynoError.String(), true
+	case bytes.HasPrefix(msg, dynoErrorSentinel):
+		if de, err := parseBytesToDynoError(msg); err == nil {
+			return "error", nil, de.String(), true
+		}
+	}
+	return "", nil, nil, false
+}
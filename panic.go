@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+
+	"github.com/bmizerany/lpx"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// How much of the line being parsed is logged when parsing panics
+	PanicSampleBytes = parseIntSetting("PANIC_SAMPLE_BYTES", os.Getenv("PANIC_SAMPLE_BYTES"), 1024)
+
+	parsePanicCounter = metrics.GetOrRegisterCounter("lumbermill.errors.parse.panic", metrics.DefaultRegistry)
+)
+
+// Like parseLines, but a panic is turned into an error (and logged along with
+// a sample of the offending line) so a bad batch doesn't take the whole
+// process down with it.
+func parseLinesSafely(lp *lpx.Reader, drain drainContext, batch *pointBatch, counts *lineCounts) (lines int, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			parsePanicCounter.Inc(1)
+			log.Printf("request_id=%s at=panic panic=%q sample=%q stack=%q\n", drain.requestId, fmt.Sprint(p), panicSample(lp, PanicSampleBytes), debug.Stack())
+			err = fmt.Errorf("panic parsing batch: %v", p)
+		}
+	}()
+
+	return parserFor(drain.token)(lp, drain, batch, counts), nil
+}
+
+// The frame lp was on, truncated to max bytes
+func panicSample(lp *lpx.Reader, max int) []byte {
+	h := lp.Header()
+	sample := []byte(fmt.Sprintf("%s %s %s %s %s %s ", h.PrivalVersion, h.Time, h.Hostname, h.Name, h.Procid, h.Msgid))
+	sample = append(sample, lp.Bytes()...)
+	if len(sample) > max {
+		sample = sample[:max]
+	}
+	return sample
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lumbermilltest"
+	"github.com/kr/logfmt"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata/corpus")
+
+const (
+	corpusDir   = "testdata/corpus"
+	corpusToken = "t.corpus"
+)
+
+// A parsed point as recorded in the golden files
+type goldenPoint struct {
+	Series string        `json:"series"`
+	Values []interface{} `json:"values"`
+}
+
+// Parses the lines as a single drain body for corpusToken
+func parseCorpusLines(lines []string) []Point {
+	return parseCorpusLinesCounting(lines, &lineCounts{})
+}
+
+func parseCorpusLinesCounting(lines []string, counts *lineCounts) []Point {
+	return parseCorpusLinesLocating(lines, counts, nil)
+}
+
+// Parses the lines like parseCorpusLinesCounting, locating router clients
+// with geoip
+func parseCorpusLinesLocating(lines []string, counts *lineCounts, geoip *GeoIP) []Point {
+	body := lumbermilltest.Body(lines...)
+	lp := lpx.NewReader(bufio.NewReader(strings.NewReader(body)))
+	batch := new(pointBatch)
+	parseLines(lp, drainContext{token: corpusToken, requestId: "corpus", allowOverrides: true, memory: NewMemorySamples(), restarts: NewDynoRestarts(), geoip: geoip}, batch, counts)
+	return batch.points
+}
+
+// How the lines of a corpus file were counted, as recorded in the golden
+// files, so files of lines that make no points still assert something
+type goldenCounts struct {
+	Counts map[string]int64 `json:"counts"`
+}
+
+// The counters of counts that aren't 0, by field name
+func nonZeroCounts(counts *lineCounts) map[string]int64 {
+	nonZero := make(map[string]int64)
+	v := reflect.ValueOf(*counts)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		switch f := v.Field(i); f.Kind() {
+		case reflect.Int64:
+			if f.Int() != 0 {
+				nonZero[name] = f.Int()
+			}
+		case reflect.Bool:
+			if f.Bool() {
+				nonZero[name] = 1
+			}
+		case reflect.Array:
+			for st := 0; st < f.Len(); st++ {
+				if n := f.Index(st).Int(); n != 0 {
+					nonZero[name+"."+SeriesType(st).Name()] = n
+				}
+			}
+		}
+	}
+	return nonZero
+}
+
+// One JSON object per point and line, so diffs are readable, followed by
+// the line counts
+func goldenJSON(points []Point, counts *lineCounts) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	for _, point := range points {
+		if err := enc.Encode(goldenPoint{point.SeriesName(), point.Points}); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Encode(goldenCounts{nonZeroCounts(counts)}); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Each testdata/corpus/<name>.log is parsed and compared with the points and
+// line counts in <name>.golden. Run with -update to regenerate them after intended changes.
+func TestParseCorpus(t *testing.T) {
+	corpus, err := lumbermilltest.LoadCorpus(corpusDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corpus) == 0 {
+		t.Fatal("Empty corpus")
+	}
+
+	for name, lines := range corpus {
+		counts := &lineCounts{}
+		actual, err := goldenJSON(parseCorpusLinesCounting(lines, counts), counts)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		goldenFile := filepath.Join(corpusDir, name+".golden")
+		if *updateGolden {
+			if err := ioutil.WriteFile(goldenFile, actual, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(goldenFile)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("%s: points differ from %s\nexpected:\n%s\nactual:\n%s", name, goldenFile, expected, actual)
+		}
+	}
+}
+
+// The router corpus with GeoIP enabled is compared with router.geoip.golden,
+// so the fwd of router lines is known to reach the database
+func TestParseCorpusGeoIP(t *testing.T) {
+	dir := writeGeoIPBlocks(t, "203.0.113.0/24,2921044,2921044,,0,0\n"+
+		"198.51.100.0/24,6252001,6252001,,0,0\n")
+	defer os.RemoveAll(dir)
+	geoip, err := LoadGeoIP(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corpus, err := lumbermilltest.LoadCorpus(corpusDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := &lineCounts{}
+	points := parseCorpusLinesLocating(corpus["router"], counts, geoip)
+	actual, err := goldenJSON(points, counts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goldenFile := filepath.Join(corpusDir, "router.geoip.golden")
+	if *updateGolden {
+		if err := ioutil.WriteFile(goldenFile, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("router: points differ from %s\nexpected:\n%s\nactual:\n%s", goldenFile, expected, actual)
+	}
+
+	located := 0
+	for _, point := range points {
+		if point.Type == Router && point.Points[8] != nil {
+			located++
+		}
+	}
+	if located == 0 {
+		t.Errorf("Expected router clients to be located")
+	}
+}
+
+func TestParseLongLines(t *testing.T) {
+	defer func(max int, policy string) { MaxLineBytes, MaxLinePolicy = max, policy }(MaxLineBytes, MaxLinePolicy)
+	MaxLineBytes = 20
+	line := lumbermilltest.SyslogLine("heroku", "web.1", "Error R14 (Memory quota exceeded)")
+
+	MaxLinePolicy = ""
+	points := parseCorpusLines([]string{line})
+	if len(points) != 1 {
+		t.Fatalf("Expected 1 point, got %d", len(points))
+	}
+	if msg := points[0].Points[4]; msg != "Error R14 (Memory qu" {
+		t.Errorf("Message wasn't truncated: %q", msg)
+	}
+	if truncated := points[0].Points[6]; truncated != true {
+		t.Errorf("Point wasn't marked as truncated")
+	}
+
+	MaxLinePolicy = "drop"
+	if points := parseCorpusLines([]string{line}); len(points) != 0 {
+		t.Errorf("Expected the line to be dropped, got %d points", len(points))
+	}
+}
+
+func TestParseFingerprintsDynoMessages(t *testing.T) {
+	defer func(modes map[string]string) { DynoMessageModes = modes }(DynoMessageModes)
+	msg := "Error R14 (Memory quota exceeded) while processing the signup of jo@example.com"
+	line := lumbermilltest.SyslogLine("heroku", "web.1", msg)
+
+	DynoMessageModes = map[string]string{corpusToken: "fingerprint"}
+	points := parseCorpusLines([]string{line})
+	if len(points) != 1 {
+		t.Fatalf("Expected 1 point, got %d", len(points))
+	}
+	fingerprint := points[0].Points[4].(string)
+	if fingerprint != msg[:64]+" sha256:"+fingerprint[len(fingerprint)-16:] || strings.Contains(fingerprint, "jo@example.com") {
+		t.Errorf("Expected the first 64 characters and a hash, got %q", fingerprint)
+	}
+	if points[0].Points[1] != "web.1" || points[0].Points[3] != 14 {
+		t.Errorf("Expected the dyno and code to be kept, got %v", points[0].Points)
+	}
+	if other := fingerprintMessage([]byte(msg + ".")); other == fingerprint {
+		t.Errorf("Expected different messages to hash differently")
+	}
+
+	DynoMessageModes = nil
+	if points := parseCorpusLines([]string{line}); points[0].Points[4] != msg {
+		t.Errorf("Expected the raw message by default, got %q", points[0].Points[4])
+	}
+}
+
+// A batch of router lines from a single token, the common case
+func BenchmarkParseSingleTokenBatch(b *testing.B) {
+	lines := make([]string, benchmarkBatchSize)
+	for i := range lines {
+		lines[i] = lumbermilltest.SyslogLine("t.bench", "router", `at=info method=GET path="/" host=bench.herokuapp.com request_id=a fwd="1.2.3.4" dyno=web.1 connect=1ms service=20ms status=200 bytes=300`)
+	}
+	body := lumbermilltest.Body(lines...)
+	hashRing := NewHashRing(HashRingReplication, nil)
+	hashRing.Add(NewDestination("a", 1), NewDestination("b", 1))
+	batch := new(pointBatch)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lp := lpx.NewReader(bufio.NewReader(strings.NewReader(body)))
+		parseLines(lp, drainContext{requestId: "bench", allowOverrides: true}, batch, &lineCounts{})
+		batch.Flush(context.Background(), hashRing)
+	}
+}
+
+func TestParseTimestampLayoutSwitches(t *testing.T) {
+	layout := 0
+	for _, ts := range []string{"2015-03-05T18:21:34.118254+00:00", "2015-03-05T18:21:34+00:00", "2015-03-05T18:21:34.118254+00:00"} {
+		if _, err := parseTimestampLayout([]byte(ts), &layout); err != nil {
+			t.Errorf("Unable to parse %s: %s", ts, err)
+		}
+	}
+	if got, _ := parseTimestampLayout([]byte("2015-03-05T18:21:34+00:00"), &layout); got != 1425579694000000 {
+		t.Errorf("Unexpected timestamp: %d", got)
+	}
+}
+
+func TestParseTokenOverrides(t *testing.T) {
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	counts := lineCounts{}
+	points := parseCorpusLinesCounting([]string{
+		lumbermilltest.SyslogLine("heroku", "router", router),
+		lumbermilltest.SyslogLine("t.other", "router", router),
+		lumbermilltest.SyslogLine("heroku", "router", router),
+		lumbermilltest.SyslogLine("t.another", "router", router),
+	}, &counts)
+
+	expected := []string{corpusToken, "t.other", corpusToken, "t.another"}
+	if len(points) != len(expected) {
+		t.Fatalf("Expected %d points, got %d", len(expected), len(points))
+	}
+	for i, point := range points {
+		if point.Token != expected[i] {
+			t.Errorf("Point %d was attributed to %s, not %s", i, point.Token, expected[i])
+		}
+	}
+	if counts.tokenOverride != 2 || !counts.multiToken {
+		t.Errorf("Expected 2 overrides in a multi token batch, got %d (multi token: %t)", counts.tokenOverride, counts.multiToken)
+	}
+
+	counts = lineCounts{}
+	parseCorpusLinesCounting([]string{lumbermilltest.SyslogLine("heroku", "router", router), lumbermilltest.SyslogLine("heroku", "router", router)}, &counts)
+	if counts.tokenOverride != 0 || counts.multiToken {
+		t.Errorf("Single token batch counted as overridden")
+	}
+}
+
+func TestRouterDurationUnits(t *testing.T) {
+	defer func(unit string) { RouterDurationUnit = unit }(RouterDurationUnit)
+
+	d, err := parseRouterDuration([]byte("1.2s"))
+	if err != nil || d != 1200*time.Millisecond {
+		t.Fatalf("Unexpected duration: %s, %v", d, err)
+	}
+	for unit, want := range map[string]interface{}{"ms": 1200, "us": 1200000, "s": 1.2} {
+		RouterDurationUnit = unit
+		v := routerDurationValue(d)
+		if v != want {
+			t.Errorf("Expected %v in %s, got %v", want, unit, v)
+		}
+		if micros, ok := routerDurationMicros(v); !ok || micros != 1200000 {
+			t.Errorf("Expected %v %s to be 1200000us, got %d", v, unit, micros)
+		}
+	}
+}
+
+func TestMemoryUnits(t *testing.T) {
+	defer func(unit string) { MemoryUnit = unit }(MemoryUnit)
+
+	for val, want := range map[string]float64{"512.32MB": 512.32, "1.5GB": 1536, "512kB": 0.5, "1048576B": 1, "64": 64} {
+		if mb, err := parseMemory([]byte(val)); err != nil || mb != want {
+			t.Errorf("Expected %s to be %gMB, got %g, %v", val, want, mb, err)
+		}
+	}
+	if _, err := parseMemory([]byte("lots")); err == nil {
+		t.Errorf("Expected an error for a malformed sample")
+	}
+
+	MemoryUnit = "bytes"
+	if v := memoryValue(1.5); v != 1572864 {
+		t.Errorf("Expected 1.5MB to be written as 1572864 bytes, got %g", v)
+	}
+}
+
+func TestRouterKeyVariants(t *testing.T) {
+	for _, line := range []string{
+		`request_id=abc tls_version=TLSv1.3 protocol=https status=200`,
+		`request-id=abc tls-version=TLSv1.3 protocol=https status=200`,
+	} {
+		rm := routerMsg{}
+		if err := parseRouterMsg([]byte(line), &rm); err != nil {
+			t.Fatal(err)
+		}
+		if rm.RequestId != "abc" || rm.TLS != "TLSv1.3" || rm.Protocol != "https" || rm.Status != 200 {
+			t.Errorf("Unexpected router message from %q: %+v", line, rm)
+		}
+	}
+}
+
+var routerScanLines = []string{
+	`at=info method=GET path="/" host=a.herokuapp.com request_id=a fwd="1.2.3.4" dyno=web.1 connect=1ms service=20ms status=200 bytes=300`,
+	`at=info method=POST path=/check?metric=a:sum&0 host=a.com request_id=b fwd="1.2.3.4" dyno=web.14 connect=1ms service=849ms status=500 bytes=306 protocol=https tls_version=TLSv1.3`,
+	`at=info method=GET path="/a \"b\"" host=a.com dyno=web.1 connect=1 service=2 status=200 bytes=3`,
+	`at=info method=GET path=/a=b host=a.com dyno=web.1 connect=1.5s service=200us status=200 bytes=3`,
+	`at=error code=H12 desc="Request timeout" method=GET path="/" host=a.com request_id=c fwd="1.2.3.4" dyno=web.1 connect=5ms service=30000ms status=503 bytes=0`,
+	`at=error code=H10 desc="App crashed" method=GET path="/" host=a.com dyno= connect= service= status=503 bytes=`,
+	`at=info path="/unterminated status=200`,
+	`sock=client at=warning code=H27 desc="Client Request Interrupted" method=GET path=/ dyno=web.2 connect=0ms service= status=499 bytes=`,
+}
+
+func TestRouterScanMatchesLogfmt(t *testing.T) {
+	for _, line := range routerScanLines {
+		var scanned, parsed routerMsg
+		scanErr := parseRouterMsg([]byte(line), &scanned)
+		parseErr := logfmt.Unmarshal([]byte(line), &parsed)
+		if scanned != parsed || (scanErr == nil) != (parseErr == nil) {
+			t.Errorf("%q: scanned %+v (%v), logfmt parsed %+v (%v)", line, scanned, scanErr, parsed, parseErr)
+		}
+
+		var scannedError, parsedError routerError
+		scanErr = parseRouterError([]byte(line), &scannedError)
+		parseErr = logfmt.Unmarshal([]byte(line), &parsedError)
+		if scanRouterPairs([]byte(line), new(routerError).scan) {
+			// Only the fields points and lookups use are scanned
+			parsedError.At, parsedError.Desc, parsedError.Sock = "", "", ""
+		}
+		if scannedError != parsedError || (scanErr == nil) != (parseErr == nil) {
+			t.Errorf("%q: scanned %+v (%v), logfmt parsed %+v (%v)", line, scannedError, scanErr, parsedError, parseErr)
+		}
+	}
+}
+
+func BenchmarkRouterMsgLogfmt(b *testing.B) {
+	msg := []byte(routerScanLines[0])
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rm := routerMsg{}
+		logfmt.Unmarshal(msg, &rm)
+	}
+}
+
+func BenchmarkRouterMsgScan(b *testing.B) {
+	msg := []byte(routerScanLines[0])
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rm := routerMsg{}
+		parseRouterMsg(msg, &rm)
+	}
+}
+
+func TestSampledRequestId(t *testing.T) {
+	defer func(rate float64, errors bool) {
+		RequestIdSampleRate, RequestIdErrors = rate, errors
+	}(RequestIdSampleRate, RequestIdErrors)
+
+	RequestIdSampleRate, RequestIdErrors = 0, false
+	if sampledRequestId("abc", true) != nil {
+		t.Errorf("Expected no request ids by default")
+	}
+
+	RequestIdSampleRate, RequestIdErrors = 0.25, true
+	sampled := 0
+	for i := 0; i < 4000; i++ {
+		id := fmt.Sprintf("%08x-request", i)
+		if v := sampledRequestId(id, false); v != nil {
+			sampled++
+			if sampledRequestId(id, false) != v {
+				t.Fatalf("Expected %s to be sampled consistently", id)
+			}
+		}
+		if sampledRequestId(id, true) != id {
+			t.Fatalf("Expected errors to always have their request id")
+		}
+	}
+	if sampled < 800 || sampled > 1200 {
+		t.Errorf("Expected about a quarter of request ids to be sampled, got %d of 4000", sampled)
+	}
+	if sampledRequestId("", true) != nil {
+		t.Errorf("Expected lines without a request id to have none")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"reflect"
+
+	"github.com/bmizerany/lpx"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// A parse tree: parses the lines of a drain body into batch, returning how
+// many lines there were
+type parseFunc func(lp *lpx.Reader, drain drainContext, batch *pointBatch, counts *lineCounts) int
+
+var (
+	// Parse tree implementations, by version. A new one is added here, and
+	// run beside the current one as PARSER_CANDIDATE until it's trusted.
+	parserVersions = map[string]parseFunc{
+		"v1": parseLines,
+	}
+
+	// The version drains are parsed with, and a candidate. The candidate
+	// parses the batches of the tokens the "parser.<candidate>" feature flag
+	// is on for, and PARSER_DIFF_PERCENT percent of all batches are parsed
+	// with both to compare their points. Unknown versions are logged at
+	// startup and left out.
+	ParserVersion     = parserVersionSetting("PARSER_VERSION", os.Getenv("PARSER_VERSION"), "v1")
+	ParserCandidate   = parserVersionSetting("PARSER_CANDIDATE", os.Getenv("PARSER_CANDIDATE"), "")
+	ParserDiffPercent = parseFloatSetting("PARSER_DIFF_PERCENT", os.Getenv("PARSER_DIFF_PERCENT"), 0)
+
+	parserDiffBatchesCounter    = metrics.GetOrRegisterCounter("lumbermill.parser.diff.batches", metrics.DefaultRegistry)
+	parserDiffMismatchesCounter = metrics.GetOrRegisterCounter("lumbermill.parser.diff.mismatches", metrics.DefaultRegistry)
+	parserDiffPointsCounter     = metrics.GetOrRegisterCounter("lumbermill.parser.diff.points", metrics.DefaultRegistry)
+	parserDiffPanicsCounter     = metrics.GetOrRegisterCounter("lumbermill.parser.diff.panics", metrics.DefaultRegistry)
+)
+
+// Parses a parser version setting, using def when it's empty or unknown
+func parserVersionSetting(name, value, def string) string {
+	if value == "" {
+		return def
+	}
+	if _, ok := parserVersions[value]; !ok {
+		log.Printf("Unknown %s (%q), using %q\n", name, value, def)
+		return def
+	}
+	return value
+}
+
+// The parse tree of version, v1 unless it's known. The settings were
+// checked at startup, so unknown versions aren't logged again here.
+func parserNamed(version string) (string, parseFunc) {
+	if parse, ok := parserVersions[version]; ok {
+		return version, parse
+	}
+	return "v1", parserVersions["v1"]
+}
+
+// The parse tree the batches of token are parsed with
+func parserFor(token string) parseFunc {
+	if ParserCandidate != "" && Flags.Enabled("parser."+ParserCandidate, token) {
+		_, parse := parserNamed(ParserCandidate)
+		return parse
+	}
+	_, parse := parserNamed(ParserVersion)
+	return parse
+}
+
+// Whether to compare the parsers on a batch
+func sampleParserDiff() bool {
+	return ParserCandidate != "" && ParserDiffPercent > 0 && rand.Float64()*100 < ParserDiffPercent
+}
+
+// Parses body with the current parser and the candidate, counting batches
+// where their points differ in lumbermill.parser.diff.mismatches and the
+// points in lumbermill.parser.diff.points. Both parse without the state
+// drains share (coalescing, memory samples, restarts), so only the
+// parsers differ.
+func diffParsers(body []byte, drain drainContext) {
+	isolated := drainContext{token: drain.token, requestId: drain.requestId, allowOverrides: drain.allowOverrides, allowedTokens: drain.allowedTokens}
+	currentName, current := parserNamed(ParserVersion)
+	candidateName, candidate := parserNamed(ParserCandidate)
+	if currentName == candidateName {
+		return
+	}
+
+	parserDiffBatchesCounter.Inc(1)
+	a, aErr := parseIsolated(current, body, isolated)
+	b, bErr := parseIsolated(candidate, body, isolated)
+	if aErr != nil || bErr != nil {
+		parserDiffPanicsCounter.Inc(1)
+		parserDiffMismatchesCounter.Inc(1)
+		log.Printf("request_id=%s at=parser_diff %s_error=%q %s_error=%q\n", drain.requestId, currentName, aErr, candidateName, bErr)
+		return
+	}
+
+	diffs := diffPoints(a, b)
+	if len(diffs) == 0 {
+		return
+	}
+	parserDiffMismatchesCounter.Inc(1)
+	for _, d := range diffs {
+		parserDiffPointsCounter.Inc(int64(d.points))
+		log.Printf("request_id=%s at=parser_diff series=%s %s=%d %s=%d mismatched=%d\n", drain.requestId, d.series, currentName, d.a, candidateName, d.b, d.points)
+	}
+}
+
+// Parses body into a batch of its own, recovering from panics
+func parseIsolated(parse parseFunc, body []byte, drain drainContext) (points []Point, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	batch := new(pointBatch)
+	lp := lpx.NewReader(newFrameLimitReader(bufio.NewReader(bytes.NewReader(body)), MaxFrameBytes))
+	parse(lp, drain, batch, &lineCounts{})
+	return batch.points, nil
+}
+
+// How two parsers' points of a series differ
+type seriesDiff struct {
+	series string
+	a, b   int // Points of the series
+	points int // Points only one parser has, or whose values differ
+}
+
+// The series whose points differ between a and b, compared in order
+func diffPoints(a, b []Point) []seriesDiff {
+	bySeries := func(points []Point) (map[string][]Point, []string) {
+		m := make(map[string][]Point)
+		var names []string
+		for _, p := range points {
+			name := p.SeriesName()
+			if _, ok := m[name]; !ok {
+				names = append(names, name)
+			}
+			m[name] = append(m[name], p)
+		}
+		return m, names
+	}
+	as, names := bySeries(a)
+	bs, bNames := bySeries(b)
+	for _, name := range bNames {
+		if _, ok := as[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	var diffs []seriesDiff
+	for _, name := range names {
+		d := seriesDiff{series: name, a: len(as[name]), b: len(bs[name])}
+		for i := 0; i < d.a || i < d.b; i++ {
+			if i >= d.a || i >= d.b || !reflect.DeepEqual(as[name][i].Points, bs[name][i].Points) {
+				d.points++
+			}
+		}
+		if d.points > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+// A candidate that loses the last point of every batch
+func lossyParser(lp *lpx.Reader, drain drainContext, batch *pointBatch, counts *lineCounts) int {
+	lines := parseLines(lp, drain, batch, counts)
+	if len(batch.points) > 0 {
+		batch.points = batch.points[:len(batch.points)-1]
+	}
+	return lines
+}
+
+func TestDiffParsers(t *testing.T) {
+	defer func(version, candidate string) { ParserVersion, ParserCandidate = version, candidate }(ParserVersion, ParserCandidate)
+	parserVersions["v2-test"] = lossyParser
+	defer delete(parserVersions, "v2-test")
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	body := []byte(lumbermilltest.Body(
+		lumbermilltest.SyslogLine("heroku", "router", router),
+		lumbermilltest.SyslogLine("heroku", "router", router)))
+	drain := drainContext{token: "t.diff", requestId: "req-diff"}
+
+	batches, mismatches, points := parserDiffBatchesCounter.Count(), parserDiffMismatchesCounter.Count(), parserDiffPointsCounter.Count()
+	ParserVersion, ParserCandidate = "v1", "v1"
+	diffParsers(body, drain)
+	if parserDiffBatchesCounter.Count() != batches {
+		t.Errorf("Expected a parser not to be compared with itself")
+	}
+
+	ParserCandidate = "v2-test"
+	diffParsers(body, drain)
+	if parserDiffBatchesCounter.Count() != batches+1 || parserDiffMismatchesCounter.Count() != mismatches+1 || parserDiffPointsCounter.Count() != points+1 {
+		t.Errorf("Expected the lost point to be a mismatch")
+	}
+}
+
+func TestDiffPoints(t *testing.T) {
+	a := []Point{
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(2), 200, 10}},
+		{Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(2), "H12"}},
+	}
+	b := []Point{
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(2), 503, 10}},
+		{Token: "t.a", Type: DynoMem, Points: []interface{}{int64(2), "web.1"}},
+	}
+
+	diffs := diffPoints(a, b)
+	expected := []seriesDiff{
+		{series: "router.t.a", a: 2, b: 2, points: 1},
+		{series: "events.router.t.a", a: 1, b: 0, points: 1},
+		{series: "dyno.mem.t.a", a: 0, b: 1, points: 1},
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, diffs)
+	}
+	for i := range expected {
+		if diffs[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], diffs[i])
+		}
+	}
+	if diffs := diffPoints(a, a); len(diffs) != 0 {
+		t.Errorf("Expected no differences, got %v", diffs)
+	}
+}
+
+func TestParserForFlaggedTokens(t *testing.T) {
+	defer func(candidate string, flags *FeatureFlags) { ParserCandidate, Flags = candidate, flags }(ParserCandidate, Flags)
+	parserVersions["v2-test"] = lossyParser
+	defer delete(parserVersions, "v2-test")
+	ParserCandidate = "v2-test"
+
+	Flags = newFeatureFlags(nil)
+	batch := new(pointBatch)
+	body := lumbermilltest.Body(lumbermilltest.SyslogLine("heroku", "router", "at=info status=200 service=1ms connect=1ms dyno=web.1"))
+	parse := func() int {
+		batch.points = nil
+		parserFor("t.a")(lpx.NewReader(newFrameLimitReader(bufio.NewReader(strings.NewReader(body)), MaxFrameBytes)), drainContext{token: "t.a"}, batch, &lineCounts{})
+		return len(batch.points)
+	}
+	if parse() != 1 {
+		t.Errorf("Expected the current parser without the flag")
+	}
+	Flags.Set("parser.v2-test", 100)
+	if parse() != 0 {
+		t.Errorf("Expected the candidate with the flag on")
+	}
+}
+
+func TestParserVersionSetting(t *testing.T) {
+	if version := parserVersionSetting("PARSER_VERSION", "v9", "v1"); version != "v1" {
+		t.Errorf("Expected an unknown version to fall back to v1, got %q", version)
+	}
+	if candidate := parserVersionSetting("PARSER_CANDIDATE", "v9", ""); candidate != "" {
+		t.Errorf("Expected an unknown candidate to be left unset, got %q", candidate)
+	}
+	if version := parserVersionSetting("PARSER_VERSION", "v1", ""); version != "v1" {
+		t.Errorf("Expected a known version to be kept, got %q", version)
+	}
+}
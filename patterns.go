@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"hash/fnv"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Tokens whose own (non heroku) log lines are fingerprinted, as
+	// "<token>,...". Lines are normalized into patterns, and per batch
+	// counts of each pattern are posted to the log.patterns series, without
+	// the text itself. Off when unset.
+	LogPatternTokens = parseSet(os.Getenv("LOG_PATTERN_TOKENS"))
+
+	// Most patterns remembered per token, so a token logging unbounded
+	// variety can't grow them forever. Later new patterns are counted as
+	// overflow, without being flagged as new.
+	LogPatternMax = parseIntSetting("LOG_PATTERN_MAX", os.Getenv("LOG_PATTERN_MAX"), 1000)
+
+	userPatternLinesCounter = metrics.GetOrRegisterCounter("lumbermill.lines.user.pattern", metrics.DefaultRegistry)
+	newPatternsCounter      = metrics.GetOrRegisterCounter("lumbermill.patterns.new", metrics.DefaultRegistry)
+	patternOverflowCounter  = metrics.GetOrRegisterCounter("lumbermill.patterns.overflow", metrics.DefaultRegistry)
+)
+
+// The patterns seen per token, to flag new ones
+type SeenPatterns struct {
+	sync.Mutex
+	tokens map[string]bool
+	max    int
+	seen   map[string]map[string]bool // Pattern ids per token
+}
+
+func NewSeenPatterns(tokens map[string]bool, max int) *SeenPatterns {
+	return &SeenPatterns{tokens: tokens, max: max, seen: make(map[string]map[string]bool)}
+}
+
+// Whether token's lines are fingerprinted. A nil SeenPatterns fingerprints
+// nothing.
+func (p *SeenPatterns) Enabled(token string) bool {
+	return p != nil && p.tokens[token]
+}
+
+// Records pattern for token, reporting whether it's the first time it was
+// seen
+func (p *SeenPatterns) firstSeen(token, pattern string) bool {
+	p.Lock()
+	defer p.Unlock()
+	seen := p.seen[token]
+	if seen == nil {
+		seen = make(map[string]bool)
+		p.seen[token] = seen
+	}
+	if seen[pattern] {
+		return false
+	}
+	if len(seen) >= p.max {
+		patternOverflowCounter.Inc(1)
+		return false
+	}
+	seen[pattern] = true
+	newPatternsCounter.Inc(1)
+	return true
+}
+
+// Normalizes msg into a template, replacing the variable parts of it (any
+// word with a digit in it, and values of key=value pairs) with "<*>"
+func logTemplate(msg []byte) []byte {
+	var template []byte
+	for i, word := range bytes.Fields(msg) {
+		if i > 0 {
+			template = append(template, ' ')
+		}
+		if eq := bytes.IndexByte(word, '='); eq > 0 {
+			template = append(append(template, word[:eq+1]...), "<*>"...)
+		} else if bytes.IndexAny(word, "0123456789@/") >= 0 {
+			template = append(template, "<*>"...)
+		} else {
+			template = append(template, word...)
+		}
+	}
+	return template
+}
+
+// The id of msg's pattern
+func logPattern(msg []byte) (string, []byte) {
+	template := logTemplate(msg)
+	h := fnv.New64a()
+	h.Write(template)
+	return strconv.FormatUint(h.Sum64(), 16), template
+}
+
+type patternKey struct {
+	token, pattern, dynoType string
+}
+
+// Pattern counts of a batch, in the order they were first seen
+type patternCounts struct {
+	index  map[patternKey]int
+	keys   []patternKey
+	times  []int64
+	counts []int
+}
+
+// Counts a line of token's
+func (c *patternCounts) add(token, procid string, timestamp int64, msg []byte) {
+	if c.index == nil {
+		c.index = make(map[patternKey]int)
+	}
+	pattern, template := logPattern(msg)
+	key := patternKey{token, pattern, dynoType(procid)}
+	i, found := c.index[key]
+	if !found {
+		i = len(c.keys)
+		c.index[key] = i
+		c.keys = append(c.keys, key)
+		c.times = append(c.times, timestamp)
+		c.counts = append(c.counts, 0)
+		if Debug.On() {
+			log.Printf("at=log_pattern token=%s pattern=%s template=%q\n", token, pattern, template)
+		}
+	}
+	c.counts[i]++
+}
+
+// Posts a point per pattern counted, flagging those patterns hasn't seen
+// before
+func (c *patternCounts) post(patterns *SeenPatterns, reqId string, batch *pointBatch) {
+	for i, key := range c.keys {
+		isNew := patterns.firstSeen(key.token, key.pattern)
+		batch.PostPoint(Point{Token: key.token, Type: LogPatterns, Points: []interface{}{c.times[i], key.pattern, c.counts[i], key.dynoType, isNew}, RequestId: reqId})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestLogTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		in, out string
+	}{
+		{"Started GET \"/users/42\" for 10.0.0.1", `Started GET <*> for <*>`},
+		{"Completed 200 OK in 12ms", "Completed <*> OK in <*>"},
+		{"at=info user=jo@example.com   state=active", "at=<*> user=<*> state=<*>"},
+		{"Connection refused", "Connection refused"},
+	} {
+		if out := string(logTemplate([]byte(tc.in))); out != tc.out {
+			t.Errorf("logTemplate(%q) = %q; want %q", tc.in, out, tc.out)
+		}
+	}
+}
+
+func parsePatternLines(patterns *SeenPatterns, lines ...string) []Point {
+	lp := lpx.NewReader(bufio.NewReader(strings.NewReader(lumbermilltest.Body(lines...))))
+	batch := new(pointBatch)
+	parseLines(lp, drainContext{token: "t.patterns", requestId: "patterns", patterns: patterns}, batch, &lineCounts{})
+	return batch.points
+}
+
+func TestParseCountsLogPatterns(t *testing.T) {
+	patterns := NewSeenPatterns(map[string]bool{"t.patterns": true}, 2)
+	lines := []string{
+		lumbermilltest.SyslogLine("app", "web.1", "Completed 200 OK in 12ms"),
+		lumbermilltest.SyslogLine("app", "web.2", "Completed 500 Internal Server Error in 3ms"),
+		lumbermilltest.SyslogLine("app", "web.1", "Completed 204 OK in 1ms"),
+	}
+
+	points := parsePatternLines(patterns, lines...)
+	if len(points) != 2 {
+		t.Fatalf("Expected a point per pattern, got %v", points)
+	}
+	for _, point := range points {
+		if point.Type != LogPatterns || point.Points[4] != true {
+			t.Errorf("Expected new log.patterns points, got %v", point)
+		}
+		for _, v := range point.Points {
+			if s, ok := v.(string); ok && strings.Contains(s, "Completed") {
+				t.Errorf("Expected no raw text, got %v", point.Points)
+			}
+		}
+	}
+	if points[0].Points[2] != 2 || points[0].Points[3] != "web" || points[1].Points[2] != 1 {
+		t.Errorf("Expected 2 and 1 lines counted, got %v and %v", points[0].Points, points[1].Points)
+	}
+
+	points = parsePatternLines(patterns, append(lines, lumbermilltest.SyslogLine("app", "worker.1", "Job failed"))...)
+	if len(points) != 3 || points[0].Points[4] != false || points[2].Points[4] != false {
+		t.Errorf("Expected no new patterns, with the third over the max, got %v", points)
+	}
+
+	if points := parsePatternLines(nil, lines...); len(points) != 0 {
+		t.Errorf("Expected user lines to be ignored without patterns, got %v", points)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"sync"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// What happens to the points of a paused token
+type PauseMode string
+
+const (
+	PauseDiscard PauseMode = "discard" // Acked, but dropped
+	PauseReject  PauseMode = "reject"  // The batch gets a 503, so logplex holds on to it
+)
+
+var (
+	// Mode of tokens paused without giving one
+	DefaultPauseMode = PauseMode(os.Getenv("PAUSE_MODE"))
+
+	pausedDiscardedCounter = metrics.GetOrRegisterCounter("lumbermill.points.paused.discarded", metrics.DefaultRegistry)
+	pausedRejectedCounter  = metrics.GetOrRegisterCounter("lumbermill.batches.paused.rejected", metrics.DefaultRegistry)
+)
+
+// Tokens whose ingestion is paused, e.g. while their backend is maintained
+type PausedTokens struct {
+	sync.RWMutex
+	tokens map[string]PauseMode
+}
+
+func NewPausedTokens() *PausedTokens {
+	return &PausedTokens{tokens: make(map[string]PauseMode)}
+}
+
+func (p *PausedTokens) Pause(token string, mode PauseMode) {
+	p.Lock()
+	defer p.Unlock()
+	p.tokens[token] = mode
+}
+
+// Resumes token, returning whether it was paused
+func (p *PausedTokens) Resume(token string) bool {
+	p.Lock()
+	defer p.Unlock()
+	_, found := p.tokens[token]
+	delete(p.tokens, token)
+	return found
+}
+
+// The paused tokens and their modes
+func (p *PausedTokens) List() map[string]PauseMode {
+	p.RLock()
+	defer p.RUnlock()
+	list := make(map[string]PauseMode, len(p.tokens))
+	for token, mode := range p.tokens {
+		list[token] = mode
+	}
+	return list
+}
+
+// Drops the points of tokens paused in discard mode from batch, returning
+// whether it has points of a token paused in reject mode instead.
+func (p *PausedTokens) Filter(batch *pointBatch) bool {
+	p.RLock()
+	defer p.RUnlock()
+	if len(p.tokens) == 0 {
+		return false
+	}
+
+	kept := batch.points[:0]
+	for _, point := range batch.points {
+		switch p.tokens[point.Token] {
+		case PauseReject:
+			return true
+		case PauseDiscard:
+			pausedDiscardedCounter.Inc(1)
+		default:
+			kept = append(kept, point)
+		}
+	}
+	batch.points = kept
+	return false
+}
+
+// GET /admin/paused, or POST /admin/paused?action=pause|resume&token=<token>
+// (and optionally &mode=discard|reject) to pause or resume a token.
+func (s *LumbermillServer) serveAdminPaused(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.adminActor(w, r, roleFor(r, Operator))
+	if !ok {
+		return
+	}
+
+	if r.Method == "POST" {
+		query := r.URL.Query()
+		token := query.Get("token")
+		if token == "" {
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "token is required")
+			return
+		}
+		switch query.Get("action") {
+		case "pause":
+			mode := PauseMode(query.Get("mode"))
+			if mode == "" {
+				mode = DefaultPauseMode
+			}
+			if mode == "" {
+				mode = PauseDiscard
+			}
+			if mode != PauseDiscard && mode != PauseReject {
+				writeError(w, r, http.StatusBadRequest, errBadRequest, "mode must be discard or reject")
+				return
+			}
+			s.paused.Pause(token, mode)
+			auditLog.Record(actor, "token.pause", token, mode)
+		case "resume":
+			if s.paused.Resume(token) {
+				auditLog.Record(actor, "token.resume", token, nil)
+			}
+		default:
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "action must be pause or resume")
+			return
+		}
+	}
+
+	writeJSON(w, s.paused.List())
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestPausedTokens(t *testing.T) {
+	User, Password = "foo", "foo"
+	destination := NewDestination("pause-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+
+	admin := func(query string) int {
+		req, _ := http.NewRequest("POST", "/admin/paused?"+query, nil)
+		req.SetBasicAuth("foo", "foo")
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	drain := func() *httptest.ResponseRecorder {
+		req := lumbermilltest.NewDrainRequest("/drain", "t.drain",
+			lumbermilltest.SyslogLine("heroku", "router", router),
+			lumbermilltest.SyslogLine("t.other", "router", router),
+		)
+		recorder := httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+		return recorder
+	}
+	tokens := func() []string {
+		tokens := make([]string, 0)
+		for len(destination.points) > 0 {
+			tokens = append(tokens, (<-destination.points).Token)
+		}
+		return tokens
+	}
+
+	if code := admin("action=pause&token=t.drain&mode=discard"); code != http.StatusOK {
+		t.Fatalf("Pausing failed: %d", code)
+	}
+	if recorder := drain(); recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected a paused token's points to be acked, got %d", recorder.Code)
+	}
+	if got := tokens(); len(got) != 1 || got[0] != "t.other" {
+		t.Errorf("Expected only t.other's point to be delivered, got %v", got)
+	}
+
+	admin("action=pause&token=t.drain&mode=reject")
+	recorder := drain()
+	if recorder.Code != http.StatusServiceUnavailable || len(tokens()) != 0 {
+		t.Errorf("Expected the batch to be refused, got %d", recorder.Code)
+	}
+	assertErrorCode(t, recorder, errPaused)
+
+	admin("action=resume&token=t.drain")
+	if recorder := drain(); recorder.Code != http.StatusNoContent || len(tokens()) != 2 {
+		t.Errorf("Expected a resumed token's points to be delivered, got %d", recorder.Code)
+	}
+
+	if code := admin("action=pause&token=t.drain&mode=later"); code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown mode to be refused, got %d", code)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"plugin"
+
+	"github.com/heroku/lumbermill/lineparser"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Parsing plugins as "<name>=<path to .so>,...", their configs as
+	// "<name>=<config>,..." and the tokens using them as
+	// "<token>=<name>,...". Only Go plugins are supported.
+	LineParserPlugins       = parseKeyValueList(os.Getenv("LINE_PARSER_PLUGINS"))
+	LineParserPluginConfigs = parseKeyValueList(os.Getenv("LINE_PARSER_PLUGIN_CONFIGS"))
+	TokenLineParsers        = parseKeyValueList(os.Getenv("TOKEN_LINE_PARSERS"))
+
+	// The parser of each token configured to use one
+	tokenLineParsers map[string]lineparser.LineParser
+
+	pluginLinesCounter        = metrics.GetOrRegisterCounter("lumbermill.lines.plugin", metrics.DefaultRegistry)
+	pluginInvalidPointCounter = metrics.GetOrRegisterCounter("lumbermill.errors.plugin.point", metrics.DefaultRegistry)
+)
+
+// Series by name, for plugins' points
+var seriesTypesByName = func() map[string]SeriesType {
+	types := make(map[string]SeriesType, numSeries)
+	for st := SeriesType(0); st < numSeries; st++ {
+		types[st.Name()] = st
+	}
+	return types
+}()
+
+// Opens a Go plugin and creates its parser
+func openLineParser(path, config string) (lineparser.LineParser, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := p.Lookup("New")
+	if err != nil {
+		return nil, err
+	}
+	newParser, ok := symbol.(func(string) (lineparser.LineParser, error))
+	if !ok {
+		return nil, fmt.Errorf("%s: New is a %T, not a func(string) (lineparser.LineParser, error)", path, symbol)
+	}
+	return newParser(config)
+}
+
+// Loads the configured plugins, returning the parser of each token using
+// one
+func loadLineParsers(plugins, configs, tokens map[string]string) (map[string]lineparser.LineParser, error) {
+	parsers := make(map[string]lineparser.LineParser, len(plugins))
+	for name, path := range plugins {
+		parser, err := openLineParser(path, configs[name])
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %s", name, err)
+		}
+		parsers[name] = parser
+		log.Printf("at=plugin_loaded name=%s path=%s\n", name, path)
+	}
+
+	byToken := make(map[string]lineparser.LineParser, len(tokens))
+	for token, name := range tokens {
+		parser, found := parsers[name]
+		if !found {
+			return nil, fmt.Errorf("token %s uses unknown plugin %s", token, name)
+		}
+		byToken[token] = parser
+	}
+	return byToken, nil
+}
+
+// Adds the points of a line a plugin handled to batch. Values are converted
+// to their columns' types, like COLUMN_TYPES would, and points of unknown
+// series, with too many values or values that can't be converted are
+// dropped. Columns added since the plugin was written are left null.
+func postPluginPoints(token, reqId string, timestamp int64, points []lineparser.Point, series seriesFilter, batch *pointBatch, counts *lineCounts) {
+	for _, p := range points {
+		values, ok := pluginPointValues(p, timestamp)
+		if !ok {
+			pluginInvalidPointCounter.Inc(1)
+			if Debug.On() {
+				log.Printf("request_id=%s Invalid plugin point for %s: %q %v\n", reqId, token, p.Series, p.Values)
+			}
+			continue
+		}
+		// Plugins parse before the series are known, so their points are
+		// filtered afterwards
+		if st := seriesTypesByName[p.Series]; series.skips(st) {
+			counts.skipped[st]++
+			continue
+		}
+		batch.PostPoint(Point{Token: token, Type: seriesTypesByName[p.Series], Points: values, RequestId: reqId})
+	}
+}
+
+// The values of a plugin's point, with its timestamp and in its columns'
+// types, and whether it's valid
+func pluginPointValues(p lineparser.Point, timestamp int64) ([]interface{}, bool) {
+	st, found := seriesTypesByName[p.Series]
+	if !found || len(p.Values) > len(st.Columns())-1 {
+		return nil, false
+	}
+	values := make([]interface{}, len(st.Columns()))
+	values[0] = timestamp
+	for i, v := range p.Values {
+		if v == nil {
+			continue
+		}
+		coerced, ok := coerceValue(v, st.columnType(i+1))
+		if !ok {
+			return nil, false
+		}
+		values[i+1] = coerced
+	}
+	return values, true
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/heroku/lumbermill/lineparser"
+)
+
+// Parses "status=<code> ms=<service>" lines of a custom proxy as router points
+type proxyParser struct{}
+
+func (proxyParser) ParseLine(line lineparser.Line) ([]lineparser.Point, bool) {
+	if line.Procid != "proxy" {
+		return nil, false
+	}
+	var status, service int
+	for _, field := range bytes.Fields(line.Msg) {
+		kv := strings.SplitN(string(field), "=", 2)
+		switch kv[0] {
+		case "status":
+			status = parseIntSetting("status", kv[1], 0)
+		case "ms":
+			service = parseIntSetting("ms", kv[1], 0)
+		}
+	}
+	return []lineparser.Point{
+		{Series: "router", Values: []interface{}{status, service, 0, "proxy", nil, nil, nil}},
+		{Series: "no.such.series", Values: []interface{}{status}},
+	}, true
+}
+
+func TestLineParserPlugins(t *testing.T) {
+	defer func(parsers map[string]lineparser.LineParser) { tokenLineParsers = parsers }(tokenLineParsers)
+	tokenLineParsers = map[string]lineparser.LineParser{corpusToken: proxyParser{}}
+
+	invalid := pluginInvalidPointCounter.Count()
+	points := parseCorpusLines([]string{
+		"<134>1 2015-03-05T18:21:34.000000+00:00 host app proxy - status=502 ms=40",
+		"<158>1 2015-03-05T18:21:35.000000+00:00 host heroku router - at=info method=GET path=/ dyno=web.1 connect=1ms service=2ms status=200 bytes=1",
+	})
+
+	if len(points) != 2 {
+		t.Fatalf("Expected the plugin's router point and a built-in one, got %v", points)
+	}
+	if want := []interface{}{int64(1425579694000000), 502, 40, 0, "proxy", nil, nil, nil, nil, nil}; !reflect.DeepEqual(points[0].Points, want) {
+		t.Errorf("Unexpected plugin point: %#v", points[0].Points)
+	}
+	if points[1].Points[1] != 200 {
+		t.Errorf("Expected lines the plugin doesn't handle to be parsed as usual, got %v", points[1].Points)
+	}
+	if pluginInvalidPointCounter.Count()-invalid != 1 {
+		t.Errorf("Expected the point of an unknown series to be dropped")
+	}
+}
+
+func TestPluginPointValues(t *testing.T) {
+	cases := []struct {
+		point lineparser.Point
+		want  []interface{}
+	}{
+		{lineparser.Point{Series: "router", Values: []interface{}{int64(502), 40.0, "3", "web"}}, []interface{}{int64(1), 502, 40, 3, "web", nil, nil, nil, nil, nil}},
+		{lineparser.Point{Series: "events.dyno", Values: []interface{}{"web.1", "R", 14, "Error R14", "web", 0}}, []interface{}{int64(1), "web.1", "R", 14, "Error R14", "web", false, nil, nil, nil}},
+		{lineparser.Point{Series: "router", Values: []interface{}{"Internal Server Error"}}, nil},
+		{lineparser.Point{Series: "dyno.mem", Values: []interface{}{"web.1", "lots"}}, nil},
+		{lineparser.Point{Series: "events.dyno", Values: []interface{}{"web.1", "R", 14.5}}, nil},
+	}
+	for _, c := range cases {
+		values, ok := pluginPointValues(c.point, 1)
+		if ok != (c.want != nil) || !reflect.DeepEqual(values, c.want) {
+			t.Errorf("pluginPointValues(%v) = %#v, %t; want %#v", c.point, values, ok, c.want)
+		}
+	}
+}
+
+func TestLoadLineParsersUnknownPlugin(t *testing.T) {
+	if _, err := loadLineParsers(nil, nil, map[string]string{"t.a": "missing"}); err == nil {
+		t.Errorf("Expected an error for a token using an unknown plugin")
+	}
+}
+
+func TestLineParserPluginsSeriesFilters(t *testing.T) {
+	defer func(parsers map[string]lineparser.LineParser, filters map[string]seriesFilter) {
+		tokenLineParsers, seriesFilters = parsers, filters
+	}(tokenLineParsers, seriesFilters)
+	tokenLineParsers = map[string]lineparser.LineParser{corpusToken: proxyParser{}}
+	seriesFilters = newSeriesFilters(nil, map[string]map[string]bool{corpusToken: {"router": true}})
+
+	counts := &lineCounts{}
+	points := parseCorpusLinesCounting([]string{
+		"<134>1 2015-03-05T18:21:34.000000+00:00 host app proxy - status=502 ms=40",
+	}, counts)
+
+	if len(points) != 0 {
+		t.Errorf("Expected the plugin's points of a denied series to be skipped, got %v", points)
+	}
+	if counts.skipped[Router] != 1 {
+		t.Errorf("Expected the skipped point to be counted")
+	}
+}
@@ -1,5 +1,15 @@
 package main
 
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
 // Encodes eries Type information
 type SeriesType int
 
@@ -9,21 +19,79 @@ const (
 	DynoMem
 	DynoLoad
 	EventsDyno
+	EventsRouterStatus
+	LogplexHealth
+	LogPatterns
+	SLO
+	DynoConcurrency
+	EventsBackpressure
+	OneOffDyno
+	DynoType
 	numSeries
 )
 
 var (
 	seriesColumns = [][]string{
-		[]string{"time", "status", "service"}, // Router
-		[]string{"time", "code"},              // EventsRouter
-		[]string{"time", "source", "memory_cache", "memory_pgpgin", "memory_pgpgout", "memory_rss", "memory_swap", "memory_total", "dynoType"}, // DynoMem
-		[]string{"time", "source", "load_avg_1m", "load_avg_5m", "load_avg_15m", "dynoType"},                                                   // DynoLoad
-		[]string{"time", "what", "type", "code", "message", "dynoType"},                                                                        // DynoEvents
+		[]string{"time", "status", "service", "connect", "dynoType", "tls_version", "protocol", "request_id", "country", "continent"},                       // Router
+		[]string{"time", "code", "dyno", "path", "dynoType", "restart_related", "request_id"},                                                               // EventsRouter
+		[]string{"time", "source", "memory_cache", "memory_pgpgin", "memory_pgpgout", "memory_rss", "memory_swap", "memory_total", "dynoType", "dyno_size"}, // DynoMem
+		[]string{"time", "source", "load_avg_1m", "load_avg_5m", "load_avg_15m", "dynoType", "dyno_size"},                                                   // DynoLoad
+		[]string{"time", "what", "type", "code", "message", "dynoType", "truncated", "repeats", "memory_total", "memory_pct_quota"},                         // DynoEvents
+		[]string{"time", "status", "dyno", "path", "dynoType", "request_id"},                                                                                // EventsRouterStatus
+		[]string{"time", "code", "dropped", "message"},                                                                                                      // LogplexHealth
+		[]string{"time", "pattern", "count", "dynoType", "new"},                                                                                             // LogPatterns
+		[]string{"time", "sli", "target", "burn_rate", "short_burn_rate", "alerting"},                                                                       // SLO
+		[]string{"time", "dynoType", "concurrency", "dynos", "per_dyno"},                                                                                    // DynoConcurrency
+		[]string{"time", "state", "connect_p95", "baseline", "windows"},                                                                                     // EventsBackpressure
+		[]string{"time", "what", "dynoType", "state", "exit_status", "error"},                                                                               // OneOffDyno
+		[]string{"time", "dynoType", "dynos", "memory_total_avg", "memory_total_max", "load_avg_1m_avg", "load_avg_1m_max"},                                 // DynoType
+	}
+
+	// What lumbermill writes the columns of each series as, which is what
+	// the aggregators expect of them. Router times are floats when
+	// ROUTER_DURATION_UNIT is "s", see columnType.
+	seriesColumnTypes = [][]ColumnType{
+		{ColumnInt, ColumnInt, ColumnInt, ColumnInt, ColumnString, ColumnString, ColumnString, ColumnString, ColumnString, ColumnString},    // Router
+		{ColumnInt, ColumnString, ColumnString, ColumnString, ColumnString, ColumnBool, ColumnString},                                       // EventsRouter
+		{ColumnInt, ColumnString, ColumnFloat, ColumnFloat, ColumnFloat, ColumnFloat, ColumnFloat, ColumnFloat, ColumnString, ColumnString}, // DynoMem
+		{ColumnInt, ColumnString, ColumnFloat, ColumnFloat, ColumnFloat, ColumnString, ColumnString},                                        // DynoLoad
+		{ColumnInt, ColumnString, ColumnString, ColumnInt, ColumnString, ColumnString, ColumnBool, ColumnInt, ColumnFloat, ColumnFloat},     // DynoEvents
+		{ColumnInt, ColumnInt, ColumnString, ColumnString, ColumnString, ColumnString},                                                      // EventsRouterStatus
+		{ColumnInt, ColumnString, ColumnInt, ColumnString},                                                                                  // LogplexHealth
+		{ColumnInt, ColumnString, ColumnInt, ColumnString, ColumnBool},                                                                      // LogPatterns
+		{ColumnInt, ColumnFloat, ColumnFloat, ColumnFloat, ColumnFloat, ColumnBool},                                                         // SLO
+		{ColumnInt, ColumnString, ColumnFloat, ColumnInt, ColumnFloat},                                                                      // DynoConcurrency
+		{ColumnInt, ColumnString, ColumnFloat, ColumnFloat, ColumnInt},                                                                      // EventsBackpressure
+		{ColumnInt, ColumnString, ColumnString, ColumnString, ColumnInt, ColumnString},                                                      // OneOffDyno
+		{ColumnInt, ColumnString, ColumnInt, ColumnFloat, ColumnFloat, ColumnFloat, ColumnFloat},                                            // DynoType
 	}
 
-	seriesNames = []string{"router", "events.router", "dyno.mem", "dyno.load", "events.dyno"}
+	seriesNames = []string{"router", "events.router", "dyno.mem", "dyno.load", "events.dyno", "events.router.status", "logplex.health", "log.patterns", "slo", "dyno.concurrency", "events.backpressure", "dyno.oneoff", "dyno.type"}
+
+	// Template applied to every series name, e.g. "staging.{series}", so
+	// several environments can share one InfluxDB.
+	SeriesNameTemplate = os.Getenv("SERIES_NAME_TEMPLATE")
+
+	// Per token overrides of SeriesNameTemplate, "<token>=<template>,..."
+	TokenSeriesNameTemplates = parseKeyValueList(os.Getenv("TOKEN_SERIES_NAME_TEMPLATES"))
+
+	// Oldest points delivered, globally and per series as
+	// "<series>=<duration>,...". Older points, e.g. from a backlog, are
+	// dropped rather than written behind current dashboards. Off when unset.
+	pointMaxAges = seriesDurations("max age", os.Getenv("POINT_MAX_AGE"), parseKeyValueList(os.Getenv("POINT_MAX_AGES")))
+
+	stalePointsCounter = metrics.GetOrRegisterCounter("lumbermill.points.stale.dropped", metrics.DefaultRegistry)
 )
 
+// A duration setting per series type, 0 when unset
+func seriesDurations(setting, global string, perSeries map[string]string) []time.Duration {
+	durations := make([]time.Duration, numSeries)
+	for st := SeriesType(0); st < numSeries; st++ {
+		durations[st] = parseDurationSetting(setting+" of "+st.Name(), settingFor(perSeries, st.Name(), global), 0)
+	}
+	return durations
+}
+
 func (st SeriesType) Name() string {
 	return seriesNames[st]
 }
@@ -32,13 +100,91 @@ func (st SeriesType) Columns() []string {
 	return seriesColumns[st]
 }
 
+// The type of column i of the series
+func (st SeriesType) columnType(i int) ColumnType {
+	if st == Router && (i == 2 || i == 3) && RouterDurationUnit == "s" {
+		return ColumnFloat
+	}
+	return seriesColumnTypes[st][i]
+}
+
 // Holds data around a data point
 type Point struct {
-	Token  string
-	Type   SeriesType
-	Points []interface{}
+	Token     string
+	Type      SeriesType
+	Points    []interface{}
+	RequestId string // Drain request the point was parsed from, or its ack id
+	Prefix    string // Prepended to the series name, e.g. the drain vhost's
+}
+
+// Whether the point is older than its series' max age
+func (p Point) Stale(now time.Time) bool {
+	maxAge := pointMaxAges[p.Type]
+	if maxAge == 0 {
+		return false
+	}
+	timestamp, ok := p.Timestamp()
+	return ok && now.UnixNano()/int64(time.Microsecond)-timestamp > int64(maxAge/time.Microsecond)
+}
+
+// The point's time, in microseconds since the epoch
+func (p Point) Timestamp() (int64, bool) {
+	timestamp, ok := p.Points[0].(int64)
+	return timestamp, ok
 }
 
 func (p Point) SeriesName() string {
-	return p.Type.Name() + "." + p.Token
+	return p.Prefix + applySeriesNameTemplate(p.Type.Name()+"."+p.Token, p.Type, p.Token)
+}
+
+// Expands {series}, {type} and {token} in the template configured for
+// token. Without a template the series name is left alone.
+func applySeriesNameTemplate(series string, st SeriesType, token string) string {
+	template, found := TokenSeriesNameTemplates[token]
+	if !found {
+		template = SeriesNameTemplate
+	}
+	if template == "" {
+		return series
+	}
+
+	var name strings.Builder
+	for _, part := range parsedSeriesNameTemplate(template) {
+		switch part {
+		case "{series}":
+			name.WriteString(series)
+		case "{type}":
+			name.WriteString(st.Name())
+		case "{token}":
+			name.WriteString(token)
+		default:
+			name.WriteString(part)
+		}
+	}
+	return name.String()
+}
+
+// Templates split into literals and placeholders, parsed once each
+var seriesNameTemplates sync.Map
+
+var seriesNamePlaceholder = regexp.MustCompile(`\{(series|type|token)\}`)
+
+func parsedSeriesNameTemplate(template string) []string {
+	if parts, ok := seriesNameTemplates.Load(template); ok {
+		return parts.([]string)
+	}
+	var parts []string
+	last := 0
+	for _, loc := range seriesNamePlaceholder.FindAllStringIndex(template, -1) {
+		if loc[0] > last {
+			parts = append(parts, template[last:loc[0]])
+		}
+		parts = append(parts, template[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	if last < len(template) {
+		parts = append(parts, template[last:])
+	}
+	seriesNameTemplates.Store(template, parts)
+	return parts
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeriesNameTemplates(t *testing.T) {
+	defer func(global string, perToken map[string]string) {
+		SeriesNameTemplate = global
+		TokenSeriesNameTemplates = perToken
+	}(SeriesNameTemplate, TokenSeriesNameTemplates)
+
+	SeriesNameTemplate = ""
+	TokenSeriesNameTemplates = parseKeyValueList("t.prod={type}.prod.{token}")
+
+	testCases := []struct {
+		global string
+		point  Point
+		name   string
+	}{
+		{"", Point{Token: "t.abc", Type: Router}, "router.t.abc"},
+		{"staging.{series}", Point{Token: "t.abc", Type: Router}, "staging.router.t.abc"},
+		{"{series}.staging", Point{Token: "t.abc", Type: DynoMem}, "dyno.mem.t.abc.staging"},
+		{"staging.{series}", Point{Token: "t.prod", Type: EventsDyno}, "events.dyno.prod.t.prod"},
+		{"{type}-{token}{bogus}", Point{Token: "t.abc", Type: DynoLoad}, "dyno.load-t.abc{bogus}"},
+	}
+
+	for _, tc := range testCases {
+		SeriesNameTemplate = tc.global
+		if name := tc.point.SeriesName(); name != tc.name {
+			t.Errorf("Expected series name %q with template %q, got %q", tc.name, tc.global, name)
+		}
+	}
+}
+
+func TestPointStale(t *testing.T) {
+	defer func(maxAges []time.Duration) { pointMaxAges = maxAges }(pointMaxAges)
+	pointMaxAges = seriesDurations("max age", "1h", parseKeyValueList("router=5m"))
+
+	now := time.Unix(1500000000, 0)
+	at := func(st SeriesType, age time.Duration) Point {
+		return Point{Token: "t.test", Type: st, Points: []interface{}{now.Add(-age).UnixNano() / int64(time.Microsecond)}}
+	}
+
+	if at(Router, 4*time.Minute).Stale(now) || !at(Router, 6*time.Minute).Stale(now) {
+		t.Errorf("Expected router points to go stale after 5m")
+	}
+	if at(EventsDyno, 6*time.Minute).Stale(now) || !at(EventsDyno, 2*time.Hour).Stale(now) {
+		t.Errorf("Expected other points to go stale after 1h")
+	}
+
+	pointMaxAges = seriesDurations("max age", "", nil)
+	if at(Router, 24*time.Hour).Stale(now) {
+		t.Errorf("Expected points never to go stale without a max age")
+	}
+}
+
+func TestSeriesColumnTypes(t *testing.T) {
+	for st := SeriesType(0); st < numSeries; st++ {
+		if len(seriesColumnTypes[st]) != len(st.Columns()) {
+			t.Errorf("%s has %d column types for %d columns", st.Name(), len(seriesColumnTypes[st]), len(st.Columns()))
+		}
+	}
+}
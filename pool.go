@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Buffers reused across drain requests and deliveries, rather than
+// allocated for each, so the garbage collector runs less often under load.
+var (
+	bufioReaderPool  = sync.Pool{New: func() interface{} { return bufio.NewReader(nil) }}
+	bufioWriterPool  = sync.Pool{New: func() interface{} { return bufio.NewWriterSize(nil, 32<<10) }}
+	bufferPool       = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+	gzipWriterPool   = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+	snappyWriterPool = sync.Pool{New: func() interface{} { return snappy.NewBufferedWriter(nil) }}
+	zstdWriterPool   = sync.Pool{New: func() interface{} {
+		// Payloads are compressed by the poster's goroutine, as they're sent
+		zw, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return zw
+	}}
+)
+
+// Buffers larger than this aren't kept, so one huge delivery doesn't pin
+// its memory
+const maxPooledBufferBytes = 4 << 20
+
+func getBufioReader(r io.Reader) *bufio.Reader {
+	br := bufioReaderPool.Get().(*bufio.Reader)
+	br.Reset(r)
+	return br
+}
+
+func putBufioReader(br *bufio.Reader) {
+	br.Reset(nil)
+	bufioReaderPool.Put(br)
+}
+
+func getBufioWriter(w io.Writer) *bufio.Writer {
+	bw := bufioWriterPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	return bw
+}
+
+func putBufioWriter(bw *bufio.Writer) {
+	bw.Reset(nil)
+	bufioWriterPool.Put(bw)
+}
+
+func getBuffer() *bytes.Buffer {
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() <= maxPooledBufferBytes {
+		bufferPool.Put(b)
+	}
+}
+
+// A gzip writer from the pool, closed back into it
+type pooledGzipWriter struct {
+	*gzip.Writer
+}
+
+func newPooledGzipWriter(w io.Writer) io.WriteCloser {
+	gz := gzipWriterPool.Get().(*gzip.Writer)
+	gz.Reset(w)
+	return pooledGzipWriter{gz}
+}
+
+func (p pooledGzipWriter) Close() error {
+	err := p.Writer.Close()
+	gzipWriterPool.Put(p.Writer)
+	return err
+}
+
+// A snappy writer (of the framing format, as payloads are streamed) from
+// the pool, closed back into it
+type pooledSnappyWriter struct {
+	*snappy.Writer
+}
+
+func newPooledSnappyWriter(w io.Writer) io.WriteCloser {
+	sw := snappyWriterPool.Get().(*snappy.Writer)
+	sw.Reset(w)
+	return pooledSnappyWriter{sw}
+}
+
+func (p pooledSnappyWriter) Close() error {
+	err := p.Writer.Close()
+	snappyWriterPool.Put(p.Writer)
+	return err
+}
+
+// A zstd writer from the pool, closed back into it
+type pooledZstdWriter struct {
+	*zstd.Encoder
+}
+
+func newPooledZstdWriter(w io.Writer) io.WriteCloser {
+	zw := zstdWriterPool.Get().(*zstd.Encoder)
+	zw.Reset(w)
+	return pooledZstdWriter{zw}
+}
+
+func (p pooledZstdWriter) Close() error {
+	err := p.Encoder.Close()
+	zstdWriterPool.Put(p.Encoder)
+	return err
+}
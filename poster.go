@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -9,35 +14,151 @@ import (
 	metrics "github.com/rcrowley/go-metrics"
 )
 
-var deliverySizeHistogram = metrics.GetOrRegisterHistogram("lumbermill.poster.deliver.sizes", metrics.DefaultRegistry, metrics.NewUniformSample(100))
+var (
+	deliverySizeHistogram = metrics.GetOrRegisterHistogram("lumbermill.poster.deliver.sizes", metrics.DefaultRegistry, metrics.NewUniformSample(100))
+	deliverySplitCounter  = metrics.GetOrRegisterCounter("lumbermill.poster.deliver.splits", metrics.DefaultRegistry)
+
+	// Points, and approximate bytes of JSON, written to InfluxDB per
+	// request. Larger deliveries are split over several. Unlimited when 0.
+	MaxPointsPerWrite = parseIntSetting("INFLUXDB_MAX_POINTS_PER_WRITE", os.Getenv("INFLUXDB_MAX_POINTS_PER_WRITE"), 0)
+	MaxBytesPerWrite  = parseIntSetting("INFLUXDB_MAX_BYTES_PER_WRITE", os.Getenv("INFLUXDB_MAX_BYTES_PER_WRITE"), 0)
+
+	// Longest a write may take, including waiting for rate limits and
+	// trying other endpoints. Unlimited when 0.
+	DeliveryTimeout = parseDurationSetting("DELIVERY_TIMEOUT", os.Getenv("DELIVERY_TIMEOUT"), 0)
+
+	// Times a write refused with a 401, 403 or 404 is tried again, with
+	// backoff, as credentials are read again from the secrets provider
+	// every time
+	AuthRetries      = parseIntSetting("INFLUXDB_AUTH_RETRIES", os.Getenv("INFLUXDB_AUTH_RETRIES"), 3)
+	authRetryDelay   = time.Second
+	authRetryCounter = metrics.GetOrRegisterCounter("lumbermill.poster.auth.retries", metrics.DefaultRegistry)
+)
+
+// The writer of each destination, for writing quarantined points again
+var (
+	seriesWritersMu sync.Mutex
+	seriesWriters   = make(map[string]SeriesWriter)
+)
+
+func seriesWriterNamed(name string) SeriesWriter {
+	seriesWritersMu.Lock()
+	defer seriesWritersMu.Unlock()
+	return seriesWriters[name]
+}
+
+// The writer of every destination, by name
+func allSeriesWriters() map[string]SeriesWriter {
+	seriesWritersMu.Lock()
+	defer seriesWritersMu.Unlock()
+	writers := make(map[string]SeriesWriter, len(seriesWriters))
+	for name, w := range seriesWriters {
+		writers[name] = w
+	}
+	return writers
+}
+
+// Maximum number of drain request ids logged with a failed delivery
+const maxLoggedRequestIds = 10
+
+// Points gathered for a single write to InfluxDB, grouped by series so
+// each is written as one block of points sharing its columns, however the
+// points arrived
+type delivery struct {
+	series     map[string]*influx.Series
+	tags       map[string][]interface{} // Catalog tags appended to each point of a series, nil without any
+	requestIds map[string]int           // Points of each drain request that contributed
+	canary     map[string]int           // Canary points of each drain request, nil without any
+	priority   map[string]bool          // Series of error events, nil without any
+}
+
+func newDelivery() *delivery {
+	return &delivery{
+		series:     make(map[string]*influx.Series),
+		requestIds: make(map[string]int),
+	}
+}
+
+func (d *delivery) add(point Point) {
+	seriesName := point.SeriesName()
+	series, found := d.series[seriesName]
+	if !found {
+		series = makeSeries(point)
+		d.series[seriesName] = series
+		// Tags are taken once per series, so its points share its columns
+		if tags := catalog.Tags(point.Token, point.Type); tags != nil {
+			series.Columns = append(series.Columns[:len(series.Columns):len(series.Columns)], catalogColumns...)
+			if d.tags == nil {
+				d.tags = make(map[string][]interface{})
+			}
+			d.tags[seriesName] = tags
+		}
+	}
+	if tags := d.tags[seriesName]; tags != nil {
+		point.Points = append(point.Points[:len(point.Points):len(point.Points)], tags...)
+	}
+	series.Points = append(series.Points, point.Points)
+	if point.Type.priority() {
+		if d.priority == nil {
+			d.priority = make(map[string]bool)
+		}
+		d.priority[seriesName] = true
+	}
+	if canary.Owns(point) {
+		if d.canary == nil {
+			d.canary = make(map[string]int)
+		}
+		d.canary[point.RequestId]++
+	}
+	if point.RequestId != "" {
+		d.requestIds[point.RequestId]++
+	}
+}
+
+// A bounded, comma separated list of the contributing request ids
+func (d *delivery) requestIdList() string {
+	ids := make([]string, 0, maxLoggedRequestIds)
+	for id := range d.requestIds {
+		if len(ids) == maxLoggedRequestIds {
+			ids = append(ids, "...")
+			break
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ",")
+}
 
 type Poster struct {
 	destination          *Destination
 	name                 string
-	influxClient         *influx.Client
+	writer               SeriesWriter
+	reorder              *reorderBuffer // nil unless a series is reordered
 	pointsSuccessCounter metrics.Counter
 	pointsSuccessTime    metrics.Timer
 	pointsFailureCounter metrics.Counter
 	pointsFailureTime    metrics.Timer
 	waitGroup            *sync.WaitGroup
+	ctx                  context.Context // Writes are canceled when it's done
 }
 
-func NewPoster(clientConfig influx.ClientConfig, name string, destination *Destination, waitGroup *sync.WaitGroup) *Poster {
-	influxClient, err := influx.NewClient(&clientConfig)
-
-	if err != nil {
-		panic(err)
-	}
+func NewPoster(writer SeriesWriter, name string, destination *Destination, waitGroup *sync.WaitGroup) *Poster {
+	seriesWritersMu.Lock()
+	seriesWriters[name] = writer
+	seriesWritersMu.Unlock()
 
+	// Added here rather than in Run, so waiting can't start before it
+	waitGroup.Add(1)
 	return &Poster{
 		destination:          destination,
 		name:                 name,
-		influxClient:         influxClient,
+		writer:               writer,
+		reorder:              newReorderBuffer(reorderWindows),
 		pointsSuccessCounter: metrics.GetOrRegisterCounter("lumbermill.poster.deliver.points."+name, metrics.DefaultRegistry),
 		pointsSuccessTime:    metrics.GetOrRegisterTimer("lumbermill.poster.success.time."+name, metrics.DefaultRegistry),
 		pointsFailureCounter: metrics.GetOrRegisterCounter("lumbermill.poster.error.points."+name, metrics.DefaultRegistry),
 		pointsFailureTime:    metrics.GetOrRegisterTimer("lumbermill.poster.error.time."+name, metrics.DefaultRegistry),
 		waitGroup:            waitGroup,
+		ctx:                  context.Background(),
 	}
 }
 
@@ -50,9 +171,8 @@ func makeSeries(p Point) *influx.Series {
 
 func (p *Poster) Run() {
 	var last bool
-	var delivery map[string]*influx.Series
+	var delivery *delivery
 
-	p.waitGroup.Add(1)
 	timeout := time.NewTicker(time.Second)
 	defer func() { timeout.Stop() }()
 	defer p.waitGroup.Done()
@@ -63,53 +183,265 @@ func (p *Poster) Run() {
 	}
 }
 
-func (p *Poster) nextDelivery(timeout *time.Ticker) (delivery map[string]*influx.Series, last bool) {
-	delivery = make(map[string]*influx.Series)
+func (p *Poster) nextDelivery(timeout *time.Ticker) (d *delivery, last bool) {
+	d = newDelivery()
+	now := time.Now()
+	add := func(point Point) {
+		if point.Stale(now) {
+			// Dropped on purpose, like paused tokens' points: failing the
+			// request would only have logplex retry ever staler points
+			stalePointsCounter.Inc(1)
+			p.destination.ack(point.RequestId, 1)
+		} else if p.reorder.Holds(point) {
+			p.reorder.Add(point, d)
+		} else {
+			d.add(point)
+		}
+	}
+
+	events := p.destination.events // nil without a priority lane
 	for {
+		// Pending events are taken before any other point
 		select {
+		case point, open := <-events:
+			if !open {
+				events = nil
+				continue
+			}
+			add(point)
+			continue
+		case <-timeout.C:
+			p.reorder.Release(d, time.Now(), false)
+			return d, false
+		default:
+		}
+
+		select {
+		case point, open := <-events:
+			if !open {
+				events = nil
+				continue
+			}
+			add(point)
 		case point, open := <-p.destination.points:
-			if open {
-				seriesName := point.SeriesName()
-				series, found := delivery[seriesName]
-				if !found {
-					series = makeSeries(point)
+			if !open {
+				if events != nil {
+					for point := range events {
+						add(point)
+					}
 				}
-				series.Points = append(series.Points, point.Points)
-				delivery[seriesName] = series
-			} else {
-				return delivery, true
+				p.reorder.Release(d, time.Now(), true)
+				return d, true
 			}
+			add(point)
 		case <-timeout.C:
-			return delivery, false
+			p.reorder.Release(d, time.Now(), false)
+			return d, false
 		}
 	}
 }
 
-func (p *Poster) deliver(allSeries map[string]*influx.Series) {
-	pointCount := 0
-	seriesGroup := make([]*influx.Series, 0, len(allSeries))
+func (p *Poster) deliver(d *delivery) {
+	writes := splitWrites(d.series, d.priority, MaxPointsPerWrite, MaxBytesPerWrite)
+	if len(writes) > 1 {
+		deliverySplitCounter.Inc(int64(len(writes) - 1))
+	}
+	delivered := true
+	for _, series := range writes {
+		delivered = p.write(d, series) && delivered
+	}
 
-	for _, s := range allSeries {
+	// Drain requests waiting on their points learn whether they were
+	// delivered. Those split over writes fail if any of them did.
+	for id, n := range d.requestIds {
+		if delivered {
+			p.destination.ack(id, n)
+		} else {
+			p.destination.fail(id)
+		}
+	}
+	if delivered {
+		for id, n := range d.canary {
+			canary.Delivered(id, n)
+		}
+	}
+}
+
+// Writes some series of a delivery in a single request, returning whether
+// they were delivered
+func (p *Poster) write(d *delivery, series []*influx.Series) bool {
+	pointCount := 0
+	for _, s := range series {
 		pointCount += len(s.Points)
-		seriesGroup = append(seriesGroup, s)
 	}
 
-	if pointCount == 0 {
-		return
+	ctx := p.ctx
+	if DeliveryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DeliveryTimeout)
+		defer cancel()
 	}
 
 	start := time.Now()
-	err := p.influxClient.WriteSeriesWithTimePrecision(seriesGroup, influx.Microsecond)
+	err := faults.DeliveryError()
+	if err == nil {
+		err = p.writeRetryingAuth(ctx, series)
+	}
+	if werr, ok := err.(*backendError); ok && werr.permanent() {
+		err = p.refused(ctx, d, series, werr)
+	}
 
 	if err != nil {
 		// TODO: Ugh. These could be timeout errors, or an internal error.
 		//       Should probably attempt to figure out which...
 		p.pointsFailureCounter.Inc(1)
 		p.pointsFailureTime.UpdateSince(start)
-		log.Printf("Error posting points: %s\n", err)
-	} else {
-		p.pointsSuccessCounter.Inc(1)
-		p.pointsSuccessTime.UpdateSince(start)
-		deliverySizeHistogram.Update(int64(pointCount))
+		log.Printf("request_ids=%s Error posting points: %s\n", d.requestIdList(), err)
+		return false
+	}
+	p.pointsSuccessCounter.Inc(1)
+	p.pointsSuccessTime.UpdateSince(start)
+	deliverySizeHistogram.Update(int64(pointCount))
+	return true
+}
+
+// Writes series, trying again while the backend refuses the credentials
+func (p *Poster) writeRetryingAuth(ctx context.Context, series []*influx.Series) error {
+	delay := authRetryDelay
+	err := p.writer.WriteContext(ctx, series)
+	for attempt := 0; attempt < AuthRetries; attempt++ {
+		if werr, ok := err.(*backendError); !ok || !werr.auth() {
+			break
+		}
+		authRetryCounter.Inc(1)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		delay *= 2
+		err = p.writer.WriteContext(ctx, series)
+	}
+	return err
+}
+
+// Splits the series of a delivery into writes of at most maxPoints points
+// and about maxBytes bytes each, splitting series too when they don't fit.
+// The first series are written ahead of the others. Series are written
+// whole, in one request, without limits, the first series still leading.
+func splitWrites(series map[string]*influx.Series, first map[string]bool, maxPoints, maxBytes int) [][]*influx.Series {
+	names := make([]string, 0, len(series))
+	for name, s := range series {
+		if len(s.Points) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if first[names[i]] != first[names[j]] {
+			return first[names[i]]
+		}
+		return names[i] < names[j]
+	})
+	if maxPoints <= 0 && maxBytes <= 0 {
+		all := make([]*influx.Series, 0, len(names))
+		for _, name := range names {
+			all = append(all, series[name])
+		}
+		return [][]*influx.Series{all}
+	}
+
+	var writes [][]*influx.Series
+	var current []*influx.Series
+	points, bytes := 0, 0
+	for _, name := range names {
+		s := series[name]
+		var part *influx.Series
+		for _, point := range s.Points {
+			size := 0
+			if maxBytes > 0 {
+				size = estimatePointBytes(point)
+				if part == nil {
+					size += estimateSeriesBytes(s)
+				}
+			}
+			if points > 0 && (maxPoints > 0 && points >= maxPoints || maxBytes > 0 && bytes+size > maxBytes) {
+				writes = append(writes, current)
+				current, part, points, bytes = nil, nil, 0, 0
+				if maxBytes > 0 {
+					size = estimatePointBytes(point) + estimateSeriesBytes(s)
+				}
+			}
+			if part == nil {
+				part = &influx.Series{Name: s.Name, Columns: s.Columns}
+				current = append(current, part)
+			}
+			part.Points = append(part.Points, point)
+			points++
+			bytes += size
+		}
+	}
+	return append(writes, current)
+}
+
+// Roughly the bytes of JSON a series takes, without its points
+func estimateSeriesBytes(s *influx.Series) int {
+	n := len(s.Name) + 32
+	for _, column := range s.Columns {
+		n += len(column) + 3
+	}
+	return n
+}
+
+// Roughly the bytes of JSON a point takes
+func estimatePointBytes(point []interface{}) int {
+	n := 2
+	for _, v := range point {
+		switch v := v.(type) {
+		case string:
+			n += len(v) + 2
+		case int:
+			n += len(strconv.Itoa(v))
+		case int64:
+			n += len(strconv.FormatInt(v, 10))
+		case float64:
+			n += 20
+		case bool:
+			n += 5
+		default:
+			n += 4
+		}
+		n++
+	}
+	return n
+}
+
+// Handles a write the backend refused. The series at fault, or all of them
+// when it doesn't say which, are quarantined, and the others written again
+// unless they already were (a partial write).
+func (p *Poster) refused(ctx context.Context, d *delivery, series []*influx.Series, werr *backendError) error {
+	var faulty *influx.Series
+	rest := make([]*influx.Series, 0, len(series))
+	for _, s := range series {
+		if s.Name == werr.series {
+			faulty = s
+		} else {
+			rest = append(rest, s)
+		}
+	}
+	if faulty == nil {
+		for _, s := range series {
+			quarantine.Add(p.name, werr.kind, s)
+		}
+		return werr
+	}
+
+	quarantine.Add(p.name, werr.kind, faulty)
+	log.Printf("request_ids=%s Refused %d points of %s: %s\n", d.requestIdList(), len(faulty.Points), faulty.Name, werr)
+	if werr.kind == "partial_write" || len(rest) == 0 {
+		return nil
 	}
+	return p.writer.WriteContext(ctx, rest)
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+func TestDeliveryTracksRequestIds(t *testing.T) {
+	d := newDelivery()
+	d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10}, RequestId: "req-1"})
+	d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(2), 200, 12}, RequestId: "req-2"})
+	d.add(Point{Token: "t.b", Type: EventsRouter, Points: []interface{}{int64(3), "H12"}, RequestId: "req-2"})
+
+	if len(d.series) != 2 {
+		t.Fatalf("Expected 2 series, got %d", len(d.series))
+	}
+	if n := len(d.series["router.t.a"].Points); n != 2 {
+		t.Errorf("Expected 2 router points, got %d", n)
+	}
+
+	ids := strings.Split(d.requestIdList(), ",")
+	if len(ids) != 2 {
+		t.Errorf("Expected 2 request ids, got %v", ids)
+	}
+
+	for i := 0; i < 2*maxLoggedRequestIds; i++ {
+		d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(i), 200, 1}, RequestId: newRequestId()})
+	}
+	ids = strings.Split(d.requestIdList(), ",")
+	if len(ids) != maxLoggedRequestIds+1 || ids[maxLoggedRequestIds] != "..." {
+		t.Errorf("Expected request id list to be bounded, got %d ids", len(ids))
+	}
+}
+
+func TestReorderBuffer(t *testing.T) {
+	windows := make([]time.Duration, numSeries)
+	windows[Router] = 10 * time.Second
+	buffer := newReorderBuffer(windows)
+
+	now := time.Unix(1500000000, 0)
+	at := func(st SeriesType, ago time.Duration) Point {
+		return Point{Token: "t.test", Type: st, Points: []interface{}{now.Add(-ago).UnixNano() / int64(time.Microsecond)}}
+	}
+	if buffer.Holds(at(EventsRouter, 0)) || !buffer.Holds(at(Router, 0)) {
+		t.Fatalf("Expected only router points to be reordered")
+	}
+	d := newDelivery()
+	for _, ago := range []time.Duration{12, 15, 5, 11, 2} {
+		buffer.Add(at(Router, ago*time.Second), d)
+	}
+
+	buffer.Release(d, now, false)
+	if times := d.series["router.t.test"].Points; len(times) != 3 ||
+		times[0][0].(int64) > times[1][0].(int64) || times[1][0].(int64) > times[2][0].(int64) {
+		t.Errorf("Expected the 3 points older than the window, in order, got %v", times)
+	}
+
+	d = newDelivery()
+	buffer.Release(d, now, true)
+	if n := len(d.series["router.t.test"].Points); n != 2 || len(buffer.series) != 0 {
+		t.Errorf("Expected flushing to release the other 2 points, got %d", n)
+	}
+
+	// A full series is released right away
+	buffer.maxPoints = 3
+	d = newDelivery()
+	for _, ago := range []time.Duration{1, 3, 2} {
+		buffer.Add(at(Router, ago*time.Second), d)
+	}
+	if times := d.series["router.t.test"].Points; len(times) != 3 || len(buffer.series) != 0 ||
+		times[0][0].(int64) > times[1][0].(int64) || times[1][0].(int64) > times[2][0].(int64) {
+		t.Errorf("Expected a full series to be released in order, got %v", times)
+	}
+
+	if newReorderBuffer(make([]time.Duration, numSeries)) != nil {
+		t.Errorf("Expected no buffer without reorder windows")
+	}
+}
+
+func TestSplitWrites(t *testing.T) {
+	d := newDelivery()
+	for i := 0; i < 5; i++ {
+		d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(i), 200, 10}})
+		d.add(Point{Token: "t.b", Type: Router, Points: []interface{}{int64(i), 200, 10}})
+	}
+
+	if writes := splitWrites(d.series, nil, 0, 0); len(writes) != 1 || len(writes[0]) != 2 {
+		t.Fatalf("Expected a single write without limits, got %d", len(writes))
+	}
+
+	writes := splitWrites(d.series, nil, 4, 0)
+	if len(writes) != 3 {
+		t.Fatalf("Expected 3 writes of at most 4 points, got %d", len(writes))
+	}
+	points := 0
+	for _, write := range writes {
+		n := 0
+		for _, s := range write {
+			n += len(s.Points)
+		}
+		if n > 4 {
+			t.Errorf("Expected at most 4 points per write, got %d", n)
+		}
+		points += n
+	}
+	if points != 10 || writes[1][0].Name != "router.t.a" || writes[1][1].Name != "router.t.b" {
+		t.Errorf("Expected all 10 points, splitting router.t.a and router.t.b, got %d", points)
+	}
+
+	perPoint := estimatePointBytes(d.series["router.t.a"].Points[0])
+	overhead := estimateSeriesBytes(d.series["router.t.a"])
+	if writes := splitWrites(d.series, nil, 0, overhead+2*perPoint); len(writes) != 6 {
+		t.Errorf("Expected 3 writes of up to 2 points per series by size, got %d", len(writes))
+	}
+	if writes := splitWrites(d.series, nil, 0, 1); len(writes) != 10 {
+		t.Errorf("Expected a write per point when each is too big, got %d", len(writes))
+	}
+}
+
+func TestDeliverSplitsLargeDeliveries(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	poster := NewPoster(newTestWriter(db, ""), "split-test", NewDestination("split-test", 10), new(sync.WaitGroup))
+
+	defer func(max int) { MaxPointsPerWrite = max }(MaxPointsPerWrite)
+	MaxPointsPerWrite = 3
+	splits := deliverySplitCounter.Count()
+
+	d := newDelivery()
+	for i := 0; i < 7; i++ {
+		d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(i), 200, 10}})
+	}
+	poster.deliver(d)
+
+	if n := len(db.Writes()); n != 3 {
+		t.Errorf("Expected 3 requests, got %d", n)
+	}
+	if n := len(db.Points("router.t.a")); n != 7 {
+		t.Errorf("Expected all 7 points to land, got %d", n)
+	}
+	if n := poster.pointsSuccessCounter.Count(); n != 3 {
+		t.Errorf("Expected 3 successful writes, got %d", n)
+	}
+	if n := deliverySplitCounter.Count() - splits; n != 2 {
+		t.Errorf("Expected 2 extra requests counted, got %d", n)
+	}
+}
+
+func TestDeliveryGroupsPointsBySeries(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	poster := &Poster{writer: newTestWriter(db, ""), name: "grouping-test", ctx: context.Background(),
+		pointsSuccessCounter: metrics.NewCounter(), pointsSuccessTime: metrics.NewTimer(),
+		pointsFailureCounter: metrics.NewCounter(), pointsFailureTime: metrics.NewTimer()}
+
+	// Points of two tokens and two series types, interleaved
+	d := newDelivery()
+	for i := 0; i < 3; i++ {
+		for _, token := range []string{"t.a", "t.b"} {
+			d.add(Point{Token: token, Type: Router, Points: []interface{}{int64(i), 200, 10, 1, "web", "", "", "", nil, nil}})
+			d.add(Point{Token: token, Type: EventsRouter, Points: []interface{}{int64(i), "H12", "web.1", "/", "web", false, ""}})
+		}
+	}
+	poster.deliver(d)
+
+	writes := db.Writes()
+	if len(writes) != 1 || len(writes[0].Series) != 4 {
+		t.Fatalf("Expected a write of 4 series blocks, got %v", writes)
+	}
+	for _, s := range writes[0].Series {
+		if len(s.Points) != 3 || len(s.Columns) != len(s.Points[0]) {
+			t.Errorf("Expected %s to hold its 3 points under its columns, got %v", s.Name, s.Points)
+		}
+	}
+}
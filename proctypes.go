@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// Post the average and maximum memory and load of each token's dyno
+	// types (web, worker, ...) every PROCESS_TYPE_WINDOW (1m) to the
+	// dyno.type series, so capacity dashboards don't need to group raw per
+	// dyno points
+	ProcessTypeAggregates = os.Getenv("PROCESS_TYPE_AGGREGATES") == "true"
+	ProcessTypeWindow     = parseDurationSetting("PROCESS_TYPE_WINDOW", os.Getenv("PROCESS_TYPE_WINDOW"), time.Minute)
+)
+
+// Running average and maximum of a value
+type aggregate struct {
+	n        int
+	sum, max float64
+}
+
+func (a *aggregate) add(v float64) {
+	if a.n == 0 || v > a.max {
+		a.max = v
+	}
+	a.n++
+	a.sum += v
+}
+
+// The average and maximum, nil without values
+func (a aggregate) values() (avg, max interface{}) {
+	if a.n == 0 {
+		return nil, nil
+	}
+	return a.sum / float64(a.n), a.max
+}
+
+type processTypeKey struct {
+	token, dynoType string
+}
+
+type processType struct {
+	dynos  map[string]bool
+	memory aggregate // memory_total
+	load   aggregate // load_avg_1m
+}
+
+// Memory and load per token and dyno type over a window
+type ProcessTypes struct {
+	sync.Mutex
+	current map[processTypeKey]*processType
+}
+
+func NewProcessTypes() *ProcessTypes {
+	return &ProcessTypes{current: make(map[processTypeKey]*processType)}
+}
+
+// Records the runtime metrics points of a batch. A nil ProcessTypes records
+// nothing.
+func (p *ProcessTypes) Record(points []Point) {
+	if p == nil {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	for _, point := range points {
+		switch point.Type {
+		case DynoMem:
+			if len(point.Points) > 8 {
+				if total, ok := point.Points[7].(float64); ok {
+					p.processType(point).memory.add(total)
+				}
+			}
+		case DynoLoad:
+			if len(point.Points) > 5 {
+				if load, ok := point.Points[2].(float64); ok {
+					p.processType(point).load.add(load)
+				}
+			}
+		}
+	}
+}
+
+// The dyno type of a runtime metrics point, counting its dyno
+func (p *ProcessTypes) processType(point Point) *processType {
+	source, _ := point.Points[1].(string)
+	key := processTypeKey{point.Token, dynoType(source)}
+	pt := p.current[key]
+	if pt == nil {
+		pt = &processType{dynos: make(map[string]bool)}
+		p.current[key] = pt
+	}
+	pt.dynos[source] = true
+	return pt
+}
+
+// Ends the current window, returning a dyno.type point per token and dyno
+// type
+func (p *ProcessTypes) Roll(now time.Time) []Point {
+	p.Lock()
+	defer p.Unlock()
+
+	timestamp := now.UnixNano() / int64(time.Microsecond)
+	points := make([]Point, 0, len(p.current))
+	for key, pt := range p.current {
+		memoryAvg, memoryMax := pt.memory.values()
+		loadAvg, loadMax := pt.load.values()
+		points = append(points, Point{Token: key.token, Type: DynoType, Points: []interface{}{timestamp, key.dynoType, len(pt.dynos), memoryAvg, memoryMax, loadAvg, loadMax}})
+	}
+	p.current = make(map[processTypeKey]*processType)
+	return points
+}
+
+// Rolls the window every so often, posting the points, until stop is closed
+func (p *ProcessTypes) Run(routes *derivedRoutes, every time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		for _, point := range p.Roll(time.Now()) {
+			routes.Post(point)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProcessTypesRoll(t *testing.T) {
+	types := NewProcessTypes()
+	types.Record([]Point{
+		{Token: "t.a", Type: DynoMem, Points: []interface{}{int64(1), "web.1", 1.0, 0, 0, 100.0, 0.0, 200.0, "web", nil}},
+		{Token: "t.a", Type: DynoMem, Points: []interface{}{int64(1), "web.2", 1.0, 0, 0, 300.0, 0.0, 400.0, "web", nil}},
+		{Token: "t.a", Type: DynoLoad, Points: []interface{}{int64(1), "web.1", 0.5, 0.4, 0.3, "web", nil}},
+		{Token: "t.a", Type: DynoLoad, Points: []interface{}{int64(1), "worker.1", 2.0, 1.0, 1.0, "worker", nil}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10, 1, "web"}},
+	})
+
+	points := types.Roll(time.Unix(1500000000, 0))
+	byType := make(map[string][]interface{})
+	for _, point := range points {
+		if point.Type != DynoType || point.Token != "t.a" {
+			t.Errorf("Unexpected point: %v", point)
+		}
+		byType[point.Points[1].(string)] = point.Points
+	}
+	if web := byType["web"]; web == nil || web[2] != 2 || web[3] != 300.0 || web[4] != 400.0 || web[5] != 0.5 || web[6] != 0.5 {
+		t.Errorf("Expected 2 web dynos averaging 300MB, got %v", web)
+	}
+	if worker := byType["worker"]; worker == nil || worker[2] != 1 || worker[3] != nil || worker[6] != 2.0 {
+		t.Errorf("Expected a worker dyno without memory samples, got %v", worker)
+	}
+	if points := types.Roll(time.Unix(1500000060, 0)); len(points) != 0 {
+		t.Errorf("Expected an empty window, got %v", points)
+	}
+}
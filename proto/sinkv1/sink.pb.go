@@ -0,0 +1,288 @@
+// Sink service for destinations with the grpc transport: lumbermill calls
+// it with the series it would otherwise write to InfluxDB.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: sink.proto
+
+package sinkv1
+
+import (
+	pointsv1 "github.com/heroku/lumbermill/proto/pointsv1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WriteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*Series              `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteRequest) Reset() {
+	*x = WriteRequest{}
+	mi := &file_sink_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteRequest) ProtoMessage() {}
+
+func (x *WriteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sink_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteRequest.ProtoReflect.Descriptor instead.
+func (*WriteRequest) Descriptor() ([]byte, []int) {
+	return file_sink_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WriteRequest) GetSeries() []*Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type WriteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteResponse) Reset() {
+	*x = WriteResponse{}
+	mi := &file_sink_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteResponse) ProtoMessage() {}
+
+func (x *WriteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sink_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteResponse.ProtoReflect.Descriptor instead.
+func (*WriteResponse) Descriptor() ([]byte, []int) {
+	return file_sink_proto_rawDescGZIP(), []int{1}
+}
+
+// Points of one series, as they'd be written to InfluxDB
+type Series struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The series' full name, e.g. "router.<token>"
+	Name          string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Columns       []string `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows          []*Row   `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Series) Reset() {
+	*x = Series{}
+	mi := &file_sink_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Series) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Series) ProtoMessage() {}
+
+func (x *Series) ProtoReflect() protoreflect.Message {
+	mi := &file_sink_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Series.ProtoReflect.Descriptor instead.
+func (*Series) Descriptor() ([]byte, []int) {
+	return file_sink_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Series) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Series) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *Series) GetRows() []*Row {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// A point's values, in the series' column order
+type Row struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*pointsv1.Value      `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Row) Reset() {
+	*x = Row{}
+	mi := &file_sink_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Row) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Row) ProtoMessage() {}
+
+func (x *Row) ProtoReflect() protoreflect.Message {
+	mi := &file_sink_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Row.ProtoReflect.Descriptor instead.
+func (*Row) Descriptor() ([]byte, []int) {
+	return file_sink_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Row) GetValues() []*pointsv1.Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+var File_sink_proto protoreflect.FileDescriptor
+
+const file_sink_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"sink.proto\x12\x12lumbermill.sink.v1\x1a\fpoints.proto\"B\n" +
+	"\fWriteRequest\x122\n" +
+	"\x06series\x18\x01 \x03(\v2\x1a.lumbermill.sink.v1.SeriesR\x06series\"\x0f\n" +
+	"\rWriteResponse\"c\n" +
+	"\x06Series\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acolumns\x18\x02 \x03(\tR\acolumns\x12+\n" +
+	"\x04rows\x18\x03 \x03(\v2\x17.lumbermill.sink.v1.RowR\x04rows\":\n" +
+	"\x03Row\x123\n" +
+	"\x06values\x18\x01 \x03(\v2\x1b.lumbermill.points.v1.ValueR\x06values2T\n" +
+	"\x04Sink\x12L\n" +
+	"\x05Write\x12 .lumbermill.sink.v1.WriteRequest\x1a!.lumbermill.sink.v1.WriteResponseB+Z)github.com/heroku/lumbermill/proto/sinkv1b\x06proto3"
+
+var (
+	file_sink_proto_rawDescOnce sync.Once
+	file_sink_proto_rawDescData []byte
+)
+
+func file_sink_proto_rawDescGZIP() []byte {
+	file_sink_proto_rawDescOnce.Do(func() {
+		file_sink_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sink_proto_rawDesc), len(file_sink_proto_rawDesc)))
+	})
+	return file_sink_proto_rawDescData
+}
+
+var file_sink_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_sink_proto_goTypes = []any{
+	(*WriteRequest)(nil),   // 0: lumbermill.sink.v1.WriteRequest
+	(*WriteResponse)(nil),  // 1: lumbermill.sink.v1.WriteResponse
+	(*Series)(nil),         // 2: lumbermill.sink.v1.Series
+	(*Row)(nil),            // 3: lumbermill.sink.v1.Row
+	(*pointsv1.Value)(nil), // 4: lumbermill.points.v1.Value
+}
+var file_sink_proto_depIdxs = []int32{
+	2, // 0: lumbermill.sink.v1.WriteRequest.series:type_name -> lumbermill.sink.v1.Series
+	3, // 1: lumbermill.sink.v1.Series.rows:type_name -> lumbermill.sink.v1.Row
+	4, // 2: lumbermill.sink.v1.Row.values:type_name -> lumbermill.points.v1.Value
+	0, // 3: lumbermill.sink.v1.Sink.Write:input_type -> lumbermill.sink.v1.WriteRequest
+	1, // 4: lumbermill.sink.v1.Sink.Write:output_type -> lumbermill.sink.v1.WriteResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_sink_proto_init() }
+func file_sink_proto_init() {
+	if File_sink_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sink_proto_rawDesc), len(file_sink_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sink_proto_goTypes,
+		DependencyIndexes: file_sink_proto_depIdxs,
+		MessageInfos:      file_sink_proto_msgTypes,
+	}.Build()
+	File_sink_proto = out.File
+	file_sink_proto_goTypes = nil
+	file_sink_proto_depIdxs = nil
+}
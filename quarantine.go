@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Keep points backends refuse for good (a 4xx, e.g. a field type
+	// conflict), up to QUARANTINE_MAX_POINTS, to be inspected and retried
+	// from the admin API. QUARANTINE_PATH keeps them in a file as JSON lines
+	// instead of memory only. They're dropped when neither is set.
+	QuarantineEnabled   = os.Getenv("QUARANTINE") == "true"
+	QuarantinePath      = os.Getenv("QUARANTINE_PATH")
+	QuarantineMaxPoints = parseIntSetting("QUARANTINE_MAX_POINTS", os.Getenv("QUARANTINE_MAX_POINTS"), 100000)
+
+	quarantine *Quarantine
+
+	quarantinedPointsCounter = metrics.GetOrRegisterCounter("lumbermill.points.quarantined", metrics.DefaultRegistry)
+	quarantineEvictedCounter = metrics.GetOrRegisterCounter("lumbermill.points.quarantine.evicted", metrics.DefaultRegistry)
+	quarantineRetriedCounter = metrics.GetOrRegisterCounter("lumbermill.points.quarantine.retried", metrics.DefaultRegistry)
+)
+
+// Points a backend refused, and why
+type QuarantineEntry struct {
+	Id          string          `json:"id"`
+	Time        time.Time       `json:"time"`
+	Destination string          `json:"destination"`
+	Reason      string          `json:"reason"`
+	Series      string          `json:"series"`
+	Columns     []string        `json:"columns"`
+	Points      [][]interface{} `json:"points"`
+}
+
+// What the admin API lists of an entry
+type quarantineSummary struct {
+	Id          string    `json:"id"`
+	Time        time.Time `json:"time"`
+	Destination string    `json:"destination"`
+	Reason      string    `json:"reason"`
+	Series      string    `json:"series"`
+	Points      int       `json:"points"`
+}
+
+// A bounded store of refused points, oldest first. When it's full the
+// oldest entries make room.
+//
+// New entries are appended to the file. Evicted ones stay in it, as
+// reloading evicts them again, until they make up half of the points
+// kept, when the file is written whole.
+type Quarantine struct {
+	sync.Mutex
+	path      string
+	file      *os.File
+	maxPoints int
+	points    int
+	stale     int // Points evicted since the file was written whole
+	entries   []QuarantineEntry
+}
+
+// Opens the quarantine at path, picking up the entries already in it. An
+// empty path keeps them in memory only.
+func NewQuarantine(path string, maxPoints int) (*Quarantine, error) {
+	q := &Quarantine{path: path, maxPoints: maxPoints}
+	if path == "" {
+		return q, nil
+	}
+
+	if existing, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(existing)
+		scanner.Buffer(nil, 64<<20)
+		for scanner.Scan() {
+			var entry QuarantineEntry
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+				q.entries = append(q.entries, entry)
+				q.points += len(entry.Points)
+			}
+		}
+		existing.Close()
+	}
+	if q.evict() > 0 {
+		if err := q.save(); err != nil {
+			return nil, err
+		}
+	}
+	return q, q.open()
+}
+
+func (q *Quarantine) open() error {
+	file, err := os.OpenFile(q.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	q.file = file
+	return nil
+}
+
+// Quarantines the points of series refused by destination. A nil
+// Quarantine drops them.
+func (q *Quarantine) Add(destination, reason string, series *influx.Series) {
+	if q == nil {
+		return
+	}
+	entry := QuarantineEntry{
+		Id:          newRequestId(),
+		Time:        time.Now().UTC(),
+		Destination: destination,
+		Reason:      reason,
+		Series:      series.Name,
+		Columns:     series.Columns,
+		Points:      series.Points,
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	q.entries = append(q.entries, entry)
+	q.points += len(entry.Points)
+	quarantinedPointsCounter.Inc(int64(len(entry.Points)))
+
+	q.stale += q.evict()
+	var err error
+	if q.file != nil {
+		if err = q.append(entry); err == nil && q.stale > q.maxPoints/2 {
+			err = q.save()
+		}
+	}
+	if err != nil {
+		log.Printf("Error quarantining points of %s: %s\n", series.Name, err)
+	}
+}
+
+func (q *Quarantine) append(entry QuarantineEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = q.file.Write(append(line, '\n'))
+	return err
+}
+
+// Drops the oldest entries until the points fit, returning how many points
+// were dropped
+func (q *Quarantine) evict() int {
+	evicted := 0
+	for q.points > q.maxPoints && len(q.entries) > 0 {
+		n := len(q.entries[0].Points)
+		q.points -= n
+		evicted += n
+		q.entries = q.entries[1:]
+	}
+	quarantineEvictedCounter.Inc(int64(evicted))
+	return evicted
+}
+
+// Writes the entries to a temporary file and moves it into place, so a
+// crash never leaves a partial file behind.
+func (q *Quarantine) save() error {
+	if q.path == "" {
+		return nil
+	}
+	tmp, err := os.OpenFile(q.path+".tmp", os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, entry := range q.entries {
+		if err := enc.Encode(entry); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+	if err := os.Rename(q.path+".tmp", q.path); err != nil {
+		return err
+	}
+	q.stale = 0
+
+	if q.file != nil {
+		q.file.Close()
+		return q.open()
+	}
+	return nil
+}
+
+// The entries, or the one with id when it's given
+func (q *Quarantine) Entries(id string) []QuarantineEntry {
+	q.Lock()
+	defer q.Unlock()
+	entries := make([]QuarantineEntry, 0, len(q.entries))
+	for _, entry := range q.entries {
+		if id == "" || entry.Id == id {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Removes the entries with the given ids
+func (q *Quarantine) Remove(ids map[string]bool) error {
+	q.Lock()
+	defer q.Unlock()
+	kept := q.entries[:0]
+	for _, entry := range q.entries {
+		if ids[entry.Id] {
+			q.points -= len(entry.Points)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	q.entries = kept
+	return q.save()
+}
+
+// Writes the entries (or the one with id) to their destinations again,
+// removing those that are written. Returns how many were.
+func (q *Quarantine) Retry(id string) (int, error) {
+	retried := make(map[string]bool)
+	var lastErr error
+	for _, entry := range q.Entries(id) {
+		writer := seriesWriterNamed(entry.Destination)
+		if writer == nil {
+			lastErr = fmt.Errorf("Unknown destination %s", entry.Destination)
+			continue
+		}
+		series := &influx.Series{Name: entry.Series, Columns: entry.Columns, Points: entry.Points}
+		if err := writer.Write([]*influx.Series{series}); err != nil {
+			lastErr = err
+			continue
+		}
+		retried[entry.Id] = true
+		quarantineRetriedCounter.Inc(int64(len(entry.Points)))
+	}
+	if len(retried) > 0 {
+		if err := q.Remove(retried); err != nil {
+			return len(retried), err
+		}
+	}
+	return len(retried), lastErr
+}
+
+// GET /admin/quarantine to list the quarantined points, or with
+// ?export=true for all of them as JSON lines (&id=<id> for one entry).
+// POST /admin/quarantine?action=retry|drop, optionally with &id=<id>, to
+// write them to their destinations again, or drop them.
+func (s *LumbermillServer) serveAdminQuarantine(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.adminActor(w, r, roleFor(r, Operator))
+	if !ok {
+		return
+	}
+	if quarantine == nil {
+		writeError(w, r, http.StatusNotFound, errNotFound, "The quarantine is disabled")
+		return
+	}
+
+	query := r.URL.Query()
+	id := query.Get("id")
+	if r.Method == "POST" {
+		switch query.Get("action") {
+		case "retry":
+			retried, err := quarantine.Retry(id)
+			auditLog.Record(actor, "quarantine.retry", id, retried)
+			if err != nil {
+				writeError(w, r, http.StatusBadGateway, errInternal, fmt.Sprintf("Retried %d entries: %s", retried, err))
+				return
+			}
+		case "drop":
+			ids := make(map[string]bool)
+			for _, entry := range quarantine.Entries(id) {
+				ids[entry.Id] = true
+			}
+			if err := quarantine.Remove(ids); err != nil {
+				writeError(w, r, http.StatusInternalServerError, errInternal, err.Error())
+				return
+			}
+			auditLog.Record(actor, "quarantine.drop", id, len(ids))
+		default:
+			writeError(w, r, http.StatusBadRequest, errBadRequest, "action must be retry or drop")
+			return
+		}
+	}
+
+	entries := quarantine.Entries(id)
+	if query.Get("export") == "true" {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		for _, entry := range entries {
+			enc.Encode(entry)
+		}
+		return
+	}
+
+	summaries := make([]quarantineSummary, 0, len(entries))
+	for _, e := range entries {
+		summaries = append(summaries, quarantineSummary{e.Id, e.Time, e.Destination, e.Reason, e.Series, len(e.Points)})
+	}
+	writeJSON(w, summaries)
+}
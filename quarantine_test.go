@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+func TestRefusedWritesAreQuarantined(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "quarantine")
+
+	defer func(q *Quarantine) { quarantine = q }(quarantine)
+	if quarantine, err = NewQuarantine(path, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	poster := NewPoster(newTestWriter(db, ""), "quarantine-test", NewDestination("quarantine-test", 10), new(sync.WaitGroup))
+
+	conflicts := metrics.GetOrRegisterCounter("lumbermill.poster.write.errors.field_type_conflict", metrics.DefaultRegistry).Count()
+	db.Respond(lumbermilltest.Response{Status: 400, Body: `{"error":"field type conflict: input field \"status\" on measurement \"router.t.a\" is type string, already exists as type integer"}`})
+	d := newDelivery()
+	d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), "200", 10}})
+	d.add(Point{Token: "t.b", Type: Router, Points: []interface{}{int64(1), 200, 10}})
+	poster.deliver(d)
+
+	if len(db.Points("router.t.b")) != 1 || len(db.Points("router.t.a")) != 0 {
+		t.Errorf("Expected the other series to be written again")
+	}
+	if metrics.GetOrRegisterCounter("lumbermill.poster.write.errors.field_type_conflict", metrics.DefaultRegistry).Count()-conflicts != 1 {
+		t.Errorf("Expected the conflict to be counted")
+	}
+
+	file, _ := os.Open(path)
+	defer file.Close()
+	entries := make([]QuarantineEntry, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry QuarantineEntry
+		json.Unmarshal(scanner.Bytes(), &entry)
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 || entries[0].Series != "router.t.a" || entries[0].Reason != "field_type_conflict" || len(entries[0].Points) != 1 {
+		t.Errorf("Expected router.t.a's point to be quarantined, got %+v", entries)
+	}
+}
+
+func TestAuthErrorsAreRetried(t *testing.T) {
+	defer func(delay time.Duration) { authRetryDelay = delay }(authRetryDelay)
+	authRetryDelay = time.Millisecond
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	poster := NewPoster(newTestWriter(db, ""), "auth-test", NewDestination("auth-test", 10), new(sync.WaitGroup))
+
+	// e.g. while the password is being rotated
+	db.Respond(lumbermilltest.Response{Status: 401, Body: "Invalid username/password"}, lumbermilltest.Response{Status: 403, Body: "Forbidden"})
+	d := newDelivery()
+	d.add(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 10}})
+	poster.deliver(d)
+
+	if len(db.Points("router.t.a")) != 1 {
+		t.Errorf("Expected the point to be written once the credentials were accepted")
+	}
+	if e := newBackendError(401, nil); e.permanent() {
+		t.Errorf("Expected auth errors not to be permanent")
+	}
+}
+
+func TestQuarantineEvictsOldestAndReloads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "quarantine")
+
+	q, err := NewQuarantine(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"router.t.a", "router.t.b", "router.t.c"} {
+		q.Add("evict-test", "field_type_conflict", &influx.Series{Name: name, Columns: seriesColumns[Router], Points: [][]interface{}{{1, 200, 10}}})
+	}
+	if entries := q.Entries(""); len(entries) != 2 || entries[0].Series != "router.t.b" {
+		t.Errorf("Expected the oldest entry to be evicted, got %+v", entries)
+	}
+
+	reloaded, err := NewQuarantine(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := reloaded.Entries(""); len(entries) != 2 || entries[1].Series != "router.t.c" {
+		t.Errorf("Expected the entries to be reloaded, got %+v", entries)
+	}
+}
+
+func TestQuarantineAppendsUntilCompacting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "quarantine")
+
+	lines := func() int {
+		data, _ := ioutil.ReadFile(path)
+		return strings.Count(string(data), "\n")
+	}
+	q, err := NewQuarantine(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 16; i++ {
+		q.Add("compact-test", "field_type_conflict", &influx.Series{Name: fmt.Sprintf("router.t.%d", i), Columns: seriesColumns[Router], Points: [][]interface{}{{1, 200, 10}}})
+		if i == 15 && lines() != 15 {
+			t.Errorf("Expected entries to be appended while few are evicted, got %d lines", lines())
+		}
+	}
+	if lines() != 10 {
+		t.Errorf("Expected the file to be compacted once half its points were evicted, got %d lines", lines())
+	}
+
+	reloaded, err := NewQuarantine(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := reloaded.Entries(""); len(entries) != 10 || entries[9].Series != "router.t.16" {
+		t.Errorf("Expected the newest entries to be reloaded, got %d", len(entries))
+	}
+}
+
+func TestAdminQuarantineRetry(t *testing.T) {
+	User, Password = "foo", "foo"
+	defer func(q *Quarantine) { quarantine = q }(quarantine)
+	quarantine, _ = NewQuarantine("", 100)
+
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	NewPoster(newTestWriter(db, ""), "retry-test", NewDestination("retry-test", 10), new(sync.WaitGroup))
+	quarantine.Add("retry-test", "field_type_conflict", &influx.Series{Name: "router.t.a", Columns: seriesColumns[Router], Points: [][]interface{}{{1, 200, 10}}})
+	quarantine.Add("unknown", "field_type_conflict", &influx.Series{Name: "router.t.b", Columns: seriesColumns[Router], Points: [][]interface{}{{1, 200, 10}}})
+
+	server := NewLumbermillServer(&http.Server{}, NewHashRing(1, nil))
+	admin := func(method, query string) int {
+		req, _ := http.NewRequest(method, "/admin/quarantine?"+query, nil)
+		req.SetBasicAuth("foo", "foo")
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if code := admin("POST", "action=retry"); code != http.StatusBadGateway {
+		t.Errorf("Expected the unknown destination to fail the retry, got %d", code)
+	}
+	if len(db.Points("router.t.a")) != 1 {
+		t.Errorf("Expected router.t.a to be written again")
+	}
+	entries := quarantine.Entries("")
+	if len(entries) != 1 || entries[0].Series != "router.t.b" {
+		t.Fatalf("Expected only the failed entry to be left, got %+v", entries)
+	}
+
+	if code := admin("POST", "action=drop&id="+entries[0].Id); code != http.StatusOK || len(quarantine.Entries("")) != 0 {
+		t.Errorf("Expected the entry to be dropped, got %d", code)
+	}
+	if code := admin("POST", "action=flush"); code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown action to be refused, got %d", code)
+	}
+}
+
+func TestBackendErrorKinds(t *testing.T) {
+	for body, kind := range map[string]string{
+		`{"error":"partial write: points beyond retention policy dropped=1"}`: "partial_write",
+		`unable to parse 'router,x=1': bad timestamp`:                         "parse",
+		`Database test not found`:                                             "other",
+	} {
+		if e := newBackendError(400, []byte(body)); e.kind != kind || !e.permanent() {
+			t.Errorf("Expected %q to be a permanent %s, got %s", body, kind, e.kind)
+		}
+	}
+	if e := newBackendError(503, []byte("busy")); e.kind != "server" || e.permanent() {
+		t.Errorf("Expected a 503 to be a transient server error")
+	}
+}
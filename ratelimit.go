@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// A token bucket refilled at rate tokens a second, holding at most a
+// second's worth, so a backlog is written at the rate rather than at once
+type tokenBucket struct {
+	sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// A bucket for rate tokens a second, nil (unlimited) unless rate is positive
+func newTokenBucket(rate float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: rate, tokens: rate}
+}
+
+// Takes n tokens at now, returning how long to wait before using them.
+// Taking more than the bucket holds runs it into debt, which later takers
+// wait out.
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.Lock()
+	defer b.Unlock()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+	}
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Limits on the points and requests a destination is written a second
+type writeLimits struct {
+	points    *tokenBucket
+	requests  *tokenBucket
+	throttled metrics.Timer // Time writes waited for the limits
+}
+
+// Limits writes of the destination to pointsPerSec points and
+// requestsPerSec requests a second. Either is unlimited when not positive.
+func (w *seriesWriter) setRateLimits(name string, pointsPerSec, requestsPerSec float64) {
+	if pointsPerSec <= 0 && requestsPerSec <= 0 {
+		w.limits = nil
+		return
+	}
+	w.limits = &writeLimits{
+		points:    newTokenBucket(pointsPerSec),
+		requests:  newTokenBucket(requestsPerSec),
+		throttled: metrics.GetOrRegisterTimer("lumbermill.poster.throttled.time."+name, metrics.DefaultRegistry),
+	}
+}
+
+// Waits until series may be written, or ctx is done
+func (l *writeLimits) wait(ctx context.Context, series []*influx.Series) error {
+	points := 0
+	for _, s := range series {
+		points += len(s.Points)
+	}
+	now := time.Now()
+	wait := l.points.take(float64(points), now)
+	if d := l.requests.take(1, now); d > wait {
+		wait = d
+	}
+	if wait <= 0 {
+		return nil
+	}
+	l.throttled.Update(wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestTokenBucket(t *testing.T) {
+	if newTokenBucket(0) != nil {
+		t.Fatalf("Expected no bucket without a rate")
+	}
+	var unlimited *tokenBucket
+	if wait := unlimited.take(1e6, time.Now()); wait != 0 {
+		t.Errorf("Expected no wait without a limit, got %s", wait)
+	}
+
+	bucket := newTokenBucket(100)
+	now := time.Unix(1500000000, 0)
+	if wait := bucket.take(100, now); wait != 0 {
+		t.Errorf("Expected a second's worth to be taken right away, got %s", wait)
+	}
+	if wait := bucket.take(50, now); wait != 500*time.Millisecond {
+		t.Errorf("Expected to wait 500ms, got %s", wait)
+	}
+	// The debt is paid off first
+	if wait := bucket.take(50, now.Add(time.Second)); wait != 0 {
+		t.Errorf("Expected no wait once refilled, got %s", wait)
+	}
+	// Refills stop at a second's worth
+	if wait := bucket.take(150, now.Add(time.Hour)); wait != 500*time.Millisecond {
+		t.Errorf("Expected the bucket to hold no more than a second's worth, got %s", wait)
+	}
+}
+
+func TestSeriesWriterRateLimits(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "")
+	writer.setRateLimits("ratelimit-test", 0, 0)
+	if writer.limits != nil {
+		t.Fatalf("Expected no limits")
+	}
+
+	writer.setRateLimits("ratelimit-test", 0, 20)
+	start := time.Now()
+	for i := 0; i < 22; i++ {
+		if err := writer.Write(testSeries()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	if took := time.Since(start); took < 50*time.Millisecond {
+		t.Errorf("Expected 22 requests at 20/s to take about 100ms, took %s", took)
+	}
+	if writer.limits.throttled.Count() == 0 {
+		t.Errorf("Expected throttled writes to be timed")
+	}
+}
+
+func TestSeriesWriterRateLimitsCanceled(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "")
+	writer.setRateLimits("ratelimit-test", 1, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := writer.WriteContext(ctx, testSeries()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	start := time.Now()
+	if err := writer.WriteContext(ctx, testSeries()); err != context.DeadlineExceeded {
+		t.Errorf("Expected the write to give up waiting, got %v", err)
+	}
+	if took := time.Since(start); took > 500*time.Millisecond || len(db.Writes()) != 1 {
+		t.Errorf("Expected the second write not to be sent, took %s", took)
+	}
+}
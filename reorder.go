@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// How long points are held back so late ones can be sorted in ahead of
+	// them, globally and per series as "<series>=<duration>,...". Off when
+	// unset.
+	reorderWindows = seriesDurations("reorder window", os.Getenv("REORDER_WINDOW"), parseKeyValueList(os.Getenv("REORDER_WINDOWS")))
+
+	// Points held back per series (i.e. per token) at most. A series that
+	// fills up is released in order right away, as if its window had passed.
+	ReorderMaxPoints = parseIntSetting("REORDER_MAX_POINTS", os.Getenv("REORDER_MAX_POINTS"), 10000)
+
+	reorderOverflowCounter = metrics.GetOrRegisterCounter("lumbermill.reorder.overflows", metrics.DefaultRegistry)
+)
+
+// Holds points back until they're older than their series' reorder window,
+// then releases them in time order. Backends downsampling with continuous
+// queries handle points arriving after newer ones badly.
+//
+// As every poster releases points once they're a window old, a series' writes
+// stay in order across posters, as long as points are no later than that.
+type reorderBuffer struct {
+	windows   []time.Duration
+	maxPoints int
+	series    map[string][]Point
+}
+
+// A buffer for the series with a reorder window, or nil if there are none
+func newReorderBuffer(windows []time.Duration) *reorderBuffer {
+	for _, window := range windows {
+		if window > 0 {
+			return &reorderBuffer{windows: windows, maxPoints: ReorderMaxPoints, series: make(map[string][]Point)}
+		}
+	}
+	return nil
+}
+
+// Whether the point's series is reordered
+func (b *reorderBuffer) Holds(point Point) bool {
+	return b != nil && b.windows[point.Type] > 0
+}
+
+// Holds point back, adding its series' points to d in order when it's full
+func (b *reorderBuffer) Add(point Point, d *delivery) {
+	name := point.SeriesName()
+	points := append(b.series[name], point)
+	if b.maxPoints > 0 && len(points) >= b.maxPoints {
+		reorderOverflowCounter.Inc(1)
+		sortByTime(points)
+		for _, point := range points {
+			d.add(point)
+		}
+		delete(b.series, name)
+		return
+	}
+	b.series[name] = points
+}
+
+func sortByTime(points []Point) {
+	sort.SliceStable(points, func(i, j int) bool {
+		ti, _ := points[i].Timestamp()
+		tj, _ := points[j].Timestamp()
+		return ti < tj
+	})
+}
+
+// Adds the points older than their window to d, oldest first, or all of
+// them when flushing. A nil buffer holds nothing.
+func (b *reorderBuffer) Release(d *delivery, now time.Time, flush bool) {
+	if b == nil {
+		return
+	}
+	nowMicros := now.UnixNano() / int64(time.Microsecond)
+	for name, points := range b.series {
+		sortByTime(points)
+
+		cutoff := nowMicros - int64(b.windows[points[0].Type]/time.Microsecond)
+		released := len(points)
+		if !flush {
+			released = sort.Search(len(points), func(i int) bool {
+				t, _ := points[i].Timestamp()
+				return t > cutoff
+			})
+		}
+		for _, point := range points[:released] {
+			d.add(point)
+		}
+
+		if released == len(points) {
+			delete(b.series, name)
+		} else {
+			b.series[name] = append(points[:0], points[released:]...)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// lumbermill replay [-token <token>] FILE...: delivers the points of
+// archive files (see ARCHIVE_PATH) again, to the backends the environment
+// configures like the server's, e.g. after an InfluxDB host lost data
+func runReplay(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(out)
+	token := flags.String("token", "", "Only replay the points of this token")
+	flags.Usage = func() {
+		fmt.Fprintln(out, "usage: lumbermill replay [options] FILE...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	// Replayed points aren't archived again
+	ArchivePath = ""
+	hashRing, destinations, posterGroup := createMessageRoutes(context.Background(), os.Getenv("INFLUXDB_HOSTS"), os.Getenv("INFLUXDB_SKIP_VERIFY") == "true")
+	status := 0
+	for _, path := range flags.Args() {
+		points, err := replayArchive(path, *token, hashRing)
+		if err != nil {
+			fmt.Fprintf(out, "Error replaying %s: %s\n", path, err)
+			status = 1
+			break
+		}
+		fmt.Fprintf(out, "%s: replayed %d points\n", path, points)
+	}
+	for _, destination := range destinations {
+		destination.Close()
+	}
+	posterGroup.Wait()
+	return status
+}
+
+// Posts the points of the archive file at path, or only token's, to their
+// destinations in hashRing, returning how many were. They're no longer
+// part of the drain requests they were parsed from.
+func replayArchive(path, token string, hashRing *HashRing) (int, error) {
+	replayed := 0
+	_, err := readArchive(path, func(point Point) error {
+		if token != "" && point.Token != token {
+			return nil
+		}
+		point.RequestId = ""
+		if destination := hashRing.Get(point.Token); destination != nil {
+			destination.PostPoint(point)
+			replayed++
+		}
+		return nil
+	})
+	return replayed, err
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Send a daily summary of each token's traffic, errors, R14s and deploys
+	// at REPORT_TIME (UTC, "15:04", default "00:00"), as JSON to
+	// REPORT_WEBHOOK_URL and/or as an email through REPORT_SMTP_ADDR
+	// ("<host>:<port>"). These get the reports of REPORT_TOKENS only, as
+	// they're shared by every token reported on.
+	ReportTime       = stringSetting(os.Getenv("REPORT_TIME"), "00:00")
+	ReportWebhookURL = os.Getenv("REPORT_WEBHOOK_URL")
+	ReportSMTPAddr   = os.Getenv("REPORT_SMTP_ADDR")
+	ReportSMTPFrom   = os.Getenv("REPORT_SMTP_FROM")
+	ReportSMTPTo     = parseSet(os.Getenv("REPORT_SMTP_TO"))
+
+	// Credentials for REPORT_SMTP_ADDR, sent with PLAIN auth when set
+	ReportSMTPUsername = os.Getenv("REPORT_SMTP_USERNAME")
+	ReportSMTPPassword = os.Getenv("REPORT_SMTP_PASSWORD")
+
+	// Tokens reported on to the shared targets, as "<token>,...". No
+	// token is when unset, so tenants never see each other's reports.
+	ReportTokens = parseSet(os.Getenv("REPORT_TOKENS"))
+
+	// Webhooks of single tokens, as "<token>=<url>,...", each getting the
+	// reports of its own token
+	ReportTokenWebhookURLs = parseKeyValueList(os.Getenv("REPORT_TOKEN_WEBHOOK_URLS"))
+
+	// Heroku API lines announcing a deploy, e.g. "Deploy 2a3b4c5 by ..."
+	API          = []byte("api")
+	deployPrefix = []byte("Deploy ")
+
+	reportsSentCounter  = metrics.GetOrRegisterCounter("lumbermill.reports.sent", metrics.DefaultRegistry)
+	reportsErrorCounter = metrics.GetOrRegisterCounter("lumbermill.errors.reports", metrics.DefaultRegistry)
+)
+
+// A token's summary of a day
+type DailyReport struct {
+	Token        string           `json:"token"`
+	Requests     int64            `json:"requests"`
+	RPS          float64          `json:"rps"`
+	P95Service   float64          `json:"p95_service_ms"`
+	Errors       int64            `json:"errors"`        // Requests with a 5xx
+	RouterErrors map[string]int64 `json:"router_errors"` // Failed requests per H code
+	R14s         int64            `json:"r14s"`
+	Deploys      int64            `json:"deploys"`
+	Start        int64            `json:"start"` // Unix time
+	End          int64            `json:"end"`
+}
+
+type tokenReport struct {
+	requests     int64
+	errors       int64
+	routerErrors map[string]int64
+	r14s         int64
+	deploys      int64
+	service      metrics.Sample // Microseconds
+}
+
+// Tallies per token for the daily reports
+type Reports struct {
+	sync.Mutex
+	tokens   map[string]bool   // Tokens reported on to the shared targets
+	webhooks map[string]string // Webhooks of single tokens, by token
+	start    time.Time
+	current  map[string]*tokenReport
+}
+
+func NewReports(tokens map[string]bool, webhooks map[string]string) *Reports {
+	return &Reports{tokens: tokens, webhooks: webhooks, start: time.Now(), current: make(map[string]*tokenReport)}
+}
+
+func (r *Reports) token(token string) *tokenReport {
+	tr, found := r.current[token]
+	if !found {
+		tr = &tokenReport{routerErrors: make(map[string]int64), service: metrics.NewUniformSample(autoscaleSampleSize)}
+		r.current[token] = tr
+	}
+	return tr
+}
+
+func (r *Reports) reports(token string) bool {
+	return r.tokens[token] || r.webhooks[token] != ""
+}
+
+// Records the points of a batch. A nil Reports records nothing.
+func (r *Reports) Record(points []Point) {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	for _, point := range points {
+		if !r.reports(point.Token) {
+			continue
+		}
+		switch point.Type {
+		case Router:
+			tr := r.token(point.Token)
+			tr.requests++
+			if service, ok := routerDurationMicros(point.Points[2]); ok {
+				tr.service.Update(service)
+			}
+			if status, ok := point.Points[1].(int); ok && status >= 500 {
+				tr.errors++
+			}
+		case EventsRouter:
+			if code, ok := point.Points[1].(string); ok {
+				r.token(point.Token).routerErrors[code]++
+			}
+		case EventsDyno:
+			if code, ok := point.Points[3].(int); ok && code == 14 {
+				// Summaries of deduplicated R14s stand for their repeats
+				repeats, ok := point.Points[dynoErrorRepeats].(int)
+				if !ok || repeats < 1 {
+					repeats = 1
+				}
+				r.token(point.Token).r14s += int64(repeats)
+			}
+		}
+	}
+}
+
+// Counts a deploy of token. A nil Reports counts nothing.
+func (r *Reports) Deploy(token string) {
+	if r == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if r.reports(token) {
+		r.token(token).deploys++
+	}
+}
+
+// Ends the current day, returning the reports of its tokens by token
+func (r *Reports) Roll(now time.Time) []DailyReport {
+	r.Lock()
+	defer r.Unlock()
+
+	reports := make([]DailyReport, 0, len(r.current))
+	for token, tr := range r.current {
+		report := DailyReport{
+			Token:        token,
+			Requests:     tr.requests,
+			P95Service:   tr.service.Percentile(0.95) / 1000,
+			Errors:       tr.errors,
+			RouterErrors: tr.routerErrors,
+			R14s:         tr.r14s,
+			Deploys:      tr.deploys,
+			Start:        r.start.Unix(),
+			End:          now.Unix(),
+		}
+		if elapsed := now.Sub(r.start).Seconds(); elapsed > 0 {
+			report.RPS = float64(tr.requests) / elapsed
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Token < reports[j].Token })
+	r.current = make(map[string]*tokenReport)
+	r.start = now
+	return reports
+}
+
+// The next time of day at (as "15:04", UTC) after now
+func nextReportTime(now time.Time, at string) time.Time {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		log.Printf("Invalid REPORT_TIME (%q), using 00:00: %s\n", at, err)
+	}
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Sends the reports every day at the time of day at
+func (r *Reports) Run(at string) {
+	for {
+		time.Sleep(time.Until(nextReportTime(time.Now(), at)))
+		r.sendReports(r.Roll(time.Now()))
+	}
+}
+
+// Sends each token's report to its own webhook, and the reports of the
+// tokens reported on to the shared targets
+func (r *Reports) sendReports(reports []DailyReport) {
+	shared := make([]DailyReport, 0, len(reports))
+	for _, report := range reports {
+		if url := r.webhooks[report.Token]; url != "" {
+			r.send("webhook", postReports(url, []DailyReport{report}))
+		}
+		if r.tokens[report.Token] {
+			shared = append(shared, report)
+		}
+	}
+	if len(shared) == 0 {
+		return
+	}
+	if ReportWebhookURL != "" {
+		r.send("webhook", postReports(ReportWebhookURL, shared))
+	}
+	if ReportSMTPAddr != "" {
+		r.send("email", mailReports(ReportSMTPAddr, shared))
+	}
+}
+
+func (r *Reports) send(target string, err error) {
+	if err != nil {
+		reportsErrorCounter.Inc(1)
+		log.Printf("Error sending daily reports by %s: %s\n", target, err)
+		return
+	}
+	reportsSentCounter.Inc(1)
+}
+
+func postReports(url string, reports []DailyReport) error {
+	body, err := json.Marshal(reports)
+	if err != nil {
+		return err
+	}
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Webhook returned (%d)", resp.StatusCode)
+	}
+	return nil
+}
+
+// The plain text body of the reports email
+func formatReports(reports []DailyReport) string {
+	var b strings.Builder
+	for _, report := range reports {
+		fmt.Fprintf(&b, "%s\n", report.Token)
+		fmt.Fprintf(&b, "  requests: %d (%.2f/s), p95 service: %.0fms\n", report.Requests, report.RPS, report.P95Service)
+		fmt.Fprintf(&b, "  5xx: %d", report.Errors)
+		codes := make([]string, 0, len(report.RouterErrors))
+		for code := range report.RouterErrors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(&b, ", %s: %d", code, report.RouterErrors[code])
+		}
+		fmt.Fprintf(&b, "\n  R14s: %d, deploys: %d\n\n", report.R14s, report.Deploys)
+	}
+	return b.String()
+}
+
+func mailReports(addr string, reports []DailyReport) error {
+	to := make([]string, 0, len(ReportSMTPTo))
+	for recipient := range ReportSMTPTo {
+		to = append(to, recipient)
+	}
+	sort.Strings(to)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\n", ReportSMTPFrom, strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: lumbermill daily report for %s\r\n", time.Unix(reports[0].Start, 0).UTC().Format("2006-01-02"))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.Replace(formatReports(reports), "\n", "\r\n", -1))
+
+	var auth smtp.Auth
+	if ReportSMTPUsername != "" {
+		host := strings.Split(addr, ":")[0]
+		auth = smtp.PlainAuth("", ReportSMTPUsername, secrets.Get("REPORT_SMTP_PASSWORD", ReportSMTPPassword), host)
+	}
+	return smtp.SendMail(addr, auth, ReportSMTPFrom, to, msg.Bytes())
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestReportsTallyTokens(t *testing.T) {
+	defer func(unit string) { RouterDurationUnit = unit }(RouterDurationUnit)
+	RouterDurationUnit = "ms"
+	reports := NewReports(map[string]bool{"t.a": true}, nil)
+	start := reports.start
+
+	reports.Record([]Point{
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 20, 1}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(2), 503, 30000, 1}},
+		{Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(3), "H12"}},
+		{Token: "t.a", Type: EventsDyno, Points: []interface{}{int64(4), "web.1", "R", 14, "Error R14", "web", false, 1}},
+		{Token: "t.a", Type: EventsDyno, Points: []interface{}{int64(5), "web.1", "R", 14, "Error R14", "web", false, 4}},
+		{Token: "t.b", Type: Router, Points: []interface{}{int64(1), 200, 20, 1}},
+	})
+	body := lumbermilltest.Body(lumbermilltest.SyslogLine("app", "api", "Deploy 2a3b4c5 by jo@example.com"))
+	lp := lpx.NewReader(bufio.NewReader(strings.NewReader(body)))
+	parseLines(lp, drainContext{token: "t.a", requestId: "reports", reports: reports}, new(pointBatch), &lineCounts{})
+
+	got := reports.Roll(start.Add(10 * time.Second))
+	if len(got) != 1 {
+		t.Fatalf("Expected a report of t.a only, got %v", got)
+	}
+	report := got[0]
+	if report.Requests != 2 || report.RPS != 0.2 || report.Errors != 1 || report.RouterErrors["H12"] != 1 || report.R14s != 5 || report.Deploys != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if text := formatReports(got); !strings.Contains(text, "5xx: 1, H12: 1") || !strings.Contains(text, "R14s: 5, deploys: 1") {
+		t.Errorf("Unexpected report text: %q", text)
+	}
+	if got := reports.Roll(start.Add(20 * time.Second)); len(got) != 0 {
+		t.Errorf("Expected the tallies to be reset, got %v", got)
+	}
+}
+
+func TestNextReportTime(t *testing.T) {
+	now := time.Date(2017, 7, 14, 9, 30, 0, 0, time.UTC)
+	for at, expected := range map[string]time.Time{
+		"12:00": time.Date(2017, 7, 14, 12, 0, 0, 0, time.UTC),
+		"09:30": time.Date(2017, 7, 15, 9, 30, 0, 0, time.UTC),
+		"00:00": time.Date(2017, 7, 15, 0, 0, 0, 0, time.UTC),
+	} {
+		if next := nextReportTime(now, at); !next.Equal(expected) {
+			t.Errorf("Expected %s for %s, got %s", expected, at, next)
+		}
+	}
+}
+
+func TestPostReports(t *testing.T) {
+	var received []DailyReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	if err := postReports(server.URL, []DailyReport{{Token: "t.a", Deploys: 2}}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(received) != 1 || received[0].Token != "t.a" || received[0].Deploys != 2 {
+		t.Errorf("Unexpected reports received: %v", received)
+	}
+}
+
+func TestSendReportsKeepsTenantsApart(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string][]string) // Tokens reported, by webhook path
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reports []DailyReport
+		json.NewDecoder(r.Body).Decode(&reports)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, report := range reports {
+			received[r.URL.Path] = append(received[r.URL.Path], report.Token)
+		}
+	}))
+	defer server.Close()
+	defer func(url string) { ReportWebhookURL = url }(ReportWebhookURL)
+	ReportWebhookURL = server.URL + "/shared"
+
+	reports := NewReports(map[string]bool{"t.ops": true}, map[string]string{"t.a": server.URL + "/a", "t.b": server.URL + "/b"})
+	reports.Record([]Point{
+		{Token: "t.ops", Type: Router, Points: []interface{}{int64(1), 200, 20, 1}},
+		{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 20, 1}},
+		{Token: "t.b", Type: Router, Points: []interface{}{int64(1), 200, 20, 1}},
+		{Token: "t.other", Type: Router, Points: []interface{}{int64(1), 200, 20, 1}},
+	})
+	reports.sendReports(reports.Roll(time.Now()))
+
+	expected := map[string][]string{"/shared": {"t.ops"}, "/a": {"t.a"}, "/b": {"t.b"}}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("Expected %v, got %v", expected, received)
+	}
+}
+
+func TestPostReportsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	defer func(client *http.Client) { webhookClient = client }(webhookClient)
+	webhookClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+	if err := postReports(server.URL, []DailyReport{{Token: "t.a"}}); err == nil {
+		t.Errorf("Expected a hanging webhook to time out")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// How long after a dyno restart router errors with RESTART_RELATED_CODES
+	// (H13 and H18 by default) on the same app are marked restart_related, to
+	// tell deploy blips from real incidents
+	RestartCorrelationWindow = parseDurationSetting("RESTART_CORRELATION_WINDOW", os.Getenv("RESTART_CORRELATION_WINDOW"), 30*time.Second)
+	RestartRelatedCodes      = parseSet(stringSetting(os.Getenv("RESTART_RELATED_CODES"), "H13,H18"))
+
+	// Heroku's lines about a dyno going down
+	dynoRestartSentinels = [][]byte{[]byte("Restarting"), []byte("State changed from up to"), []byte("Stopping all processes")}
+)
+
+func isDynoRestart(msg []byte) bool {
+	for _, sentinel := range dynoRestartSentinels {
+		if bytes.HasPrefix(msg, sentinel) {
+			return true
+		}
+	}
+	return false
+}
+
+// The latest dyno restart of each app (token). Only errors after a restart
+// are related to it, as they aren't held back waiting for one.
+type DynoRestarts struct {
+	sync.Mutex
+	latest map[string]int64 // In microseconds
+}
+
+func NewDynoRestarts() *DynoRestarts {
+	return &DynoRestarts{latest: make(map[string]int64)}
+}
+
+// Records a restart of one of token's dynos. A nil DynoRestarts records
+// nothing.
+func (d *DynoRestarts) Record(token string, timestamp int64) {
+	if d == nil {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	if timestamp > d.latest[token] {
+		d.latest[token] = timestamp
+	}
+}
+
+// Whether a router error with code at timestamp followed one of token's dyno
+// restarts closely enough to be related to it
+func (d *DynoRestarts) Related(token, code string, timestamp int64) bool {
+	if d == nil || !RestartRelatedCodes[code] {
+		return false
+	}
+	d.Lock()
+	restart, found := d.latest[token]
+	d.Unlock()
+	return found && timestamp >= restart && timestamp-restart <= int64(RestartCorrelationWindow/time.Microsecond)
+}
+
+// Forgets restarts no errors can be related to anymore
+func (d *DynoRestarts) Expire(now time.Time) {
+	cutoff := now.Add(-RestartCorrelationWindow).UnixNano() / int64(time.Microsecond)
+	d.Lock()
+	defer d.Unlock()
+	for token, restart := range d.latest {
+		if restart < cutoff {
+			delete(d.latest, token)
+		}
+	}
+}
+
+// Expires restarts every so often
+func (d *DynoRestarts) Run(every time.Duration) {
+	for {
+		time.Sleep(every)
+		d.Expire(time.Now())
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"time"
+)
+
+var (
+	// Bounds of the Retry-After hint of shed drain requests. Requests shed
+	// for maintenance or paused tokens, which has no end in sight, are told
+	// to wait the longest.
+	RetryAfterMin = parseDurationSetting("RETRY_AFTER_MIN", os.Getenv("RETRY_AFTER_MIN"), time.Second)
+	RetryAfterMax = parseDurationSetting("RETRY_AFTER_MAX", os.Getenv("RETRY_AFTER_MAX"), time.Minute)
+)
+
+// How long the destinations should take to deliver their backlog at their
+// current rate
+func backlogRetryAfter(destinations []*Destination) time.Duration {
+	var pending int64
+	var rate float64
+	for _, d := range destinations {
+		pending += int64(d.depth())
+		rate += d.DrainRate()
+	}
+	return retryAfter(pending, rate)
+}
+
+// Time to deliver pending points at rate points per second, between
+// RetryAfterMin and RetryAfterMax
+func retryAfter(pending int64, rate float64) time.Duration {
+	after := RetryAfterMax
+	if pending == 0 {
+		after = 0
+	} else if rate > 0 {
+		after = time.Duration(float64(pending) / rate * float64(time.Second))
+	}
+	if after < RetryAfterMin {
+		return RetryAfterMin
+	}
+	if after > RetryAfterMax {
+		return RetryAfterMax
+	}
+	return after
+}
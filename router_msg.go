@@ -2,10 +2,98 @@ package main
 
 import (
 	"bytes"
+	"hash/fnv"
+	"log"
+	"os"
 	"strconv"
-	"strings"
+	"time"
+
+	"github.com/kr/logfmt"
 )
 
+// Unit router service and connect times are written in: "ms" (the default)
+// or "us" as ints, or "s" as floats. Routers report them with units, or as
+// bare milliseconds in older formats.
+var RouterDurationUnit = routerDurationUnit(os.Getenv("ROUTER_DURATION_UNIT"))
+
+func routerDurationUnit(unit string) string {
+	switch unit {
+	case "ms", "us", "s":
+		return unit
+	case "":
+	default:
+		log.Printf("Unknown router duration unit (%q), using ms\n", unit)
+	}
+	return "ms"
+}
+
+// Parses a router duration, e.g. "12ms", "1.2s" or "12"
+func parseRouterDuration(val []byte) (time.Duration, error) {
+	s := string(val)
+	if ms, err := strconv.Atoi(s); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// A router duration in RouterDurationUnit
+func routerDurationValue(d time.Duration) interface{} {
+	switch RouterDurationUnit {
+	case "us":
+		return int(d / time.Microsecond)
+	case "s":
+		return d.Seconds()
+	}
+	return int(d / time.Millisecond)
+}
+
+// A router duration value back in microseconds, whatever unit it's in
+func routerDurationMicros(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		if RouterDurationUnit == "us" {
+			return int64(v), true
+		}
+		return int64(v) * 1000, true
+	case float64:
+		return int64(v * 1e6), true
+	}
+	return 0, false
+}
+
+var (
+	// Share of router points (0 to 1) recorded with their request_id, to
+	// trace requests back to app logs, and whether errors always are
+	RequestIdSampleRate = parseFloatSetting("REQUEST_ID_SAMPLE_RATE", os.Getenv("REQUEST_ID_SAMPLE_RATE"), 0)
+	RequestIdErrors     = os.Getenv("REQUEST_ID_ERRORS") == "true"
+)
+
+// The request_id column of a router point, null unless it's sampled. The
+// sample is by request id, so all points of a request agree.
+func sampledRequestId(requestId string, isError bool) interface{} {
+	if requestId == "" {
+		return nil
+	}
+	if isError && RequestIdErrors {
+		return requestId
+	}
+	if RequestIdSampleRate <= 0 {
+		return nil
+	}
+	h := fnv.New32a()
+	h.Write([]byte(requestId))
+	if float64(h.Sum32()%10000) < RequestIdSampleRate*10000 {
+		return requestId
+	}
+	return nil
+}
+
+// Statuses worth an events.router.status point even without an H code:
+// 5xx responses, and 499s when clients gave up on a request
+func statusEvent(status int) bool {
+	return status >= 500 || status == 499
+}
+
 var (
 	keyAt        = []byte("at")
 	keyCode      = []byte("code")
@@ -20,14 +108,33 @@ var (
 	keyStatus    = []byte("status")
 	keySock      = []byte("sock")
 	keyBytes     = []byte("bytes")
+	keyTLS       = []byte("tls_version")
+	keyProtocol  = []byte("protocol")
 	keyCodeH     = []byte("code=H")
 	keyCodeBlank = []byte("code=blank-app")
 	keyDescBlank = []byte("desc=\"Blank app\"")
 )
 
+// Router keys have been spelled with hyphens as well as underscores (e.g.
+// request-id), so both are accepted
+func routerKey(key []byte) []byte {
+	if bytes.IndexByte(key, '-') == -1 {
+		return key
+	}
+	return bytes.Replace(key, []byte("-"), []byte("_"), -1)
+}
+
+// An optional string column, null when the line doesn't have it
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
 // at=info method=GET path=/check?metric=railgun.accepting:sum:max,railgun.running:sum:max&0
 // host=umpire.herokai.com request_id=1f3ed8a9-c80c-49de-a4af-2df9f4ddb858 fwd="46.20.45.18"
-// dyno=web.14 connect=1ms service=849ms status=500 bytes=306
+// dyno=web.14 connect=1ms service=849ms status=500 bytes=306 protocol=https tls_version=TLSv1.3
 type routerMsg struct {
 	Method    string
 	Path      string
@@ -35,13 +142,16 @@ type routerMsg struct {
 	RequestId string
 	Fwd       string
 	Dyno      string
-	Connect   int
-	Service   int
+	Connect   time.Duration
+	Service   time.Duration
 	Status    int
 	Bytes     int
+	TLS       string // Only on newer lines
+	Protocol  string // Only on newer lines
 }
 
 func (rm *routerMsg) HandleLogfmt(key, val []byte) error {
+	key = routerKey(key)
 	switch {
 	case bytes.Equal(key, keyMethod):
 		rm.Method = string(val)
@@ -56,13 +166,13 @@ func (rm *routerMsg) HandleLogfmt(key, val []byte) error {
 	case bytes.Equal(key, keyDyno):
 		rm.Dyno = string(val)
 	case bytes.Equal(key, keyConnect):
-		connect, e := strconv.Atoi(strings.TrimSuffix(string(val), "ms"))
+		connect, e := parseRouterDuration(val)
 		if e != nil {
 			return e
 		}
 		rm.Connect = connect
 	case bytes.Equal(key, keyService):
-		service, e := strconv.Atoi(strings.TrimSuffix(string(val), "ms"))
+		service, e := parseRouterDuration(val)
 		if e != nil {
 			return e
 		}
@@ -79,6 +189,10 @@ func (rm *routerMsg) HandleLogfmt(key, val []byte) error {
 			return e
 		}
 		rm.Bytes = bytes
+	case bytes.Equal(key, keyTLS):
+		rm.TLS = string(val)
+	case bytes.Equal(key, keyProtocol):
+		rm.Protocol = string(val)
 	default:
 		return nil
 		// log.Printf("Unknown key (%s) with value: %s\n", key, string(val))
@@ -96,14 +210,15 @@ type routerError struct {
 	Dyno      string
 	Path      string
 	RequestId string
-	Connect   int
-	Service   int
+	Connect   time.Duration
+	Service   time.Duration
 	Status    int
 	Bytes     int
 	Sock      string
 }
 
 func (re *routerError) HandleLogfmt(key, val []byte) error {
+	key = routerKey(key)
 	switch {
 	case bytes.Equal(key, keyAt):
 		re.At = string(val)
@@ -124,11 +239,11 @@ func (re *routerError) HandleLogfmt(key, val []byte) error {
 	case bytes.Equal(key, keyDyno):
 		re.Dyno = string(val)
 	case bytes.Equal(key, keyConnect):
-		connect, _ := strconv.Atoi(strings.TrimSuffix(string(val), "ms"))
+		connect, _ := parseRouterDuration(val)
 		// swallow errors because connect could be nothing
 		re.Connect = connect
 	case bytes.Equal(key, keyService):
-		service, _ := strconv.Atoi(strings.TrimSuffix(string(val), "ms"))
+		service, _ := parseRouterDuration(val)
 		// swallow errors because service could be nothing
 		re.Service = service
 	case bytes.Equal(key, keyStatus):
@@ -147,3 +262,165 @@ func (re *routerError) HandleLogfmt(key, val []byte) error {
 	}
 	return nil
 }
+
+// The router line shape is by far the most common, so it's scanned by hand
+// for the keys points and lookups use, without allocating for the others or
+// going through the generic logfmt handler. Lines of another shape (escaped
+// quotes, hyphenated keys, durations other than whole milliseconds, bare
+// keys) return false, and are left to logfmt.
+func scanRouterPairs(msg []byte, pair func(key, val []byte) bool) bool {
+	i := 0
+	for i < len(msg) {
+		if msg[i] <= ' ' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(msg) && isIdentByte(msg[i]) {
+			if msg[i] == '-' {
+				return false
+			}
+			i++
+		}
+		if i == start || i == len(msg) || msg[i] != '=' {
+			return false
+		}
+		key := msg[start:i]
+		i++
+
+		var val []byte
+		if i < len(msg) && msg[i] == '"' {
+			end := bytes.IndexByte(msg[i+1:], '"')
+			if end == -1 {
+				return false
+			}
+			val = msg[i+1 : i+1+end]
+			if bytes.IndexByte(val, '\\') != -1 {
+				return false
+			}
+			i += end + 2
+		} else {
+			start = i
+			for i < len(msg) && isIdentByte(msg[i]) {
+				i++
+			}
+			val = msg[start:i]
+		}
+		if i < len(msg) && msg[i] > ' ' {
+			// e.g. a '=' in an unquoted value, which logfmt splits oddly
+			return false
+		}
+		if !pair(key, val) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentByte(c byte) bool {
+	return c > ' ' && c != '"' && c != '='
+}
+
+// Parses a non negative int without allocating
+func atoiBytes(val []byte) (int, bool) {
+	if len(val) == 0 || len(val) > 9 {
+		return 0, false
+	}
+	n := 0
+	for _, c := range val {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+// Parses whole milliseconds, e.g. "12ms" or "12"
+func millisBytes(val []byte) (time.Duration, bool) {
+	n, ok := atoiBytes(bytes.TrimSuffix(val, []byte("ms")))
+	return time.Duration(n) * time.Millisecond, ok
+}
+
+// Parses a router line into rm, scanning it by hand when it has the usual
+// shape
+func parseRouterMsg(msg []byte, rm *routerMsg) error {
+	if scanRouterPairs(msg, rm.scan) {
+		return nil
+	}
+	*rm = routerMsg{}
+	return logfmt.Unmarshal(msg, rm)
+}
+
+// Like HandleLogfmt, for the same keys
+func (rm *routerMsg) scan(key, val []byte) bool {
+	ok := true
+	switch string(key) {
+	case "status":
+		rm.Status, ok = atoiBytes(val)
+	case "service":
+		rm.Service, ok = millisBytes(val)
+	case "connect":
+		rm.Connect, ok = millisBytes(val)
+	case "bytes":
+		rm.Bytes, ok = atoiBytes(val)
+	case "dyno":
+		rm.Dyno = string(val)
+	case "method":
+		rm.Method = string(val)
+	case "path":
+		rm.Path = string(val)
+	case "host":
+		rm.Host = string(val)
+	case "fwd":
+		rm.Fwd = string(val)
+	case "request_id":
+		rm.RequestId = string(val)
+	case "tls_version":
+		rm.TLS = string(val)
+	case "protocol":
+		rm.Protocol = string(val)
+	}
+	return ok
+}
+
+// Parses a router error line into re, scanning it by hand when it has the
+// usual shape
+func parseRouterError(msg []byte, re *routerError) error {
+	if scanRouterPairs(msg, re.scan) {
+		return nil
+	}
+	*re = routerError{}
+	return logfmt.Unmarshal(msg, re)
+}
+
+// Like HandleLogfmt, for all but the at, desc and sock keys nothing reads
+func (re *routerError) scan(key, val []byte) bool {
+	ok := true
+	switch string(key) {
+	case "code":
+		re.Code = string(val)
+	case "status":
+		re.Status, ok = atoiBytes(val)
+	case "service":
+		re.Service, ok = millisBytes(val)
+	case "connect":
+		re.Connect, ok = millisBytes(val)
+	case "bytes":
+		re.Bytes, ok = atoiBytes(val)
+	case "dyno":
+		re.Dyno = string(val)
+	case "method":
+		re.Method = string(val)
+	case "path":
+		re.Path = string(val)
+	case "host":
+		re.Host = string(val)
+	case "fwd":
+		re.Fwd = string(val)
+	case "request_id":
+		re.RequestId = string(val)
+	}
+	return ok
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Makes s safe to serialize: invalid UTF-8 sequences are replaced with
+// U+FFFD and control characters, other than tabs, are stripped. Reports
+// whether s had to be changed.
+func sanitizeString(s string) (string, bool) {
+	if utf8.ValidString(s) && strings.IndexFunc(s, isStrippedControl) == -1 {
+		return s, false
+	}
+	s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	return strings.Map(func(r rune) rune {
+		if isStrippedControl(r) {
+			return -1
+		}
+		return r
+	}, s), true
+}
+
+func isStrippedControl(r rune) bool {
+	return r != '\t' && unicode.IsControl(r)
+}
+
+// Sanitizes the string values of point, returning how many changed. The
+// values are copied before being changed, as other destinations may share
+// them.
+func sanitizePoint(point Point) (Point, int) {
+	changed := 0
+	for i, v := range point.Points {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if clean, dirty := sanitizeString(s); dirty {
+			if changed == 0 {
+				point.Points = append([]interface{}(nil), point.Points...)
+			}
+			point.Points[i] = clean
+			changed++
+		}
+	}
+	return point, changed
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestSanitizeString(t *testing.T) {
+	for _, tc := range []struct {
+		in, out string
+		dirty   bool
+	}{
+		{"Error R14 (Memory quota exceeded)", "Error R14 (Memory quota exceeded)", false},
+		{"tabs\tare fine", "tabs\tare fine", false},
+		{"caf\xe9", "caf�", true},
+		{"bell\x07 and \x1b[31mescape", "bell and [31mescape", true},
+	} {
+		out, dirty := sanitizeString(tc.in)
+		if out != tc.out || dirty != tc.dirty {
+			t.Errorf("sanitizeString(%q) = %q, %t; want %q, %t", tc.in, out, dirty, tc.out, tc.dirty)
+		}
+	}
+}
+
+func TestDestinationSanitizesPoints(t *testing.T) {
+	values := []interface{}{int64(1), "web.1", "caf\xe9"}
+	destination := NewDestination("sanitize-test", 1)
+	destination.SanitizeUTF8 = true
+	destination.PostPoint(Point{Token: "t.test", Type: EventsDyno, Points: values})
+
+	point := <-destination.points
+	if point.Points[2] != "caf�" {
+		t.Errorf("Value wasn't sanitized: %q", point.Points[2])
+	}
+	if values[2] != "caf\xe9" {
+		t.Errorf("Original values were changed")
+	}
+	if n := destination.sanitizedCounter.Count(); n != 1 {
+		t.Errorf("Expected 1 sanitized value, got %d", n)
+	}
+}
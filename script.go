@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/file"
+	"github.com/expr-lang/expr/parser/lexer"
+	"github.com/expr-lang/expr/vm"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// File of scripts run on every point before delivery, the most nodes an
+	// expression may have, and the memory an expression may allocate per
+	// point (in elements of the arrays and maps it builds)
+	ScriptsPath        = os.Getenv("SCRIPTS_PATH")
+	ScriptMaxNodes     = parseIntSetting("SCRIPT_MAX_NODES", os.Getenv("SCRIPT_MAX_NODES"), 200)
+	ScriptMemoryBudget = parseIntSetting("SCRIPT_MEMORY_BUDGET", os.Getenv("SCRIPT_MEMORY_BUDGET"), 1000)
+
+	// The scripts of each series, nil when there are none
+	seriesScripts [][]*script
+
+	scriptDroppedCounter = metrics.GetOrRegisterCounter("lumbermill.points.script.dropped", metrics.DefaultRegistry)
+	scriptErrorCounter   = metrics.GetOrRegisterCounter("lumbermill.errors.script", metrics.DefaultRegistry)
+)
+
+// A drop or set statement of a series, one of
+//
+//	<series> drop [if <expression>]
+//	<series> set <column> = <expression> [if <expression>]
+//
+// e.g. `router drop if status == 404 && hasPrefix(path, "/assets/")`.
+// Expressions are expr (https://expr-lang.org) expressions seeing the
+// point's columns, and its token as `token`. Null columns are nil.
+//
+// Expressions can't do I/O and only loop over the arrays they build, so
+// SCRIPT_MAX_NODES and SCRIPT_MEMORY_BUDGET bound how long they run, and
+// scripts can't hold up the drain.
+type script struct {
+	source string
+	column int         // Column set, or dropColumn to drop the point
+	value  *vm.Program // What the column is set to
+	cond   *vm.Program // nil to always apply
+}
+
+const dropColumn = -1
+
+// Builtins that allocate without counting against the memory budget
+var scriptDisabledBuiltins = []string{"repeat"}
+
+// Loads the scripts at path, one per line. Blank lines and lines starting
+// with # are skipped.
+func loadScripts(path string) ([][]*script, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scripts := make([][]*script, numSeries)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		st, s, err := parseScript(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, n, err)
+		}
+		scripts[st] = append(scripts[st], s)
+	}
+	return scripts, scanner.Err()
+}
+
+func parseScript(line string) (SeriesType, *script, error) {
+	fields := strings.SplitN(line, " ", 3)
+	st, found := seriesTypesByName[fields[0]]
+	if !found || len(fields) < 2 {
+		return 0, nil, fmt.Errorf("unknown series %q", fields[0])
+	}
+	rest := ""
+	if len(fields) == 3 {
+		rest = strings.TrimSpace(fields[2])
+	}
+
+	s := &script{source: line, column: dropColumn}
+	var value, cond string
+	var conditional bool
+	switch fields[1] {
+	case "drop":
+		if rest != "" {
+			if conditional = strings.HasPrefix(rest, "if "); !conditional {
+				return 0, nil, fmt.Errorf("expected if after drop")
+			}
+			cond = rest[len("if "):]
+		}
+	case "set":
+		assignment := strings.SplitN(rest, "=", 2)
+		column := strings.TrimSpace(assignment[0])
+		if s.column = columnIndex(st, column); s.column < 1 {
+			return 0, nil, fmt.Errorf("can't set %q", column)
+		}
+		if len(assignment) < 2 {
+			return 0, nil, fmt.Errorf("expected = after set %s", column)
+		}
+		var err error
+		if value, cond, conditional, err = splitCondition(assignment[1]); err != nil {
+			return 0, nil, err
+		}
+	default:
+		return 0, nil, fmt.Errorf("expected drop or set")
+	}
+
+	var err error
+	if s.column != dropColumn {
+		if s.value, err = compileScriptExpression(st, value); err != nil {
+			return 0, nil, err
+		}
+	}
+	if conditional {
+		if s.cond, err = compileScriptExpression(st, cond, expr.AsBool()); err != nil {
+			return 0, nil, err
+		}
+	}
+	return st, s, nil
+}
+
+// The index of the series' column, or -1
+func columnIndex(st SeriesType, name string) int {
+	for i, column := range st.Columns() {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Splits "<expression> [if <expression>]" at the if, which is the only one
+// outside of brackets as expressions can't have if operators
+func splitCondition(s string) (value, cond string, conditional bool, err error) {
+	tokens, err := lexer.Lex(file.NewSource(s))
+	if err != nil {
+		return "", "", false, err
+	}
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.Is(lexer.Bracket, "(", "[", "{"):
+			depth++
+		case t.Is(lexer.Bracket, ")", "]", "}"):
+			depth--
+		case depth == 0 && t.Is(lexer.Operator, "if"):
+			runes := []rune(s)
+			return string(runes[:t.From]), string(runes[t.To:]), true, nil
+		}
+	}
+	return s, "", false, nil
+}
+
+// Compiles an expression over the columns of series st
+func compileScriptExpression(st SeriesType, source string, options ...expr.Option) (*vm.Program, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("expected an expression")
+	}
+	options = append(options,
+		expr.Env(scriptEnvTypes(st)),
+		expr.DisableIfOperator(),
+		expr.MaxNodes(uint(ScriptMaxNodes)))
+	for _, builtin := range scriptDisabledBuiltins {
+		options = append(options, expr.DisableBuiltin(builtin))
+	}
+	return expr.Compile(source, options...)
+}
+
+// The columns of series st and the token, with a value of each one's type
+// to check expressions against
+func scriptEnvTypes(st SeriesType) map[string]interface{} {
+	env := map[string]interface{}{"token": ""}
+	for i, column := range st.Columns() {
+		switch st.columnType(i) {
+		case ColumnInt:
+			env[column] = 0
+		case ColumnFloat:
+			env[column] = 0.0
+		case ColumnBool:
+			env[column] = false
+		default:
+			env[column] = ""
+		}
+	}
+	return env
+}
+
+// Runs the scripts of the batch's points' series, dropping and changing
+// them. Points a script fails on are left as they are.
+func (b *pointBatch) RunScripts() {
+	if seriesScripts == nil {
+		return
+	}
+	kept := b.points[:0]
+	for _, point := range b.points {
+		if runScripts(seriesScripts[point.Type], point) {
+			kept = append(kept, point)
+		} else {
+			scriptDroppedCounter.Inc(1)
+		}
+	}
+	b.points = kept
+}
+
+// Runs scripts on point, returning whether it's kept
+func runScripts(scripts []*script, point Point) bool {
+	if len(scripts) == 0 {
+		return true
+	}
+	env := scriptEnv(point)
+	for _, s := range scripts {
+		if s.cond != nil {
+			v, err := runScript(s.cond, env)
+			if err != nil {
+				scriptError(s, err)
+				continue
+			}
+			if v != true {
+				continue
+			}
+		}
+		if s.column == dropColumn {
+			return false
+		}
+		v, err := runScript(s.value, env)
+		if err != nil {
+			scriptError(s, err)
+			continue
+		}
+		if s.column < len(point.Points) {
+			point.Points[s.column] = v
+			env[point.Type.Columns()[s.column]] = v
+		}
+	}
+	return true
+}
+
+func runScript(program *vm.Program, env map[string]interface{}) (interface{}, error) {
+	machine := vm.VM{MemoryBudget: uint(ScriptMemoryBudget)}
+	return machine.Run(program, env)
+}
+
+// What a point's expressions are evaluated against
+func scriptEnv(point Point) map[string]interface{} {
+	env := map[string]interface{}{"token": point.Token}
+	for i, column := range point.Type.Columns() {
+		if i < len(point.Points) {
+			env[column] = point.Points[i]
+		} else {
+			env[column] = nil
+		}
+	}
+	return env
+}
+
+func scriptError(s *script, err error) {
+	scriptErrorCounter.Inc(1)
+	if Debug.On() {
+		log.Printf("Error running script (%q): %s\n", s.source, err)
+	}
+}
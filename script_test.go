@@ -0,0 +1,96 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScripts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scripts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "scripts")
+	ioutil.WriteFile(path, []byte(`# Health checks aren't interesting
+router drop if status == 200 && token == "t.a" && hasPrefix(dynoType, "health")
+router set service = service / 1000 if service >= 1000
+events.router set path = lower(path) + "?" if !(path contains "?") || code != "H12"
+`), 0600)
+
+	defer func(scripts [][]*script) { seriesScripts = scripts }(seriesScripts)
+	if seriesScripts, err = loadScripts(path); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := new(pointBatch)
+	batch.PostPoint(Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 3, 1, "healthcheck"}})
+	batch.PostPoint(Point{Token: "t.b", Type: Router, Points: []interface{}{int64(1), 200, 2500, 1, "healthcheck"}})
+	batch.PostPoint(Point{Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(1), "H10", "web.1", "/Users", "web", false}})
+	batch.RunScripts()
+
+	if len(batch.points) != 2 {
+		t.Fatalf("Expected t.a's health check to be dropped, got %v", batch.points)
+	}
+	if service := batch.points[0].Points[2]; service != 2.5 {
+		t.Errorf("Expected the service time to be set to 2.5, got %#v", service)
+	}
+	if path := batch.points[1].Points[3]; path != "/users?" {
+		t.Errorf("Expected the path to be lowered, got %#v", path)
+	}
+}
+
+func TestScriptErrors(t *testing.T) {
+	for _, line := range []string{
+		"router explode",
+		"routers drop",
+		"router set time = 1",
+		"router set nope = 1",
+		"router drop if status ==",
+		"router drop if (status == 1",
+		`router drop if contains(dynoType)`,
+		`router drop if dynoType == "open`,
+		"router drop if status == 1 2",
+		"router drop status == 1",
+		"router set service =",
+		"router set service = 1 if",
+		`router drop if repeat("a", 10) == ""`,
+		"router drop if status",
+	} {
+		if _, _, err := parseScript(line); err == nil {
+			t.Errorf("Expected an error parsing %q", line)
+		}
+	}
+
+	defer func(nodes, budget int) { ScriptMaxNodes, ScriptMemoryBudget = nodes, budget }(ScriptMaxNodes, ScriptMemoryBudget)
+	ScriptMaxNodes = 5
+	if _, _, err := parseScript("router drop if status + 1 + 1 + 1 + 1 > 0"); err == nil {
+		t.Errorf("Expected an expression with too many nodes to be refused")
+	}
+
+	ScriptMaxNodes, ScriptMemoryBudget = 200, 100
+	_, s, err := parseScript("router drop if len(map(1..1000000, # * 2)) > 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errors := scriptErrorCounter.Count()
+	if !runScripts([]*script{s}, Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 3, 1, "web"}}) {
+		t.Errorf("Expected a point to be kept when its script runs out of memory")
+	}
+	if scriptErrorCounter.Count()-errors != 1 {
+		t.Errorf("Expected the script to fail")
+	}
+}
+
+func TestScriptNullColumns(t *testing.T) {
+	_, s, err := parseScript("router set tls_version = \"none\" if tls_version == nil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	point := Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), 200, 3, 1, "web", nil}}
+	if !runScripts([]*script{s}, point) || point.Points[5] != "none" {
+		t.Errorf("Expected the null column to be set, got %#v", point.Points[5])
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// Columns scrubbed of PII, as "<series>=<column>|<column>,...". Defaults
+	// to the raw message and path columns.
+	ScrubColumns = stringSetting(os.Getenv("SCRUB_COLUMNS"),
+		"events.dyno=message,events.router=path,events.router.status=path")
+
+	// File of extra patterns to redact, one regular expression per line
+	ScrubPatternsPath = os.Getenv("SCRUB_PATTERNS_PATH")
+
+	scrubColumnIndexes = columnIndexes(scrubColumnNames(parseAllowedTokens(ScrubColumns)))
+
+	// Query strings go first, so their tokens and emails are redacted whole
+	scrubRules = []scrubRule{
+		{regexp.MustCompile(`\?[^\s"#]*=[^\s"#]*`), "?" + redacted},
+		{regexp.MustCompile(`(?i)\b(access_token|api_key|apikey|token|secret|password|auth)=[^\s&"]+`), "$1=" + redacted},
+		{regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9._~+/=-]+`), "Bearer " + redacted},
+		{regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`), redacted},
+	}
+)
+
+// A pattern and what its matches are replaced with
+type scrubRule struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// The scrubbed column names per series type
+func scrubColumnNames(columns map[string]map[string]bool) [][]string {
+	names := make([][]string, numSeries)
+	for series, set := range columns {
+		st, found := seriesTypesByName[series]
+		if !found {
+			continue
+		}
+		for column := range set {
+			names[st] = append(names[st], column)
+		}
+	}
+	return names
+}
+
+// Adds the patterns in path to the scrub rules, redacting their matches
+func loadScrubPatterns(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		re, err := regexp.Compile(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s", path, n, err)
+		}
+		scrubRules = append(scrubRules, scrubRule{re, redacted})
+	}
+	return scanner.Err()
+}
+
+// Redacts emails, tokens, query strings and any extra patterns from s.
+// Reports whether s had to be changed.
+func scrubString(s string) (string, bool) {
+	clean := s
+	for _, rule := range scrubRules {
+		clean = rule.re.ReplaceAllString(clean, rule.repl)
+	}
+	return clean, clean != s
+}
+
+// Scrubs the configured columns of point, returning how many values
+// changed. Like sanitizePoint, values are copied before being changed.
+func scrubPoint(point Point) (Point, int) {
+	changed := 0
+	for _, i := range scrubColumnIndexes[point.Type] {
+		if i >= len(point.Points) {
+			continue
+		}
+		s, ok := point.Points[i].(string)
+		if !ok {
+			continue
+		}
+		if clean, dirty := scrubString(s); dirty {
+			if changed == 0 {
+				point.Points = append([]interface{}(nil), point.Points...)
+			}
+			point.Points[i] = clean
+			changed++
+		}
+	}
+	return point, changed
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScrubString(t *testing.T) {
+	for _, tc := range []struct {
+		in, out string
+	}{
+		{"Error R14 (Memory quota exceeded)", "Error R14 (Memory quota exceeded)"},
+		{"/users?email=jo@example.com&page=2", "/users?[REDACTED]"},
+		{"/search?", "/search?"},
+		{"signup from jo.smith+x@example.co.uk failed", "signup from [REDACTED] failed"},
+		{"retrying with token=abc123 and Authorization: Bearer eyJhbGci.x", "retrying with token=[REDACTED] and Authorization: Bearer [REDACTED]"},
+	} {
+		if out, _ := scrubString(tc.in); out != tc.out {
+			t.Errorf("scrubString(%q) = %q; want %q", tc.in, out, tc.out)
+		}
+	}
+}
+
+func TestDestinationScrubsPoints(t *testing.T) {
+	values := []interface{}{int64(1), "H12", "web.1", "/reset?token=abc", "web", false, "req-1"}
+	destination := NewDestination("scrub-test", 1)
+	destination.ScrubPII = true
+	destination.PostPoint(Point{Token: "t.test", Type: EventsRouter, Points: values})
+
+	point := <-destination.points
+	if point.Points[3] != "/reset?[REDACTED]" || point.Points[2] != "web.1" {
+		t.Errorf("Expected only the path to be scrubbed, got %v", point.Points)
+	}
+	if values[3] != "/reset?token=abc" {
+		t.Errorf("Original values were changed")
+	}
+	if n := destination.scrubbedCounter.Count(); n != 1 {
+		t.Errorf("Expected 1 scrubbed value, got %d", n)
+	}
+}
+
+func TestLoadScrubPatterns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "patterns")
+	ioutil.WriteFile(path, []byte("# card numbers\n\\b\\d{4}-\\d{4}-\\d{4}-\\d{4}\\b\n"), 0644)
+
+	defer func(rules []scrubRule) { scrubRules = rules }(scrubRules)
+	if err := loadScrubPatterns(path); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if out, _ := scrubString("charged 4111-1111-1111-1111"); out != "charged [REDACTED]" {
+		t.Errorf("Expected the extra pattern to be redacted, got %q", out)
+	}
+
+	ioutil.WriteFile(path, []byte("(unclosed\n"), 0644)
+	if err := loadScrubPatterns(path); err == nil {
+		t.Errorf("Expected an invalid pattern to fail")
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A source of secrets such as credentials, keyed by the name of the
+// environment variable they'd otherwise be read from.
+type SecretsProvider interface {
+	Secrets() (map[string]string, error)
+}
+
+// Reads KEY=VALUE lines from a file, e.g. a mounted Kubernetes secret or
+// an env-file. Blank lines and lines starting with # are ignored.
+type fileSecrets struct {
+	path string
+}
+
+func (f fileSecrets) Secrets() (map[string]string, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	secrets := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		secrets[strings.TrimSpace(parts[0])] = strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+	}
+	return secrets, scanner.Err()
+}
+
+// Reads a secret from HashiCorp Vault's KV secrets engine (v1 or v2)
+type vaultSecrets struct {
+	addr   string
+	token  string
+	path   string
+	client *http.Client
+}
+
+func (v vaultSecrets) Secrets() (map[string]string, error) {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(v.addr, "/")+"/v1/"+strings.TrimPrefix(v.path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Vault returned (%d) for %s", resp.StatusCode, v.path)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	// KV v2 nests the secret under data.data, next to its metadata
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nested
+		}
+	}
+
+	secrets := make(map[string]string)
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			secrets[k] = s
+		}
+	}
+	return secrets, nil
+}
+
+// Secrets loaded from a provider and refreshed periodically, so they can be
+// rotated without a restart.
+type Secrets struct {
+	provider SecretsProvider
+
+	sync.RWMutex
+	values map[string]string
+}
+
+func NewSecrets(provider SecretsProvider) *Secrets {
+	return &Secrets{provider: provider, values: make(map[string]string)}
+}
+
+// Reloads the secrets from the provider
+func (s *Secrets) Refresh() error {
+	if s.provider == nil {
+		return nil
+	}
+	values, err := s.provider.Secrets()
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	before := s.values
+	s.values = values
+	s.Unlock()
+
+	// The first load isn't a change
+	if len(before) > 0 && changed(before, values) {
+		auditLog.Record("system", "secrets.refresh", fingerprints(before), fingerprints(values))
+	}
+	return nil
+}
+
+func changed(before, after map[string]string) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for k, v := range after {
+		if b, found := before[k]; !found || b != v {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh the secrets every so often
+func (s *Secrets) Watch(every time.Duration) {
+	for {
+		time.Sleep(every)
+		if err := s.Refresh(); err != nil {
+			log.Printf("Error refreshing secrets: %s\n", err)
+		}
+	}
+}
+
+// Returns the named secret, or fallback if the provider doesn't have it
+func (s *Secrets) Get(name, fallback string) string {
+	s.RLock()
+	defer s.RUnlock()
+	if v, found := s.values[name]; found {
+		return v
+	}
+	return fallback
+}
+
+// Creates the secrets provider configured in the environment, if any
+func secretsProviderFromEnv() SecretsProvider {
+	switch {
+	case os.Getenv("VAULT_ADDR") != "" && os.Getenv("VAULT_SECRET_PATH") != "":
+		return vaultSecrets{
+			addr:   os.Getenv("VAULT_ADDR"),
+			token:  os.Getenv("VAULT_TOKEN"),
+			path:   os.Getenv("VAULT_SECRET_PATH"),
+			client: &http.Client{Timeout: 10 * time.Second},
+		}
+	case os.Getenv("SECRETS_FILE") != "":
+		return fileSecrets{os.Getenv("SECRETS_FILE")}
+	}
+	return nil
+}
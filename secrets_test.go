@@ -0,0 +1,85 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestFileSecretsRotation(t *testing.T) {
+	file, err := ioutil.TempFile("", "lumbermill-secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	write := func(contents string) {
+		if err := ioutil.WriteFile(file.Name(), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("# credentials\nINFLUXDB_USER=writer\nINFLUXDB_PWD=\"first\"\n\n")
+	s := NewSecrets(fileSecrets{file.Name()})
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := s.Get("INFLUXDB_PWD", "env"); v != "first" {
+		t.Errorf("Expected first password, got %q", v)
+	}
+	if v := s.Get("PASSWORD", "env"); v != "env" {
+		t.Errorf("Expected fallback for missing secret, got %q", v)
+	}
+
+	write("INFLUXDB_USER=writer\nINFLUXDB_PWD=second\n")
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.Get("INFLUXDB_PWD", "env"); v != "second" {
+		t.Errorf("Expected rotated password, got %q", v)
+	}
+
+	entries := auditLog.Entries()
+	if len(entries) == 0 || entries[len(entries)-1].Action != "secrets.refresh" {
+		t.Fatalf("Rotation wasn't audited")
+	}
+	if after := entries[len(entries)-1].After.(map[string]string); after["INFLUXDB_PWD"] == "second" {
+		t.Errorf("Audit log revealed a secret")
+	}
+}
+
+func TestVaultSecrets(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/lumbermill":
+			w.Write([]byte(`{"data": {"data": {"PASSWORD": "v2"}, "metadata": {"version": 3}}}`))
+		case "/v1/secret/lumbermill":
+			w.Write([]byte(`{"data": {"PASSWORD": "v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+
+	for path, expected := range map[string]string{"secret/data/lumbermill": "v2", "secret/lumbermill": "v1"} {
+		s := NewSecrets(vaultSecrets{addr: vault.URL, token: "root", path: path, client: http.DefaultClient})
+		if err := s.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if v := s.Get("PASSWORD", ""); v != expected {
+			t.Errorf("Expected %q from %s, got %q", expected, path, v)
+		}
+	}
+
+	s := NewSecrets(vaultSecrets{addr: vault.URL, token: "wrong", path: "secret/lumbermill", client: http.DefaultClient})
+	if err := s.Refresh(); err == nil {
+		t.Error("Expected an error with a bad token")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+)
+
+// What a Heroku line is about, from the sentinels in it
+type lineKind int
+
+const (
+	unknownLine lineKind = iota
+	routerInfoLine
+	routerErrorLine
+	routerBlankLine
+	dynoErrorLine
+	dynoMemLine
+	dynoLoadLine
+	dynoRestartLine
+	dynoKillLine
+	logplexErrorLine
+)
+
+var (
+	sentinelCode        = []byte("code=")
+	sentinelSample      = []byte("sample#")
+	sentinelMemoryTotal = dynoMemMsgSentinel[len("sample#"):]
+	sentinelLoadAvg1m   = dynoLoadMsgSentinel[len("sample#"):]
+)
+
+// Classifies a router line. Rather than a scan per sentinel, each "code="
+// is checked for an H or blank app code, so an ordinary line is scanned
+// once for codes and once for a blank app description.
+func routerLineKind(msg []byte) lineKind {
+	blank := false
+	for rest := msg; ; {
+		i := bytes.Index(rest, sentinelCode)
+		if i == -1 {
+			break
+		}
+		rest = rest[i:]
+		if bytes.HasPrefix(rest, keyCodeH) {
+			return routerErrorLine
+		}
+		blank = blank || bytes.HasPrefix(rest, keyCodeBlank)
+		rest = rest[len(sentinelCode):]
+	}
+	if blank || bytes.Contains(msg, keyDescBlank) {
+		return routerBlankLine
+	}
+	return routerInfoLine
+}
+
+// Classifies a dyno line: the prefixes are checked first, as they're
+// cheap, then the line is scanned once for runtime metrics samples. Dyno
+// errors and kills come before samples, and samples before restarts and
+// logplex errors.
+func dynoLineKind(msg []byte) lineKind {
+	prefixed := unknownLine
+	if len(msg) > 0 {
+		switch msg[0] {
+		case 'E':
+			if bytes.HasPrefix(msg, dynoErrorSentinel) {
+				return dynoErrorLine
+			}
+			if bytes.HasPrefix(msg, logplexErrorSentinel) {
+				prefixed = logplexErrorLine
+			}
+		case 'R', 'S':
+			if bytes.HasPrefix(msg, dynoKillSentinel) {
+				return dynoKillLine
+			}
+			if isDynoRestart(msg) {
+				prefixed = dynoRestartLine
+			}
+		}
+	}
+
+	i := bytes.Index(msg, sentinelSample)
+	if i == -1 {
+		return prefixed
+	}
+	// Memory samples come first, so the rest only needs scanning for a
+	// line that starts with another sample
+	rest := msg[i+len(sentinelSample):]
+	if bytes.HasPrefix(rest, sentinelMemoryTotal) || bytes.Contains(rest, dynoMemMsgSentinel) {
+		return dynoMemLine
+	}
+	if bytes.HasPrefix(rest, sentinelLoadAvg1m) || bytes.Contains(rest, dynoLoadMsgSentinel) {
+		return dynoLoadLine
+	}
+	return prefixed
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/lpx"
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+// How lines were classified before, with a scan per sentinel
+func lineKindByContains(router bool, msg []byte) lineKind {
+	if router {
+		switch {
+		case bytes.Contains(msg, keyCodeH):
+			return routerErrorLine
+		case bytes.Contains(msg, keyCodeBlank), bytes.Contains(msg, keyDescBlank):
+			return routerBlankLine
+		}
+		return routerInfoLine
+	}
+	switch {
+	case bytes.HasPrefix(msg, dynoErrorSentinel):
+		return dynoErrorLine
+	case bytes.HasPrefix(msg, dynoKillSentinel):
+		return dynoKillLine
+	case bytes.Contains(msg, dynoMemMsgSentinel):
+		return dynoMemLine
+	case bytes.Contains(msg, dynoLoadMsgSentinel):
+		return dynoLoadLine
+	case isDynoRestart(msg):
+		return dynoRestartLine
+	case bytes.HasPrefix(msg, logplexErrorSentinel):
+		return logplexErrorLine
+	}
+	return unknownLine
+}
+
+func lineKindBySentinels(router bool, msg []byte) lineKind {
+	if router {
+		return routerLineKind(msg)
+	}
+	return dynoLineKind(msg)
+}
+
+// The procid and message of every corpus line, and a few odd ones
+func sentinelTestLines(t testing.TB) (procids []string, msgs [][]byte) {
+	corpus, err := lumbermilltest.LoadCorpus(corpusDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lines := range corpus {
+		lp := lpx.NewReader(bufio.NewReader(strings.NewReader(lumbermilltest.Body(lines...))))
+		for lp.Next() {
+			procids = append(procids, string(lp.Header().Procid))
+			msgs = append(msgs, append([]byte(nil), lp.Bytes()...))
+		}
+	}
+	for _, odd := range []struct{ procid, msg string }{
+		{"router", `at=info code=blank-app desc="Blank app" status=404`},
+		{"router", `at=info desc="Blank app" code=H14 status=503`},
+		{"router", `at=info path=/code=Hx status=200`},
+		{"web.1", `Error R14 (Memory quota exceeded) sample#memory_total=1MB`},
+		{"web.1", `Restarting sample#load_avg_1m=0.1`},
+		{"web.1", `Stopping process with SIGKILL sample#memory_total=1MB`},
+		{"web.1", `source=web.1 sample#load_avg_5m=0.1 sample#memory_total=1MB sample#load_avg_1m=0.1`},
+		{"web.1", `State changed from up to down`},
+		{"web.1", `Error L10 (output buffer overflow): 5 messages dropped since 2015-03-05T18:21:30+00:00.`},
+		{"web.1", ``},
+	} {
+		procids = append(procids, odd.procid)
+		msgs = append(msgs, []byte(odd.msg))
+	}
+	return procids, msgs
+}
+
+func TestLineKinds(t *testing.T) {
+	procids, msgs := sentinelTestLines(t)
+	for i, msg := range msgs {
+		router := procids[i] == "router"
+		if kind, expected := lineKindBySentinels(router, msg), lineKindByContains(router, msg); kind != expected {
+			t.Errorf("%q: expected kind %d, got %d", msg, expected, kind)
+		}
+	}
+}
+
+func benchmarkLineKinds(b *testing.B, classify func(bool, []byte) lineKind) {
+	procids, msgs := sentinelTestLines(b)
+	routers := make([]bool, len(procids))
+	for i, procid := range procids {
+		routers[i] = procid == "router"
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		// A large batch of every kind of line
+		for r := 0; r < 10; r++ {
+			for i, msg := range msgs {
+				classify(routers[i], msg)
+			}
+		}
+	}
+}
+
+func BenchmarkLineKindsContains(b *testing.B)  { benchmarkLineKinds(b, lineKindByContains) }
+func BenchmarkLineKindsSentinels(b *testing.B) { benchmarkLineKinds(b, lineKindBySentinels) }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Series a token's lines are parsed into, as
+	// "<token>=<series>|<series>,...", e.g. "t.abc=router|events.router".
+	// Lines for other series are skipped before being parsed, and parsing
+	// plugins' points for them dropped. Tokens without an allowlist get
+	// every series.
+	TokenSeriesAllowlists = parseTokenSeriesLists("TOKEN_SERIES_ALLOWLISTS", os.Getenv("TOKEN_SERIES_ALLOWLISTS"))
+
+	// Series skipped per token, in the same form
+	TokenSeriesDenylists = parseTokenSeriesLists("TOKEN_SERIES_DENYLISTS", os.Getenv("TOKEN_SERIES_DENYLISTS"))
+
+	seriesFilters = newSeriesFilters(TokenSeriesAllowlists, TokenSeriesDenylists)
+
+	skippedSeriesCounters = func() []metrics.Counter {
+		counters := make([]metrics.Counter, numSeries)
+		for st := SeriesType(0); st < numSeries; st++ {
+			counters[st] = metrics.GetOrRegisterCounter("lumbermill.lines.skipped."+st.Name(), metrics.DefaultRegistry)
+		}
+		return counters
+	}()
+)
+
+// Parses "<token>=<series>|<series>,..." into each token's set of series
+// names. Series that don't exist are logged and left out, and so are the
+// lists left empty, so a typo can't skip every series of a token.
+func parseTokenSeriesLists(name, list string) map[string]map[string]bool {
+	lists := make(map[string]map[string]bool)
+	for token, series := range parseKeyValueList(list) {
+		set := parseSet(strings.Replace(series, "|", ",", -1))
+		for s := range set {
+			if _, found := seriesTypesByName[s]; !found {
+				log.Printf("Unknown series in %s for %s (%q), ignoring it\n", name, token, s)
+				delete(set, s)
+			}
+		}
+		if len(set) > 0 {
+			lists[token] = set
+		}
+	}
+	return lists
+}
+
+// Whether each series type is skipped for a token. A nil filter skips
+// nothing.
+type seriesFilter []bool
+
+func newSeriesFilters(allowlists, denylists map[string]map[string]bool) map[string]seriesFilter {
+	filters := make(map[string]seriesFilter)
+	for token, allowed := range allowlists {
+		filter := make(seriesFilter, numSeries)
+		for st := SeriesType(0); st < numSeries; st++ {
+			filter[st] = !allowed[st.Name()]
+		}
+		filters[token] = filter
+	}
+	for token, denied := range denylists {
+		filter := filters[token]
+		if filter == nil {
+			filter = make(seriesFilter, numSeries)
+			filters[token] = filter
+		}
+		for st := SeriesType(0); st < numSeries; st++ {
+			filter[st] = filter[st] || denied[st.Name()]
+		}
+	}
+	return filters
+}
+
+// Whether all of the series types are skipped
+func (f seriesFilter) skips(types ...SeriesType) bool {
+	if f == nil {
+		return false
+	}
+	for _, st := range types {
+		if !f[st] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestNewSeriesFilters(t *testing.T) {
+	filters := newSeriesFilters(
+		map[string]map[string]bool{"t.a": {"router": true, "events.router": true}},
+		map[string]map[string]bool{"t.a": {"router": true}, "t.b": {"dyno.mem": true}},
+	)
+	if a := filters["t.a"]; a.skips(EventsRouter) || !a.skips(Router) || !a.skips(DynoMem) {
+		t.Errorf("Expected t.a to only get events.router, got %v", a)
+	}
+	if b := filters["t.b"]; b.skips(Router) || !b.skips(DynoMem) {
+		t.Errorf("Expected t.b to get everything but dyno.mem, got %v", b)
+	}
+	if filters["t.c"].skips(Router) {
+		t.Errorf("Expected tokens without lists to get every series")
+	}
+}
+
+func TestParseSkipsFilteredSeries(t *testing.T) {
+	defer func(filters map[string]seriesFilter) { seriesFilters = filters }(seriesFilters)
+	seriesFilters = newSeriesFilters(map[string]map[string]bool{corpusToken: {"router": true}}, nil)
+	lines := []string{
+		lumbermilltest.SyslogLine("heroku", "router", `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=3ms status=503 bytes=0`),
+		lumbermilltest.SyslogLine("heroku", "web.1", "Error R14 (Memory quota exceeded)"),
+		lumbermilltest.SyslogLine("heroku", "web.1", "source=web.1 sample#load_avg_1m=0.5 sample#load_avg_5m=0.4 sample#load_avg_15m=0.3"),
+	}
+
+	counts := &lineCounts{}
+	points := parseCorpusLinesCounting(lines, counts)
+	if len(points) != 1 || points[0].Type != Router {
+		t.Errorf("Expected only the router point, got %v", points)
+	}
+	if counts.skipped[EventsDyno] != 1 || counts.skipped[DynoLoad] != 1 || counts.skipped[Router] != 0 {
+		t.Errorf("Unexpected skipped lines: %v", counts.skipped)
+	}
+}
+
+func TestParseTokenSeriesLists(t *testing.T) {
+	lists := parseTokenSeriesLists("TOKEN_SERIES_ALLOWLISTS", "t.a=router|events.routr,t.b=routr")
+	if a := lists["t.a"]; len(a) != 1 || !a["router"] {
+		t.Errorf("Expected only the known series to be kept, got %v", a)
+	}
+	if _, found := lists["t.b"]; found {
+		t.Errorf("Expected a list of unknown series to be left out, got %v", lists["t.b"])
+	}
+	if filters := newSeriesFilters(lists, nil); filters["t.b"].skips(Router) {
+		t.Errorf("Expected a typo not to skip every series of t.b")
+	}
+}
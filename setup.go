@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Builds a server delivering to the InfluxDB hosts of hostlist (or the file,
+// console or BigQuery backend configured instead), with the drain routes,
+// tenants, limits, enrichment and aggregators the environment configures.
+// Serving requests and shutting it down are up to the caller, which waits
+// on the returned posters when shutting down. It's what main runs, for
+// tests and programs embedding lumbermill.
+func SetupLumbermill(httpServer *http.Server, hostlist string, skipVerify bool) (*LumbermillServer, *sync.WaitGroup, error) {
+	// Drain URLs handed out mustn't point wherever a request's Host says
+	if (AddonPassword != "" || SelfServiceDrains) && AddonDrainHost == "" {
+		return nil, nil, fmt.Errorf("ADDON_DRAIN_HOST must be set for add-ons and self-service drains")
+	}
+
+	// Made before the server, so posters' deliveries are canceled when
+	// shutting down runs out of time
+	shutdown := NewShutdown()
+	hashRing, destinations, posterGroup := createMessageRoutes(shutdown.Context(), hostlist, skipVerify)
+
+	server := NewLumbermillServer(httpServer, hashRing)
+	server.destinations = destinations
+	server.shutdown = shutdown
+	for path, hosts := range DrainRoutes {
+		if err := server.AddDrainRoute(path, newRouteRing(path, hosts, destinations)); err != nil {
+			return nil, nil, err
+		}
+	}
+	server.vhosts = newVhostTenants(DrainVhosts, DrainVhostUsers, DrainVhostSeriesPrefixes, destinations)
+	if mb := parseIntSetting("MEMORY_BUDGET_MB", MemoryBudgetMB, 0); mb > 0 {
+		server.memoryBudget = NewMemoryBudget(int64(mb)<<20, destinations)
+	}
+	if AddonPassword != "" || SelfServiceDrains {
+		addons, err := NewAddonStore(AddonResourcesPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to load add-on resources: %s", err)
+		}
+		server.addons = addons
+	}
+	if GeoIPDatabase != "" {
+		geoip, err := LoadGeoIP(GeoIPDatabase)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to load GeoIP database: %s", err)
+		}
+		switch GeoIPPrecision {
+		case "continent":
+			geoip.continentOnly = true
+		case "", "country":
+		default:
+			log.Printf("Unknown GeoIP precision (%q), using country\n", GeoIPPrecision)
+		}
+		geoip.tokens = GeoIPTokens
+		server.geoip = geoip
+	}
+	if len(LogPatternTokens) > 0 {
+		server.patterns = NewSeenPatterns(LogPatternTokens, LogPatternMax)
+	}
+	if CatalogURL != "" {
+		catalog = NewCatalog(CatalogURL, CatalogTTL, CatalogFetchers)
+	}
+	if FormationInterval > 0 {
+		server.formations = NewFormations()
+		go server.runFormations(FormationInterval)
+	}
+	if DynoErrorDedupWindow > 0 {
+		server.dedup = NewDynoErrorDedup(DynoErrorDedupWindow)
+		shutdown.runAggregator(func(stop <-chan struct{}) {
+			server.dedup.Run(server.derived, 10*time.Second, stop)
+		})
+	}
+	if AutoscaleSignals || Aggregates || ConcurrencyEstimates {
+		server.throughput = NewThroughput(AutoscaleWindow)
+		server.throughput.concurrency = ConcurrencyEstimates
+		webhookURL := ""
+		if AutoscaleSignals {
+			webhookURL = AutoscaleWebhookURL
+		}
+		shutdown.runAggregator(func(stop <-chan struct{}) {
+			server.throughput.Run(webhookURL, server.derived, stop)
+		})
+	}
+
+	if len(SLODefinitions) > 0 {
+		server.slos = NewSLOs(SLODefinitions, SLOWindow, SLOShortWindow, SLOInterval)
+		shutdown.runAggregator(func(stop <-chan struct{}) {
+			server.slos.Run(server.derived, SLOInterval, stop)
+		})
+	}
+
+	if (ReportWebhookURL != "" || ReportSMTPAddr != "") && len(ReportTokens) == 0 {
+		log.Println("REPORT_TOKENS is unset, not sending reports to REPORT_WEBHOOK_URL or REPORT_SMTP_ADDR")
+	}
+	if (ReportWebhookURL != "" || ReportSMTPAddr != "") && len(ReportTokens) > 0 || len(ReportTokenWebhookURLs) > 0 {
+		server.reports = NewReports(ReportTokens, ReportTokenWebhookURLs)
+		go server.reports.Run(ReportTime)
+	}
+
+	if BackpressureDetection {
+		server.backpressure = NewBackpressure()
+		shutdown.runAggregator(func(stop <-chan struct{}) {
+			server.backpressure.Run(server.derived, BackpressureWindow, stop)
+		})
+	}
+
+	if ProcessTypeAggregates {
+		server.processTypes = NewProcessTypes()
+		shutdown.runAggregator(func(stop <-chan struct{}) {
+			server.processTypes.Run(server.derived, ProcessTypeWindow, stop)
+		})
+	}
+
+	if CardinalityMonitoring {
+		server.cardinality = NewCardinality()
+		go server.cardinality.Run(cardinalityWindow)
+	}
+
+	if HeartbeatInterval > 0 {
+		go NewHeartbeats(HeartbeatVerify).Run(HeartbeatInterval)
+	}
+
+	return server, posterGroup, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// Where the server is in shutting down. Phases only move forward.
+type shutdownPhase int
+
+const (
+	phaseRunning  shutdownPhase = iota
+	phaseStopping               // Drain requests are refused from here on
+	phaseDraining               // Waiting for drain requests being parsed, then aggregators
+	phaseFlushing               // Destinations closed, posters delivering what's left
+	phaseClosed
+)
+
+var shutdownPhaseNames = []string{"running", "stopping", "draining", "flushing", "closed"}
+
+func (p shutdownPhase) String() string {
+	return shutdownPhaseNames[p]
+}
+
+var (
+	shutdownPhaseGauge = metrics.GetOrRegisterGauge("lumbermill.shutdown.phase", metrics.DefaultRegistry)
+
+	// How long shutting down may take before drain requests and deliveries
+	// still in flight are canceled. Unlimited when 0.
+	ShutdownTimeout = parseDurationSetting("SHUTDOWN_TIMEOUT", os.Getenv("SHUTDOWN_TIMEOUT"), 25*time.Second)
+)
+
+// Steps a server through shutting down: it stops accepting drain requests,
+// waits for those in flight, stops the aggregators posting points, closes
+// the destinations and waits for the posters to deliver what's left, canceling whatever's left after
+// ShutdownTimeout. Each phase is timed as lumbermill.shutdown.<phase>.time.
+type Shutdown struct {
+	sync.Mutex
+	phase   shutdownPhase
+	entered time.Time
+	begun   chan struct{}
+	once    sync.Once
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	aggregators     sync.WaitGroup
+	stopAggregators chan struct{}
+}
+
+func NewShutdown() *Shutdown {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Shutdown{begun: make(chan struct{}), ctx: ctx, cancel: cancel, stopAggregators: make(chan struct{})}
+}
+
+// Runs an aggregator posting points to the destinations, until shutting
+// down stops it before closing them
+func (s *Shutdown) runAggregator(run func(stop <-chan struct{})) {
+	s.aggregators.Add(1)
+	go func() {
+		defer s.aggregators.Done()
+		run(s.stopAggregators)
+	}()
+}
+
+// Done once shutting down runs out of time, or is over. Drain requests and
+// deliveries derive their contexts from it.
+func (s *Shutdown) Context() context.Context {
+	return s.ctx
+}
+
+// Starts shutting down. Only the first call does anything.
+func (s *Shutdown) Begin() {
+	s.once.Do(func() {
+		s.enter(phaseStopping)
+		close(s.begun)
+		if ShutdownTimeout > 0 {
+			time.AfterFunc(ShutdownTimeout, s.cancel)
+		}
+	})
+}
+
+func (s *Shutdown) Phase() shutdownPhase {
+	s.Lock()
+	defer s.Unlock()
+	return s.phase
+}
+
+// Runs f unless the server is shutting down, returning whether it did.
+// Shutting down waits for f, so it may count work in flight.
+func (s *Shutdown) unlessStopped(f func()) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.phase != phaseRunning {
+		return false
+	}
+	f()
+	return true
+}
+
+// Moves on to phase, timing the one it ends
+func (s *Shutdown) enter(phase shutdownPhase) {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	if s.phase != phaseRunning {
+		metrics.GetOrRegisterTimer("lumbermill.shutdown."+s.phase.String()+".time", metrics.DefaultRegistry).Update(now.Sub(s.entered))
+	}
+	s.phase, s.entered = phase, now
+	shutdownPhaseGauge.Update(int64(phase))
+	log.Printf("at=shutdown phase=%s\n", phase)
+}
+
+// Waits for the server to be closed, then shuts it down, returning once the
+// posters are done
+func (s *LumbermillServer) awaitShutdown(posters *sync.WaitGroup) {
+	<-s.shutdown.begun
+
+	s.shutdown.enter(phaseDraining)
+	s.Wait()
+	if s.ingest != nil {
+		s.ingest.Stop()
+	}
+	close(s.shutdown.stopAggregators)
+	s.shutdown.aggregators.Wait()
+
+	s.shutdown.enter(phaseFlushing)
+	for _, destination := range s.destinations {
+		destination.Close()
+	}
+	posters.Wait()
+
+	s.shutdown.enter(phaseClosed)
+	s.shutdown.cancel()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestShutdownPhases(t *testing.T) {
+	destination := NewDestination("shutdown-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.destinations = []*Destination{destination}
+	posters := new(sync.WaitGroup)
+	posters.Add(1)
+	go func() {
+		defer posters.Done()
+		NewNullPoster(destination).Run()
+	}()
+
+	health := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.http.Handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/health", nil))
+		return recorder
+	}
+	if recorder := health(); recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"running"`) {
+		t.Fatalf("Expected a healthy server, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		server.awaitShutdown(posters)
+		close(done)
+	}()
+	server.Close()
+	server.Close() // Closing twice is harmless
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected shutdown to complete")
+	}
+	if phase := server.shutdown.Phase(); phase != phaseClosed {
+		t.Errorf("Expected the server to be closed, got %s", phase)
+	}
+
+	if recorder := health(); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected an unhealthy server, got %d", recorder.Code)
+	}
+	recorder := httptest.NewRecorder()
+	server.serveDrain(recorder, lumbermilltest.NewDrainRequest("/drain", "t.a",
+		lumbermilltest.SyslogLine("t.a", "router", `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`)))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected drain requests to be refused, got %d", recorder.Code)
+	}
+	assertErrorCode(t, recorder, errShuttingDown)
+}
+
+func TestShutdownStopsAggregatorsFirst(t *testing.T) {
+	destination := NewDestination("shutdown-aggregator-test", 10)
+	hashRing := NewHashRing(1, nil)
+	hashRing.Add(destination)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.destinations = []*Destination{destination}
+	posters := new(sync.WaitGroup)
+	posters.Add(1)
+	go func() {
+		defer posters.Done()
+		NewNullPoster(destination).Run()
+	}()
+
+	// Its last points are posted while the destinations are still open
+	stopped := make(chan struct{})
+	server.shutdown.runAggregator(func(stop <-chan struct{}) {
+		<-stop
+		time.Sleep(10 * time.Millisecond)
+		destination.PostPoint(pointAt(1))
+		close(stopped)
+	})
+
+	server.Close()
+	server.awaitShutdown(posters)
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("Expected shutting down to wait for the aggregator")
+	}
+	if dropped := destination.droppedCounter.Count(); dropped != 0 {
+		t.Errorf("Expected the aggregator's last point to be delivered, %d dropped", dropped)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Service level objectives per token, as
+	// "<token>=<availability %>|<latency>,...", e.g. "t.abc=99.9|500ms".
+	// Requests are good when they didn't fail (5xx or an H code) and, with
+	// a latency threshold, were served within it. Off when unset.
+	SLODefinitions = parseSLODefinitions(parseKeyValueList(os.Getenv("SLO_DEFINITIONS")))
+
+	// The SLI is computed over SLO_WINDOW (1h) and posted every
+	// SLO_INTERVAL (1m) to the slo series, with burn rates over the window
+	// and the last SLO_SHORT_WINDOW (5m). A token alerts while both burn
+	// rates are at least SLO_BURN_RATE_ALERT (14.4, spending a 30 day budget
+	// in about 2 days).
+	SLOWindow        = parseDurationSetting("SLO_WINDOW", os.Getenv("SLO_WINDOW"), time.Hour)
+	SLOShortWindow   = parseDurationSetting("SLO_SHORT_WINDOW", os.Getenv("SLO_SHORT_WINDOW"), 5*time.Minute)
+	SLOInterval      = parseDurationSetting("SLO_INTERVAL", os.Getenv("SLO_INTERVAL"), time.Minute)
+	SLOBurnRateAlert = parseFloatSetting("SLO_BURN_RATE_ALERT", os.Getenv("SLO_BURN_RATE_ALERT"), 14.4)
+
+	sloAlertCounter  = metrics.GetOrRegisterCounter("lumbermill.slo.alerts", metrics.DefaultRegistry)
+	sloAlertingGauge = metrics.GetOrRegisterGauge("lumbermill.slo.alerting", metrics.DefaultRegistry)
+)
+
+// A token's objective
+type sloDefinition struct {
+	target  float64       // Share of good requests, 0 to 1
+	latency time.Duration // Slowest good request, any when 0
+}
+
+func parseSLODefinitions(list map[string]string) map[string]sloDefinition {
+	definitions := make(map[string]sloDefinition, len(list))
+	for token, definition := range list {
+		fields := strings.SplitN(definition, "|", 2)
+		target := parseFloatSetting("SLO target of "+token, fields[0], 0)
+		if target <= 0 || target >= 100 {
+			log.Printf("Invalid SLO target of %s (%q), ignoring it\n", token, fields[0])
+			continue
+		}
+		d := sloDefinition{target: target / 100}
+		if len(fields) > 1 {
+			d.latency = parseDurationSetting("SLO latency of "+token, fields[1], 0)
+		}
+		definitions[token] = d
+	}
+	return definitions
+}
+
+// Good and total requests of an interval
+type sloBucket struct {
+	good, total int64
+}
+
+type tokenSLO struct {
+	definition sloDefinition
+	buckets    []sloBucket // Oldest first, the last being the current interval
+	alerting   bool
+}
+
+// Rolling SLIs and burn rates of the tokens with an objective
+type SLOs struct {
+	sync.Mutex
+	tokens       map[string]*tokenSLO
+	buckets      int // Intervals in the window
+	shortBuckets int // Intervals in the short window
+}
+
+func NewSLOs(definitions map[string]sloDefinition, window, shortWindow, interval time.Duration) *SLOs {
+	s := &SLOs{tokens: make(map[string]*tokenSLO, len(definitions))}
+	s.buckets = int(window / interval)
+	if s.buckets < 1 {
+		s.buckets = 1
+	}
+	s.shortBuckets = int(shortWindow / interval)
+	if s.shortBuckets < 1 {
+		s.shortBuckets = 1
+	}
+	for token, definition := range definitions {
+		s.tokens[token] = &tokenSLO{definition: definition, buckets: []sloBucket{{}}}
+	}
+	return s
+}
+
+// Records the router points of a batch. A nil SLOs records nothing.
+func (s *SLOs) Record(points []Point) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	for _, point := range points {
+		ts := s.tokens[point.Token]
+		if ts == nil {
+			continue
+		}
+		bucket := &ts.buckets[len(ts.buckets)-1]
+		switch point.Type {
+		case Router:
+			bucket.total++
+			if ts.definition.good(point) {
+				bucket.good++
+			}
+		case EventsRouter:
+			bucket.total++
+		}
+	}
+}
+
+// Whether a router point was a good request
+func (d sloDefinition) good(point Point) bool {
+	if status, ok := point.Points[1].(int); ok && status >= 500 {
+		return false
+	}
+	if d.latency > 0 {
+		service, ok := routerDurationMicros(point.Points[2])
+		return ok && time.Duration(service)*time.Microsecond <= d.latency
+	}
+	return true
+}
+
+// Share of good requests in buckets, and how fast they spend the error
+// budget, 1 spending it exactly over the objective's period
+func (d sloDefinition) rates(buckets []sloBucket) (sli, burnRate float64, total int64) {
+	var good int64
+	for _, b := range buckets {
+		good += b.good
+		total += b.total
+	}
+	if total == 0 {
+		return 1, 0, 0
+	}
+	sli = float64(good) / float64(total)
+	return sli, (1 - sli) / (1 - d.target), total
+}
+
+// Ends the current interval, returning an slo point for each token with
+// requests in the window, and alerting on those burning their budget
+func (s *SLOs) Roll(now time.Time) []Point {
+	s.Lock()
+	defer s.Unlock()
+
+	timestamp := now.UnixNano() / int64(time.Microsecond)
+	var points []Point
+	alerting := 0
+	for token, ts := range s.tokens {
+		sli, burnRate, total := ts.definition.rates(ts.buckets)
+		short := ts.buckets
+		if len(short) > s.shortBuckets {
+			short = short[len(short)-s.shortBuckets:]
+		}
+		_, shortBurnRate, _ := ts.definition.rates(short)
+
+		alert := burnRate >= SLOBurnRateAlert && shortBurnRate >= SLOBurnRateAlert
+		if alert && !ts.alerting {
+			sloAlertCounter.Inc(1)
+			log.Printf("at=slo_alert token=%s sli=%.5f target=%g burn_rate=%.2f short_burn_rate=%.2f\n", token, sli, ts.definition.target, burnRate, shortBurnRate)
+		} else if !alert && ts.alerting {
+			log.Printf("at=slo_recovered token=%s sli=%.5f burn_rate=%.2f short_burn_rate=%.2f\n", token, sli, burnRate, shortBurnRate)
+		}
+		ts.alerting = alert
+		if alert {
+			alerting++
+		}
+
+		if total > 0 {
+			points = append(points, Point{Token: token, Type: SLO, Points: []interface{}{timestamp, sli, ts.definition.target, burnRate, shortBurnRate, alert}})
+		}
+
+		ts.buckets = append(ts.buckets, sloBucket{})
+		if len(ts.buckets) > s.buckets {
+			ts.buckets = ts.buckets[len(ts.buckets)-s.buckets:]
+		}
+	}
+	sloAlertingGauge.Update(int64(alerting))
+	return points
+}
+
+// Rolls the interval every so often, posting the slo points, until stop is
+// closed
+func (s *SLOs) Run(routes *derivedRoutes, every time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		for _, point := range s.Roll(time.Now()) {
+			routes.Post(point)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseSLODefinitions(t *testing.T) {
+	definitions := parseSLODefinitions(map[string]string{"t.a": "99.9|500ms", "t.b": "99", "t.c": "100", "t.d": "fast"})
+	if len(definitions) != 2 {
+		t.Fatalf("Expected 2 valid definitions, got %v", definitions)
+	}
+	if d := definitions["t.a"]; math.Abs(d.target-0.999) > 1e-9 || d.latency != 500*time.Millisecond {
+		t.Errorf("Unexpected definition of t.a: %+v", d)
+	}
+	if d := definitions["t.b"]; math.Abs(d.target-0.99) > 1e-9 || d.latency != 0 {
+		t.Errorf("Unexpected definition of t.b: %+v", d)
+	}
+}
+
+func TestSLOBurnRates(t *testing.T) {
+	defer func(unit string) { RouterDurationUnit = unit }(RouterDurationUnit)
+	RouterDurationUnit = "ms"
+	slos := NewSLOs(map[string]sloDefinition{"t.a": {target: 0.9, latency: 100 * time.Millisecond}}, 4*time.Minute, 2*time.Minute, time.Minute)
+	request := func(status, service int) Point {
+		return Point{Token: "t.a", Type: Router, Points: []interface{}{int64(1), status, service, 1}}
+	}
+	now := time.Unix(1500000000, 0)
+
+	// 10% bad, spending the budget exactly
+	var points []Point
+	for i := 0; i < 9; i++ {
+		points = append(points, request(200, 50))
+	}
+	points = append(points, request(200, 150), request(200, 50), Point{Token: "t.b", Type: Router, Points: []interface{}{int64(1), 500, 50, 1}})
+	slos.Record(points[:10])
+	slo := slos.Roll(now)
+	if len(slo) != 1 || slo[0].Type != SLO || slo[0].Points[1] != 0.9 || slo[0].Points[5] != false {
+		t.Fatalf("Expected an SLI of 0.9 without alerting, got %v", slo)
+	}
+
+	// All bad over the short window, half over the long one
+	for i := 0; i < 2; i++ {
+		slos.Record([]Point{request(503, 10), request(200, 500), {Token: "t.a", Type: EventsRouter, Points: []interface{}{int64(1), "H12"}}})
+		slo = slos.Roll(now)
+	}
+	alerts := sloAlertCounter.Count()
+	defer func(alert float64) { SLOBurnRateAlert = alert }(SLOBurnRateAlert)
+	SLOBurnRateAlert = 4
+	slos.Record([]Point{request(503, 10)})
+	slo = slos.Roll(now)
+	if burn, short := slo[0].Points[3].(float64), slo[0].Points[4].(float64); short < 9.99 || short > 10.01 || burn < 4 || burn >= 10 || slo[0].Points[5] != true {
+		t.Errorf("Expected to alert on burn rates of >4 and 10, got %v", slo[0].Points)
+	}
+	if n := sloAlertCounter.Count() - alerts; n != 1 {
+		t.Errorf("Expected 1 alert, got %d", n)
+	}
+
+	// The window forgets the first interval
+	for i := 0; i < 4; i++ {
+		slo = slos.Roll(now)
+	}
+	if len(slo) != 0 {
+		t.Errorf("Expected no points for an idle window, got %v", slo)
+	}
+	(*SLOs)(nil).Record(points)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Number of tokens listed by volume on the status page
+const statusTopTokens = 10
+
+// What the status page shows. Counters are totals; the page turns them into
+// rates between refreshes.
+type pipelineStatus struct {
+	Lines        int64                `json:"lines"`
+	Batches      int64                `json:"batches"`
+	ParseErrors  map[string]int64     `json:"parse_errors"`
+	Destinations []destinationStatus  `json:"destinations"`
+	TopTokens    []ThroughputSignal   `json:"top_tokens"` // Empty unless AGGREGATES or AUTOSCALE_SIGNALS are on
+	Maintenance  bool                 `json:"maintenance"`
+	Paused       map[string]PauseMode `json:"paused"`
+}
+
+type destinationStatus struct {
+	Name        string  `json:"name"`
+	Pending     int     `json:"pending"`
+	DrainRate   float64 `json:"drain_rate"`
+	Dropped     int64   `json:"dropped"`
+	Maintenance bool    `json:"maintenance"`
+}
+
+func (s *LumbermillServer) pipelineStatus() pipelineStatus {
+	status := pipelineStatus{
+		Lines:   linesCounter.Count(),
+		Batches: batchCounter.Count(),
+		ParseErrors: map[string]int64{
+			"time":   timeParsingErrorCounter.Count(),
+			"logfmt": logfmtParsingErrorCounter.Count(),
+			"panic":  parsePanicCounter.Count(),
+		},
+		Destinations: make([]destinationStatus, 0, len(s.destinations)),
+		TopTokens:    make([]ThroughputSignal, 0),
+		Maintenance:  Maintenance.On(),
+		Paused:       s.paused.List(),
+	}
+
+	for _, d := range s.destinations {
+		status.Destinations = append(status.Destinations, destinationStatus{
+			Name:        d.Name,
+			Pending:     d.depth(),
+			DrainRate:   d.DrainRate(),
+			Dropped:     d.droppedCounter.Count(),
+			Maintenance: d.Maintenance.On(),
+		})
+	}
+
+	if s.throughput != nil {
+		signals := s.throughput.Signals()
+		sort.Slice(signals, func(i, j int) bool { return signals[i].Requests > signals[j].Requests })
+		if len(signals) > statusTopTokens {
+			signals = signals[:statusTopTokens]
+		}
+		status.TopTokens = signals
+	}
+
+	return status
+}
+
+// GET /admin/status for the status page, or /admin/status?format=json for
+// what it shows
+func (s *LumbermillServer) serveAdminStatus(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.adminActor(w, r, ReadOnly); !ok {
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, s.pipelineStatus())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(statusPage))
+}
+
+// Polls the JSON status every 5 seconds. Kept free of external assets so it
+// works wherever lumbermill runs.
+const statusPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>lumbermill status</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #333; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 1em; text-align: right; border-bottom: 1px solid #ddd; }
+th:first-child, td:first-child { text-align: left; }
+.warn { color: #c00; }
+</style>
+</head>
+<body>
+<h1>lumbermill</h1>
+<p id="maintenance" class="warn"></p>
+<h2>Pipeline</h2>
+<table>
+<tr><th></th><th>total</th><th>per second</th></tr>
+<tbody id="pipeline"></tbody>
+</table>
+<h2>Destinations</h2>
+<table>
+<tr><th>destination</th><th>backlog</th><th>delivered/s</th><th>dropped</th></tr>
+<tbody id="destinations"></tbody>
+</table>
+<h2>Top tokens</h2>
+<table>
+<tr><th>token</th><th>rps</th><th>p95 ms</th><th>error rate</th><th>dynos</th></tr>
+<tbody id="tokens"></tbody>
+</table>
+<script>
+var previous = null, previousAt = null;
+
+function rows(id, values) {
+  var body = document.getElementById(id);
+  body.innerHTML = "";
+  values.forEach(function(cells) {
+    var tr = document.createElement("tr");
+    cells.forEach(function(cell) {
+      var td = document.createElement("td");
+      td.textContent = cell;
+      tr.appendChild(td);
+    });
+    body.appendChild(tr);
+  });
+}
+
+function refresh() {
+  fetch("?format=json", {credentials: "same-origin"}).then(function(r) { return r.json(); }).then(function(s) {
+    var now = Date.now();
+    function rate(current, last) {
+      return previous ? ((current - last) * 1000 / (now - previousAt)).toFixed(1) : "";
+    }
+    var pipeline = [["lines", s.lines, rate(s.lines, previous && previous.lines)],
+                    ["batches", s.batches, rate(s.batches, previous && previous.batches)]];
+    Object.keys(s.parse_errors).forEach(function(kind) {
+      pipeline.push([kind + " parse errors", s.parse_errors[kind], rate(s.parse_errors[kind], previous && previous.parse_errors[kind])]);
+    });
+    rows("pipeline", pipeline);
+    rows("destinations", s.destinations.map(function(d) {
+      return [d.name + (d.maintenance ? " (maintenance)" : ""), d.pending, d.drain_rate.toFixed(1), d.dropped];
+    }));
+    rows("tokens", s.top_tokens.map(function(t) {
+      return [t.token + (s.paused[t.token] ? " (paused)" : ""), t.rps.toFixed(1), t.p95_service_ms.toFixed(0), (100 * t.error_rate).toFixed(1) + "%", t.dynos];
+    }));
+    document.getElementById("maintenance").textContent = s.maintenance ? "In maintenance: drain requests get a 503" : "";
+    previous = s;
+    previousAt = now;
+  });
+}
+
+refresh();
+setInterval(refresh, 5000);
+</script>
+</body>
+</html>
+`
@@ -9,14 +9,14 @@ import (
 // GET /target/<opaque id>
 func (s *LumbermillServer) serveTarget(w http.ResponseWriter, r *http.Request) {
 	if err := s.checkAuth(r); err != nil {
-		w.WriteHeader(http.StatusForbidden)
+		writeError(w, r, http.StatusForbidden, errAuthFailed, err.Error())
 		authFailureCounter.Inc(1)
 		return
 	}
 
 	parts := strings.SplitN(r.URL.Path, "/", 3)
 	if len(parts) != 3 || parts[2] == "" {
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, errBadRequest, "Missing target id")
 		badRequestCounter.Inc(1)
 		return
 	}
@@ -26,7 +26,7 @@ func (s *LumbermillServer) serveTarget(w http.ResponseWriter, r *http.Request) {
 	destination := s.hashRing.Get(id)
 
 	if destination == nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, errInternal, "No destinations available")
 		internalServerErrorCounter.Inc(1)
 		return
 	}
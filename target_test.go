@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -21,6 +23,21 @@ func TestTargetWithoutAuth(t *testing.T) {
 	if recorder.Code != http.StatusForbidden {
 		t.Fatal("Wrong Response Code: ", recorder.Code)
 	}
+
+	assertErrorCode(t, recorder, errAuthFailed)
+}
+
+func assertErrorCode(t *testing.T, recorder *httptest.ResponseRecorder, code string) {
+	var body errorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal("Error response isn't JSON: ", err)
+	}
+	if body.Code != code {
+		t.Fatalf("Expected error code %q, got %q", code, body.Code)
+	}
+	if body.RequestId == "" || body.RequestId != recorder.Header().Get(requestIdHeader) {
+		t.Fatalf("Expected request id %q in body, got %q", recorder.Header().Get(requestIdHeader), body.RequestId)
+	}
 }
 
 func TestTargetWithoutId(t *testing.T) {
@@ -41,6 +58,8 @@ func TestTargetWithoutId(t *testing.T) {
 	if recorder.Code != http.StatusBadRequest {
 		t.Fatal("Wrong Response Code: ", recorder.Code)
 	}
+
+	assertErrorCode(t, recorder, errBadRequest)
 }
 
 func TestTargetWithoutRing(t *testing.T) {
@@ -62,6 +81,8 @@ func TestTargetWithoutRing(t *testing.T) {
 	if recorder.Code != http.StatusInternalServerError {
 		t.Fatal("Wrong Response Code: ", recorder.Code)
 	}
+
+	assertErrorCode(t, recorder, errInternal)
 }
 
 func TestTarget(t *testing.T) {
@@ -69,7 +90,7 @@ func TestTarget(t *testing.T) {
 	User = "foo"
 	Password = "foo"
 
-	hashRing, _, _ := createMessageRoutes("null", true)
+	hashRing, _, _ := createMessageRoutes(context.Background(), "null", true)
 	server := NewLumbermillServer(&http.Server{}, hashRing)
 
 	recorder := httptest.NewRecorder()
@@ -91,3 +112,23 @@ func TestTarget(t *testing.T) {
 		t.Fatal("Wrong Body: ", body)
 	}
 }
+
+func TestTargetRefusesDrainUsers(t *testing.T) {
+	defer func(user, password, users string) { User, Password, DrainUsers = user, password, users }(User, Password, DrainUsers)
+	User, Password, DrainUsers = "foo", "foo", "tenant=secret"
+	server := NewLumbermillServer(&http.Server{}, nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/target/foo", bytes.NewReader([]byte("")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth("tenant", "secret")
+
+	server.serveTarget(recorder, req)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatal("Expected a drain user to be refused, got: ", recorder.Code)
+	}
+	assertErrorCode(t, recorder, errAuthFailed)
+}
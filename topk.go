@@ -0,0 +1,210 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	// Number of tokens tracked by line volume and parse errors, 0 for none,
+	// and how often they're reported as lumbermill.top.{lines,errors}.<token>
+	// gauges
+	TopTokensCount    = parseIntSetting("TOP_TOKENS", os.Getenv("TOP_TOKENS"), 10)
+	TopTokensInterval = parseDurationSetting("TOP_TOKENS_INTERVAL", os.Getenv("TOP_TOKENS_INTERVAL"), time.Minute)
+
+	topTokens = NewTopTokens(TopTokensCount)
+)
+
+// Entries kept by a sketch per token reported, for accuracy
+const topTokensSketchFactor = 10
+
+// A token's tally in a sketch. Count overestimates it by at most Error.
+type TopEntry struct {
+	Token string `json:"token"`
+	Count int64  `json:"count"`
+	Error int64  `json:"error"`
+}
+
+// Space-Saving sketch of the heaviest keys, in bounded memory. When full,
+// a new key replaces the lightest one, inheriting its count as error.
+type SpaceSaving struct {
+	capacity int
+	entries  map[string]*TopEntry
+}
+
+// A sketch of at least one key
+func NewSpaceSaving(capacity int) *SpaceSaving {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &SpaceSaving{capacity: capacity, entries: make(map[string]*TopEntry, capacity)}
+}
+
+func (s *SpaceSaving) Offer(key string, n int64) {
+	if entry, found := s.entries[key]; found {
+		entry.Count += n
+		return
+	}
+	if len(s.entries) < s.capacity {
+		s.entries[key] = &TopEntry{Token: key, Count: n}
+		return
+	}
+
+	var min *TopEntry
+	for _, entry := range s.entries {
+		if min == nil || entry.Count < min.Count {
+			min = entry
+		}
+	}
+	delete(s.entries, min.Token)
+	s.entries[key] = &TopEntry{Token: key, Count: min.Count + n, Error: min.Count}
+}
+
+// The n heaviest keys, heaviest first
+func (s *SpaceSaving) Top(n int) []TopEntry {
+	top := make([]TopEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		top = append(top, *entry)
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].Count > top[j].Count })
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// Lines and parse errors of a run of consecutive lines for a token
+type tokenRun struct {
+	token  string
+	lines  int64
+	errors int64
+}
+
+// The tokens with the most lines and parse errors per interval, to find
+// noisy apps without keeping counters for every token
+type TopTokens struct {
+	sync.Mutex
+	n      int
+	lines  *SpaceSaving
+	errors *SpaceSaving
+	last   map[string][]TopEntry // Top tokens of the last interval, by "lines" or "errors"
+	gauges map[string]metrics.Gauge
+}
+
+// Top tokens tracking n of them, or nil when n is 0
+func NewTopTokens(n int) *TopTokens {
+	if n < 0 {
+		log.Printf("TOP_TOKENS can't be negative, using 10 instead of %d\n", n)
+		n = 10
+	}
+	if n == 0 {
+		return nil
+	}
+	t := &TopTokens{n: n, last: map[string][]TopEntry{"lines": {}, "errors": {}}, gauges: make(map[string]metrics.Gauge)}
+	t.reset()
+	return t
+}
+
+func (t *TopTokens) reset() {
+	t.lines = NewSpaceSaving(t.n * topTokensSketchFactor)
+	t.errors = NewSpaceSaving(t.n * topTokensSketchFactor)
+}
+
+// Tallies the runs of a batch
+func (t *TopTokens) Record(runs []tokenRun) {
+	if t == nil || len(runs) == 0 {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	for _, run := range runs {
+		t.lines.Offer(run.token, run.lines)
+		if run.errors > 0 {
+			t.errors.Offer(run.token, run.errors)
+		}
+	}
+}
+
+// Ends the interval, publishing its top tokens
+func (t *TopTokens) Roll() {
+	t.Lock()
+	defer t.Unlock()
+	t.last = map[string][]TopEntry{"lines": t.lines.Top(t.n), "errors": t.errors.Top(t.n)}
+	t.reset()
+
+	// Gauges of tokens that dropped out of the top are unregistered, so
+	// there are never more than 2n of them
+	current := make(map[string]bool)
+	for by, top := range t.last {
+		summary := make([]string, 0, len(top))
+		for _, entry := range top {
+			name := "lumbermill.top." + by + "." + entry.Token
+			current[name] = true
+			gauge, found := t.gauges[name]
+			if !found {
+				gauge = metrics.GetOrRegisterGauge(name, metrics.DefaultRegistry)
+				t.gauges[name] = gauge
+			}
+			gauge.Update(entry.Count)
+			summary = append(summary, entry.Token+":"+strconv.FormatInt(entry.Count, 10))
+		}
+		if len(summary) > 0 {
+			log.Printf("at=top_tokens by=%s tokens=%s\n", by, strings.Join(summary, ","))
+		}
+	}
+	for name := range t.gauges {
+		if !current[name] {
+			metrics.DefaultRegistry.Unregister(name)
+			delete(t.gauges, name)
+		}
+	}
+}
+
+// The top tokens of the last interval, by "lines" or "errors"
+func (t *TopTokens) Last(by string) ([]TopEntry, bool) {
+	t.Lock()
+	defer t.Unlock()
+	top, found := t.last[by]
+	return top, found
+}
+
+// Rolls the interval every so often
+func (t *TopTokens) Run(every time.Duration) {
+	if t == nil {
+		return
+	}
+	for {
+		time.Sleep(every)
+		t.Roll()
+	}
+}
+
+// GET /admin/top?by=lines|errors, the tokens with the most lines or parse
+// errors over the last interval
+func (s *LumbermillServer) serveAdminTop(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.adminActor(w, r, ReadOnly); !ok {
+		return
+	}
+	if topTokens == nil {
+		writeError(w, r, http.StatusNotFound, errNotFound, "Top tokens are disabled")
+		return
+	}
+	by := r.URL.Query().Get("by")
+	if by == "" {
+		by = "lines"
+	}
+	top, found := topTokens.Last(by)
+	if !found {
+		writeError(w, r, http.StatusBadRequest, errBadRequest, "by must be lines or errors")
+		return
+	}
+	writeJSON(w, top)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+func TestSpaceSaving(t *testing.T) {
+	sketch := NewSpaceSaving(10)
+	for i := 0; i < 1000; i++ {
+		sketch.Offer("t.noisy", 5)
+		sketch.Offer("t.busy", 2)
+		sketch.Offer(fmt.Sprintf("t.quiet%d", i), 1)
+	}
+
+	top := sketch.Top(2)
+	if len(top) != 2 || top[0].Token != "t.noisy" || top[1].Token != "t.busy" {
+		t.Fatalf("Expected the heavy hitters on top, got %+v", top)
+	}
+	if top[0].Count != 5000 || top[0].Error != 0 {
+		t.Errorf("Expected t.noisy's exact count, got %+v", top[0])
+	}
+	if len(sketch.entries) != 10 {
+		t.Errorf("Expected the sketch to stay bounded, got %d entries", len(sketch.entries))
+	}
+}
+
+func TestTopTokensRoll(t *testing.T) {
+	top := NewTopTokens(1)
+	top.Record([]tokenRun{{"t.a", 10, 0}, {"t.b", 3, 2}, {"t.a", 5, 0}})
+	top.Roll()
+
+	if lines, _ := top.Last("lines"); len(lines) != 1 || lines[0].Token != "t.a" || lines[0].Count != 15 {
+		t.Errorf("Expected t.a to top lines, got %+v", lines)
+	}
+	if errors, _ := top.Last("errors"); len(errors) != 1 || errors[0].Token != "t.b" {
+		t.Errorf("Expected t.b to top errors, got %+v", errors)
+	}
+	if metrics.DefaultRegistry.Get("lumbermill.top.lines.t.a") == nil {
+		t.Errorf("Expected a gauge for t.a")
+	}
+
+	top.Record([]tokenRun{{"t.c", 1, 0}})
+	top.Roll()
+	if metrics.DefaultRegistry.Get("lumbermill.top.lines.t.a") != nil {
+		t.Errorf("Expected t.a's gauge to go once it dropped out")
+	}
+	if _, found := top.Last("volume"); found {
+		t.Errorf("Expected only lines and errors to be tracked")
+	}
+}
+
+func TestLineCountsRuns(t *testing.T) {
+	counts := lineCounts{}
+	counts.line("t.a")
+	counts.line("t.a")
+	counts.parseError()
+	counts.line("t.b")
+	counts.line("t.a")
+
+	if len(counts.runs) != 3 || counts.runs[0] != (tokenRun{"t.a", 2, 1}) || counts.runs[2] != (tokenRun{"t.a", 1, 0}) {
+		t.Errorf("Unexpected runs: %+v", counts.runs)
+	}
+}
+
+func TestTopTokensCapacity(t *testing.T) {
+	if top := NewTopTokens(0); top != nil {
+		t.Errorf("Expected no top tokens when tracking none")
+	}
+	var disabled *TopTokens
+	disabled.Record([]tokenRun{{"t.a", 1, 0}})
+
+	if top := NewTopTokens(-1); top == nil || top.n != 10 {
+		t.Errorf("Expected a negative count to fall back to the default")
+	}
+
+	sketch := NewSpaceSaving(0)
+	sketch.Offer("t.a", 1)
+	sketch.Offer("t.b", 2)
+	if top := sketch.Top(1); len(top) != 1 || top[0].Token != "t.b" {
+		t.Errorf("Expected an empty sketch to keep one key, got %+v", top)
+	}
+}
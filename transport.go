@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net"
+	"os"
+	"strconv"
+
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// Writes series to a destination's backend. A writer is shared by all the
+// posters of a destination.
+type SeriesWriter interface {
+	Write(series []*influx.Series) error
+	// Like Write, giving up when ctx is done
+	WriteContext(ctx context.Context, series []*influx.Series) error
+}
+
+// How a destination's points are sent to it
+type Transport string
+
+const (
+	TransportHTTP   Transport = "http"   // The InfluxDB 0.8 HTTP API
+	TransportUDP    Transport = "udp"    // An InfluxDB 0.8 UDP listener
+	TransportStatsd Transport = "statsd" // Gauges for a statsd server
+	TransportGRPC   Transport = "grpc"   // A gRPC sink (proto/sink.proto)
+)
+
+var (
+	// Largest datagram sent by the UDP transports
+	UDPMaxPayload = parseIntSetting("UDP_MAX_PAYLOAD", os.Getenv("UDP_MAX_PAYLOAD"), 1400)
+
+	// The transports besides HTTP, by name, creating the writer of the
+	// destination name at addr
+	transports = map[Transport]func(addr, name string) (SeriesWriter, error){
+		TransportUDP: func(addr, name string) (SeriesWriter, error) {
+			return newUDPWriter(addr, name, UDPMaxPayload, influxUDPDatagrams)
+		},
+		TransportStatsd: func(addr, name string) (SeriesWriter, error) {
+			return newUDPWriter(addr, name, UDPMaxPayload, statsdDatagrams)
+		},
+		TransportGRPC: func(addr, name string) (SeriesWriter, error) {
+			return newGRPCWriter(addr, name)
+		},
+	}
+)
+
+// Transport of a destination, TransportHTTP unless it's configured
+func transportFor(transport, name string) Transport {
+	if _, ok := transports[Transport(transport)]; ok {
+		return Transport(transport)
+	}
+	switch Transport(transport) {
+	case TransportHTTP, "":
+	default:
+		log.Printf("Unknown transport (%q) for %s, using %s\n", transport, name, TransportHTTP)
+	}
+	return TransportHTTP
+}
+
+// Writes series to a UDP listener as datagrams of at most maxPayload
+// bytes. Nothing's acknowledged, so a write only fails when the datagrams
+// can't be sent.
+type udpWriter struct {
+	conn       net.Conn
+	maxPayload int
+	datagrams  func(series []*influx.Series, maxPayload int) [][]byte
+	sent       metrics.Counter
+}
+
+func newUDPWriter(addr, name string, maxPayload int, datagrams func([]*influx.Series, int) [][]byte) (*udpWriter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &udpWriter{
+		conn:       conn,
+		maxPayload: maxPayload,
+		datagrams:  datagrams,
+		sent:       metrics.GetOrRegisterCounter("lumbermill.poster.udp.datagrams."+name, metrics.DefaultRegistry),
+	}, nil
+}
+
+func (w *udpWriter) Write(series []*influx.Series) error {
+	return w.WriteContext(context.Background(), series)
+}
+
+func (w *udpWriter) WriteContext(ctx context.Context, series []*influx.Series) error {
+	for _, datagram := range w.datagrams(series, w.maxPayload) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := w.conn.Write(datagram); err != nil {
+			return err
+		}
+		w.sent.Inc(1)
+	}
+	return nil
+}
+
+// The JSON an InfluxDB UDP listener expects, split like large deliveries
+// over HTTP. Sizes are estimated, so a datagram may run a little over.
+func influxUDPDatagrams(series []*influx.Series, maxPayload int) [][]byte {
+	byName := make(map[string]*influx.Series, len(series))
+	for _, s := range series {
+		byName[s.Name] = s
+	}
+	var datagrams [][]byte
+	for _, chunk := range splitWrites(byName, nil, 0, maxPayload) {
+		var buf bytes.Buffer
+		if err := writeSeriesJSON(&buf, chunk); err != nil {
+			continue
+		}
+		datagrams = append(datagrams, buf.Bytes())
+	}
+	return datagrams
+}
+
+// A "<series>.<column>:<value>|g" gauge per numeric value, packed a line
+// at a time into datagrams. Statsd has no timestamps, so the time column
+// is left out.
+func statsdDatagrams(series []*influx.Series, maxPayload int) [][]byte {
+	var datagrams [][]byte
+	var buf bytes.Buffer
+	for _, s := range series {
+		for _, point := range s.Points {
+			for i, v := range point {
+				if i >= len(s.Columns) || s.Columns[i] == "time" {
+					continue
+				}
+				value, ok := statsdValue(v)
+				if !ok {
+					continue
+				}
+				line := s.Name + "." + s.Columns[i] + ":" + value + "|g\n"
+				if buf.Len() > 0 && buf.Len()+len(line) > maxPayload {
+					datagrams = append(datagrams, append([]byte(nil), buf.Bytes()...))
+					buf.Reset()
+				}
+				buf.WriteString(line)
+			}
+		}
+	}
+	if buf.Len() > 0 {
+		datagrams = append(datagrams, buf.Bytes())
+	}
+	return datagrams
+}
+
+func statsdValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	influx "github.com/influxdb/influxdb-go"
+)
+
+func listenUDP(t *testing.T) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %s", err)
+	}
+	return conn
+}
+
+// Reads datagrams until none arrive for a while
+func readDatagrams(conn net.PacketConn) []string {
+	var datagrams []string
+	buf := make([]byte, 65536)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return datagrams
+		}
+		datagrams = append(datagrams, string(buf[:n]))
+	}
+}
+
+func TestUDPWriterSplitsDatagrams(t *testing.T) {
+	conn := listenUDP(t)
+	defer conn.Close()
+	writer, err := newUDPWriter(conn.LocalAddr().String(), "udp-test", 200, influxUDPDatagrams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	series := &influx.Series{Name: "dyno.mem", Columns: []string{"time", "rss", "source"}}
+	for i := 0; i < 20; i++ {
+		series.Points = append(series.Points, []interface{}{int64(1400000000000000 + i), 12.5, "web.1"})
+	}
+	if err := writer.Write([]*influx.Series{series}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	datagrams := readDatagrams(conn)
+	if len(datagrams) < 2 {
+		t.Fatalf("Expected the points to be split over datagrams, got %d", len(datagrams))
+	}
+	points := 0
+	for _, datagram := range datagrams {
+		var decoded []struct {
+			Name   string
+			Points [][]interface{}
+		}
+		if err := json.Unmarshal([]byte(datagram), &decoded); err != nil {
+			t.Fatalf("Expected a JSON datagram, got %q: %s", datagram, err)
+		}
+		for _, s := range decoded {
+			points += len(s.Points)
+		}
+	}
+	if points != 20 {
+		t.Errorf("Expected 20 points, got %d", points)
+	}
+	if writer.sent.Count() != int64(len(datagrams)) {
+		t.Errorf("Expected %d datagrams counted, got %d", len(datagrams), writer.sent.Count())
+	}
+}
+
+func TestStatsdDatagrams(t *testing.T) {
+	series := []*influx.Series{{
+		Name:    "router",
+		Columns: []string{"time", "status", "service", "protocol"},
+		Points: [][]interface{}{
+			{int64(1400000000000000), 200, 15.5, "https"},
+			{int64(1400000000000001), 503, 30001.0, "http"},
+		},
+	}}
+
+	datagrams := statsdDatagrams(series, 1400)
+	if len(datagrams) != 1 {
+		t.Fatalf("Expected 1 datagram, got %d", len(datagrams))
+	}
+	expected := "router.status:200|g\nrouter.service:15.5|g\nrouter.status:503|g\nrouter.service:30001|g\n"
+	if string(datagrams[0]) != expected {
+		t.Errorf("Expected %q, got %q", expected, datagrams[0])
+	}
+
+	for _, datagram := range statsdDatagrams(series, 40) {
+		if len(datagram) > 40 || !strings.HasSuffix(string(datagram), "|g\n") {
+			t.Errorf("Expected whole lines under 40 bytes, got %q", datagram)
+		}
+	}
+}
+
+func TestTransportFor(t *testing.T) {
+	for transport, expected := range map[string]Transport{
+		"":       TransportHTTP,
+		"http":   TransportHTTP,
+		"udp":    TransportUDP,
+		"statsd": TransportStatsd,
+		"grpc":   TransportGRPC,
+		"tcp":    TransportHTTP,
+	} {
+		if got := transportFor(transport, "transport-test"); got != expected {
+			t.Errorf("Expected %q to be %s, got %s", transport, expected, got)
+		}
+	}
+}
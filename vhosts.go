@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var (
+	// Tenants told apart by the Host drains post to, as "<vhost>=<value>,...":
+	// the InfluxDB hosts their points go to ("<host>|<host>"), the users
+	// that may drain through them ("<user>|<user>"), and a prefix for their
+	// series names
+	DrainVhosts              = parseKeyValueList(os.Getenv("DRAIN_VHOSTS"))
+	DrainVhostUsers          = parseAllowedTokens(os.Getenv("DRAIN_VHOST_USERS"))
+	DrainVhostSeriesPrefixes = parseKeyValueList(os.Getenv("DRAIN_VHOST_SERIES_PREFIXES"))
+)
+
+// A tenant drains reach through a virtual host
+type vhostTenant struct {
+	ring   *HashRing       // nil when the tenant's points go where any others would
+	users  map[string]bool // Users that may drain through the vhost, anyone when nil
+	prefix string
+}
+
+// The tenant of each vhost configured, with rings of the destinations
+func newVhostTenants(hosts map[string]string, users map[string]map[string]bool, prefixes map[string]string, destinations []*Destination) map[string]*vhostTenant {
+	tenants := make(map[string]*vhostTenant)
+	tenant := func(vhost string) *vhostTenant {
+		vhost = strings.ToLower(vhost)
+		if tenants[vhost] == nil {
+			tenants[vhost] = &vhostTenant{}
+		}
+		return tenants[vhost]
+	}
+	for vhost, list := range hosts {
+		tenant(vhost).ring = newRouteRing(vhost, list, destinations)
+	}
+	for vhost, allowed := range users {
+		tenant(vhost).users = allowed
+	}
+	for vhost, prefix := range prefixes {
+		tenant(vhost).prefix = prefix
+	}
+	return tenants
+}
+
+// The tenant r was sent to, nil unless its Host is a configured vhost
+func (s *LumbermillServer) vhostTenant(r *http.Request) *vhostTenant {
+	if len(s.vhosts) == 0 {
+		return nil
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return s.vhosts[strings.ToLower(host)]
+}
+
+// Whether principal may drain through the tenant's vhost
+func (t *vhostTenant) allows(principal string) bool {
+	return t.users == nil || principal != "" && t.users[principal]
+}
+
+// The prefix of the tenant's series, none without a tenant
+func (t *vhostTenant) seriesPrefix() string {
+	if t == nil {
+		return ""
+	}
+	return t.prefix
+}
+
+// Prefixes the series names of the batch's points
+func (b *pointBatch) PrefixSeries(prefix string) {
+	if prefix == "" {
+		return
+	}
+	for i := range b.points {
+		b.points[i].Prefix = prefix
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+)
+
+func TestVhostTenants(t *testing.T) {
+	prod, staging := NewDestination("prod-influx", 10), NewDestination("staging-influx", 10)
+	hashRing := NewHashRing(HashRingReplication, nil)
+	hashRing.Add(prod)
+	server := NewLumbermillServer(&http.Server{}, hashRing)
+	server.vhosts = newVhostTenants(
+		map[string]string{"drain-staging.example.com": "staging-influx"},
+		map[string]map[string]bool{"drain-staging.example.com": {"staging-user": true}},
+		map[string]string{"Drain-Staging.example.com": "staging."},
+		[]*Destination{prod, staging})
+
+	defer func(users string) { DrainUsers = users }(DrainUsers)
+	DrainUsers = "staging-user=secret,prod-user=secret"
+
+	router := `at=info method=GET path="/" host=a.herokuapp.com dyno=web.1 connect=1ms service=2ms status=200 bytes=3`
+	send := func(host, user string) int {
+		req := lumbermilltest.NewDrainRequest("/drain", "t.vhost", lumbermilltest.SyslogLine("heroku", "router", router))
+		req.Host = host
+		if user != "" {
+			req.SetBasicAuth(user, "secret")
+		}
+		recorder := httptest.NewRecorder()
+		server.serveDrain(recorder, req)
+		return recorder.Code
+	}
+
+	if code := send("drain-prod.example.com", ""); code != http.StatusNoContent || len(prod.points) != 1 {
+		t.Fatalf("Expected other hosts to drain as usual, got %d", code)
+	}
+	if point := <-prod.points; strings.HasPrefix(point.SeriesName(), "staging.") {
+		t.Errorf("Expected other hosts' series not to be prefixed, got %s", point.SeriesName())
+	}
+
+	if code := send("drain-staging.example.com:443", "staging-user"); code != http.StatusNoContent || len(staging.points) != 1 {
+		t.Fatalf("Expected the vhost's points to go to its destination, got %d", code)
+	}
+	if point := <-staging.points; point.SeriesName() != "staging.router.t.vhost" {
+		t.Errorf("Expected the vhost's series to be prefixed, got %s", point.SeriesName())
+	}
+	server.derived.Post(Point{Token: "t.vhost", Type: EventsRouterStatus, Points: []interface{}{int64(1), 200, "web.1", "/", "web", nil}})
+	if len(staging.points) != 1 {
+		t.Fatalf("Expected the token's derived points to go to the vhost's destination")
+	}
+	if point := <-staging.points; point.SeriesName() != "staging.events.router.status.t.vhost" {
+		t.Errorf("Expected the token's derived series to be prefixed, got %s", point.SeriesName())
+	}
+
+	for _, user := range []string{"", "prod-user"} {
+		if code := send("drain-staging.example.com", user); code != http.StatusForbidden {
+			t.Errorf("Expected %q to be refused by the vhost, got %d", user, code)
+		}
+	}
+}
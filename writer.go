@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	influx "github.com/influxdb/influxdb-go"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// What counts as a successful write
+type AckLevel string
+
+const (
+	AckStatus AckLevel = "status" // A 2xx
+	AckBody   AckLevel = "body"   // A 2xx without errors in the body
+	AckNone   AckLevel = "none"   // Any response at all
+)
+
+// Ack level of a destination, AckStatus unless it's configured
+func ackLevel(level, name string) AckLevel {
+	switch AckLevel(level) {
+	case AckStatus, AckBody, AckNone:
+		return AckLevel(level)
+	case "":
+	default:
+		log.Printf("Unknown ack level (%q) for %s, using %s\n", level, name, AckStatus)
+	}
+	return AckStatus
+}
+
+// Payload encodings we can compress with, by Content-Encoding name
+var payloadEncoders = map[string]func(io.Writer) io.WriteCloser{
+	"gzip":   newPooledGzipWriter,
+	"snappy": newPooledSnappyWriter,
+	"zstd":   newPooledZstdWriter,
+}
+
+// Writes series to an InfluxDB 0.8 HTTP API. A writer is shared by all the
+// posters of a destination.
+type seriesWriter struct {
+	config   influx.ClientConfig
+	encoding atomic.Value // Content-Encoding of payloads, "" when uncompressed
+	ack      AckLevel
+
+	compressionRatio metrics.Histogram // Compressed size as a % of the original
+	compressionTime  metrics.Timer
+	connsReused      metrics.Counter
+	connsNew         metrics.Counter
+	unacked          metrics.Counter // Failed writes ignored as AckNone
+	trace            *httptrace.ClientTrace
+
+	endpoints    []string // Hosts posted to, when there are several
+	endpointMode EndpointMode
+	nextEndpoint uint32
+	failovers    metrics.Counter // Writes posted again to another endpoint
+	races        metrics.Counter // Writes raced against another endpoint
+
+	limits *writeLimits // nil unless writes are rate limited
+}
+
+func newSeriesWriter(config influx.ClientConfig, name string, encoding string) *seriesWriter {
+	if config.HttpClient == nil {
+		config.HttpClient = http.DefaultClient
+	}
+
+	w := &seriesWriter{
+		config:           config,
+		compressionRatio: metrics.GetOrRegisterHistogram("lumbermill.poster.compression.ratio."+name, metrics.DefaultRegistry, metrics.NewUniformSample(100)),
+		compressionTime:  metrics.GetOrRegisterTimer("lumbermill.poster.compression.time."+name, metrics.DefaultRegistry),
+		connsReused:      metrics.GetOrRegisterCounter("lumbermill.poster.conns.reused."+name, metrics.DefaultRegistry),
+		connsNew:         metrics.GetOrRegisterCounter("lumbermill.poster.conns.new."+name, metrics.DefaultRegistry),
+		unacked:          metrics.GetOrRegisterCounter("lumbermill.poster.unacked.errors."+name, metrics.DefaultRegistry),
+		ack:              AckStatus,
+	}
+
+	// Tracks whether deliveries reuse pooled connections
+	w.trace = &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				w.connsReused.Inc(1)
+			} else {
+				w.connsNew.Inc(1)
+			}
+		},
+	}
+
+	if _, supported := payloadEncoders[encoding]; encoding != "" && !supported {
+		log.Printf("Unsupported compression (%q) for %s, sending uncompressed\n", encoding, name)
+		encoding = ""
+	}
+	w.encoding.Store(encoding)
+
+	return w
+}
+
+func (w *seriesWriter) url(host string) string {
+	scheme := "http"
+	if w.config.IsSecure {
+		scheme = "https"
+	}
+
+	query := url.Values{}
+	query.Set("u", secrets.Get("INFLUXDB_USER", w.config.Username))
+	query.Set("p", secrets.Get("INFLUXDB_PWD", w.config.Password))
+	query.Set("time_precision", string(influx.Microsecond))
+
+	return fmt.Sprintf("%s://%s/db/%s/series?%s", scheme, host, w.config.Database, query.Encode())
+}
+
+// Writes series as the JSON InfluxDB expects a point at a time, so a
+// delivery is never held in memory as a whole
+func writeSeriesJSON(w io.Writer, series []*influx.Series) error {
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+	enc := json.NewEncoder(scratch)
+	// Encode ends values with a newline, which isn't sent
+	encode := func(prefix string, v interface{}) error {
+		scratch.Reset()
+		scratch.WriteString(prefix)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		_, err := w.Write(scratch.Bytes()[:scratch.Len()-1])
+		return err
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, s := range series {
+		prefix := `{"name":`
+		if i > 0 {
+			prefix = `,{"name":`
+		}
+		if err := encode(prefix, s.Name); err != nil {
+			return err
+		}
+		if err := encode(`,"columns":`, s.Columns); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, `,"points":[`); err != nil {
+			return err
+		}
+		for j, point := range s.Points {
+			prefix = ""
+			if j > 0 {
+				prefix = ","
+			}
+			if err := encode(prefix, point); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, "]}"); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// Counts the bytes written through it, and the time spent writing them
+type countingWriter struct {
+	io.Writer
+	n       int64
+	elapsed time.Duration
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	start := time.Now()
+	n, err := c.Writer.Write(p)
+	c.elapsed += time.Since(start)
+	c.n += int64(n)
+	return n, err
+}
+
+// A request body the series are serialized (and compressed with encoding)
+// into while the transport reads it
+func (w *seriesWriter) stream(series []*influx.Series, encoding string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		bw := getBufioWriter(pw)
+		defer putBufioWriter(bw)
+		compressed := &countingWriter{Writer: bw}
+		raw := &countingWriter{Writer: compressed}
+
+		var enc io.WriteCloser
+		if encoding != "" {
+			enc = payloadEncoders[encoding](compressed)
+			raw.Writer = enc
+		}
+		err := writeSeriesJSON(raw, series)
+		if enc != nil {
+			start := time.Now()
+			if closeErr := enc.Close(); err == nil {
+				err = closeErr
+			}
+			// Time in the encoder, less what it spent waiting on the
+			// connection, as payloads are compressed as they're sent
+			w.compressionTime.Update(raw.elapsed + time.Since(start) - compressed.elapsed)
+			if err == nil && raw.n > 0 {
+				w.compressionRatio.Update(compressed.n * 100 / raw.n)
+			}
+		}
+		if err == nil {
+			err = bw.Flush()
+		}
+		// Unblocks the transport, or tells it the body is broken. When the
+		// transport gave up on the body first, writes fail and end this.
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+func (w *seriesWriter) Write(series []*influx.Series) error {
+	return w.WriteContext(context.Background(), series)
+}
+
+// Like Write, giving up (waiting for the rate limits too) when ctx is done
+func (w *seriesWriter) WriteContext(ctx context.Context, series []*influx.Series) error {
+	if w.limits != nil {
+		if err := w.limits.wait(ctx, series); err != nil {
+			return err
+		}
+	}
+	encoding := w.encoding.Load().(string)
+	status, err := w.send(ctx, series, encoding)
+	if err == nil && status == http.StatusUnsupportedMediaType && encoding != "" {
+		// The backend doesn't accept this encoding, so stop using it
+		log.Printf("%s rejected %s payloads, sending uncompressed\n", w.config.Host, encoding)
+		w.encoding.Store("")
+		_, err = w.send(ctx, series, "")
+	}
+
+	return err
+}
+
+// Posts series to the destination's endpoint, or one of them
+func (w *seriesWriter) send(ctx context.Context, series []*influx.Series, encoding string) (int, error) {
+	if len(w.endpoints) < 2 {
+		return w.post(ctx, w.config.Host, series, encoding)
+	}
+	if w.endpointMode == EndpointsRace {
+		return w.postRace(ctx, series, encoding)
+	}
+	return w.postRoundRobin(ctx, series, encoding)
+}
+
+func (w *seriesWriter) post(ctx context.Context, host string, series []*influx.Series, encoding string) (int, error) {
+	req, err := http.NewRequest("POST", w.url(host), nil)
+	if err != nil {
+		return 0, err
+	}
+	// The length isn't known up front, so payloads are sent chunked
+	req.Body = w.stream(series, encoding)
+	req.ContentLength = -1
+	req.GetBody = func() (io.ReadCloser, error) { return w.stream(series, encoding), nil }
+	req = req.WithContext(httptrace.WithClientTrace(ctx, w.trace))
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+
+	resp, err := w.config.HttpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnsupportedMediaType && encoding != "" {
+		return resp.StatusCode, nil
+	}
+
+	ok := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if w.ack == AckNone || ok && w.ack == AckStatus {
+		io.Copy(ioutil.Discard, resp.Body)
+		if !ok {
+			w.unacked.Inc(1)
+		}
+		return resp.StatusCode, nil
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if !ok {
+		return resp.StatusCode, newBackendError(resp.StatusCode, respBody)
+	}
+	if bodyErr := responseBodyError(respBody); bodyErr != "" {
+		return resp.StatusCode, fmt.Errorf("Server returned (%d) with an error: %s", resp.StatusCode, bodyErr)
+	}
+	return resp.StatusCode, nil
+}
+
+// The error reported in a successful response's body, if any. InfluxDB 0.8
+// answers writes with an empty body; other backends may answer 200 with
+// {"error": ...}, or {"results": [{"error": ...}]} like InfluxDB 0.9+.
+func responseBodyError(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	var response struct {
+		Error   string `json:"error"`
+		Results []struct {
+			Error string `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "unexpected body: " + string(body)
+	}
+	if response.Error != "" {
+		return response.Error
+	}
+	for _, result := range response.Results {
+		if result.Error != "" {
+			return result.Error
+		}
+	}
+	return ""
+}
+
+var (
+	// Kinds of errors backends refuse writes with, by what their message
+	// contains
+	writeErrorKinds = []struct{ kind, contains string }{
+		{"partial_write", "partial write"},
+		{"field_type_conflict", "field type conflict"},
+		{"retention", "beyond retention policy"},
+		{"parse", "unable to parse"},
+		{"database_not_found", "database not found"},
+		{"limit", "limit exceeded"},
+	}
+
+	// The series an error is about, e.g. on measurement "router.t.a"
+	writeErrorSeries = regexp.MustCompile(`(?:measurement|series) "([^"]+)"`)
+)
+
+// A write the backend refused
+type backendError struct {
+	status  int
+	message string
+	kind    string // One of writeErrorKinds, "other" or "server" for a 5xx
+	series  string // The series at fault, when the message says
+}
+
+func newBackendError(status int, body []byte) *backendError {
+	e := &backendError{status: status, message: string(body), kind: "other"}
+	// InfluxDB 0.9+ answers with {"error": ...}
+	var response struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &response) == nil && response.Error != "" {
+		e.message = response.Error
+	}
+
+	if status >= 500 {
+		e.kind = "server"
+	} else {
+		message := strings.ToLower(e.message)
+		for _, k := range writeErrorKinds {
+			if strings.Contains(message, k.contains) {
+				e.kind = k.kind
+				break
+			}
+		}
+	}
+	if match := writeErrorSeries.FindStringSubmatch(e.message); match != nil {
+		e.series = match[1]
+	}
+
+	metrics.GetOrRegisterCounter("lumbermill.poster.write.errors."+e.kind, metrics.DefaultRegistry).Inc(1)
+	return e
+}
+
+func (e *backendError) Error() string {
+	return fmt.Sprintf("Server returned (%d): %s", e.status, e.message)
+}
+
+// Whether the backend refused the points themselves, so writing them again
+// won't help
+func (e *backendError) permanent() bool {
+	return e.status >= 400 && e.status < 500 && e.status != http.StatusTooManyRequests && e.status != http.StatusRequestTimeout && !e.auth()
+}
+
+// Whether the backend refused the credentials or doesn't know the database
+// (yet), which rotating secrets or bootstrapping the host fix
+func (e *backendError) auth() bool {
+	return e.status == http.StatusUnauthorized || e.status == http.StatusForbidden || e.status == http.StatusNotFound
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/heroku/lumbermill/lumbermilltest"
+	influx "github.com/influxdb/influxdb-go"
+)
+
+func newTestWriter(db *lumbermilltest.FakeInfluxDB, compression string) *seriesWriter {
+	config := influx.ClientConfig{Host: db.Host(), Database: "test"}
+	return newSeriesWriter(config, "writer-test", compression)
+}
+
+func encodings(db *lumbermilltest.FakeInfluxDB) []string {
+	encodings := make([]string, 0)
+	for _, w := range db.Writes() {
+		encodings = append(encodings, w.Encoding)
+	}
+	return encodings
+}
+
+func testSeries() []*influx.Series {
+	return []*influx.Series{
+		{Name: "router.t.a", Columns: seriesColumns[Router], Points: [][]interface{}{{1, 200, 10}}},
+	}
+}
+
+func TestSeriesWriterCompression(t *testing.T) {
+	for _, encoding := range []string{"gzip", "snappy", "zstd"} {
+		db := lumbermilltest.NewFakeInfluxDB()
+		writer := newTestWriter(db, encoding)
+
+		// Twice, so pooled encoders are reused
+		for i := 0; i < 2; i++ {
+			if err := writer.Write(testSeries()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		writes := db.Writes()
+		if len(writes) != 2 || writes[0].Encoding != encoding || writes[1].Encoding != encoding {
+			t.Fatalf("Expected two %s payloads, got %v", encoding, encodings(db))
+		}
+		if writes[0].Database != "test" || writes[0].Query.Get("time_precision") != "u" {
+			t.Errorf("Wrong database or time precision: %s, %v", writes[0].Database, writes[0].Query)
+		}
+		if len(db.Points("router.t.a")) != 2 {
+			t.Errorf("%s: Expected a point for router.t.a per write", encoding)
+		}
+		db.Close()
+	}
+}
+
+func TestSeriesWriterCompressionFallback(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	db.RejectEncoding("gzip")
+	writer := newTestWriter(db, "gzip")
+
+	for i := 0; i < 2; i++ {
+		if err := writer.Write(testSeries()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if e := encodings(db); len(e) != 2 || e[0] != "" || e[1] != "" {
+		t.Errorf("Expected uncompressed payloads after a 415, got %v", e)
+	}
+}
+
+func TestSeriesWriterUnsupportedCompression(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "brotli")
+
+	if err := writer.Write(testSeries()); err != nil {
+		t.Fatal(err)
+	}
+	if e := encodings(db); e[0] != "" {
+		t.Errorf("Expected unsupported compression to be disabled, got %q", e[0])
+	}
+}
+
+func TestSeriesWriterReusesConnections(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "")
+
+	reusedBefore, newBefore := writer.connsReused.Count(), writer.connsNew.Count()
+	for i := 0; i < 3; i++ {
+		if err := writer.Write(testSeries()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := writer.connsNew.Count() - newBefore; n != 1 {
+		t.Errorf("Expected 1 new connection, got %d", n)
+	}
+	if n := writer.connsReused.Count() - reusedBefore; n != 2 {
+		t.Errorf("Expected 2 reused connections, got %d", n)
+	}
+}
+
+func TestSeriesWriterAckLevels(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "")
+
+	db.Respond(lumbermilltest.Response{Status: 200, Body: `{"error":"field type conflict"}`})
+	if err := writer.Write(testSeries()); err != nil {
+		t.Errorf("Expected a 2xx to be enough by default, got %s", err)
+	}
+
+	writer.ack = AckBody
+	db.Respond(
+		lumbermilltest.Response{Status: 200, Body: `{"error":"field type conflict"}`},
+		lumbermilltest.Response{Status: 200, Body: `{"results":[{"error":"partial write"}]}`},
+		lumbermilltest.Response{Status: 204},
+	)
+	for i, want := range []bool{false, false, true} {
+		if err := writer.Write(testSeries()); (err == nil) != want {
+			t.Errorf("Write %d: expected success to be %t, got %v", i, want, err)
+		}
+	}
+
+	writer.ack = AckNone
+	unacked := writer.unacked.Count()
+	db.Respond(lumbermilltest.Response{Status: 500, Body: "oops"})
+	if err := writer.Write(testSeries()); err != nil || writer.unacked.Count()-unacked != 1 {
+		t.Errorf("Expected a 500 to be ignored and counted, got %v", err)
+	}
+}
+
+func TestSeriesWriterReusesBuffers(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "gzip")
+
+	// Payloads written one after the other from pooled buffers mustn't
+	// bleed into each other
+	for i := 0; i < 20; i++ {
+		series := []*influx.Series{{Name: fmt.Sprintf("router.t.%d", i), Columns: seriesColumns[Router], Points: [][]interface{}{{i, 200, 10}}}}
+		if err := writer.Write(series); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		if points := db.Points(fmt.Sprintf("router.t.%d", i)); len(points) != 1 {
+			t.Errorf("Expected a point for router.t.%d, got %v", i, points)
+		}
+	}
+}
+
+func TestCompressionTimeExcludesConnection(t *testing.T) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "gzip")
+	series := &influx.Series{Name: "router.t.a", Columns: seriesColumns[Router]}
+	for i := 0; i < 5000; i++ {
+		series.Points = append(series.Points, []interface{}{int64(i), 200, rand.Int63(), rand.Int63(), "web", nil, nil, nil})
+	}
+
+	// A connection slow to take the payload, which outgrows the buffers
+	body := writer.stream([]*influx.Series{series}, "gzip")
+	time.Sleep(200 * time.Millisecond)
+	if _, err := io.Copy(ioutil.Discard, body); err != nil {
+		t.Fatal(err)
+	}
+	if max := writer.compressionTime.Max(); max <= 0 || time.Duration(max) >= 200*time.Millisecond {
+		t.Errorf("Expected the time waiting on the connection not to count as compression, got %s", time.Duration(max))
+	}
+}
+
+func BenchmarkSeriesWriterWrite(b *testing.B) {
+	db := lumbermilltest.NewFakeInfluxDB()
+	defer db.Close()
+	writer := newTestWriter(db, "gzip")
+	series := &influx.Series{Name: "router.t.a", Columns: seriesColumns[Router]}
+	for i := 0; i < 1000; i++ {
+		series.Points = append(series.Points, []interface{}{int64(i), 200, 10, 1, "web", nil, nil, nil})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		writer.Write([]*influx.Series{series})
+	}
+}
+
+func TestWriteSeriesJSON(t *testing.T) {
+	series := []*influx.Series{
+		{Name: "router.t.a", Columns: seriesColumns[Router], Points: [][]interface{}{{int64(1), 200, 10, 1, "web", nil, "<h2>", 1.5}, {int64(2), 500, 20, 2, "web", "TLSv1.3", nil, nil}}},
+		{Name: "events.router.t.a", Columns: seriesColumns[EventsRouter], Points: [][]interface{}{}},
+	}
+	expected, _ := json.Marshal(series)
+
+	var streamed bytes.Buffer
+	if err := writeSeriesJSON(&streamed, series); err != nil {
+		t.Fatal(err)
+	}
+	if streamed.String() != string(expected) {
+		t.Errorf("Expected %s, got %s", expected, streamed.String())
+	}
+}